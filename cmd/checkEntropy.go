@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spark8899/gowallet/internal/entropy"
+	"github.com/spark8899/gowallet/internal/hdwallet"
+	"github.com/spark8899/gowallet/internal/security"
+	"github.com/spf13/cobra"
+)
+
+var entropyAlpha float64
+
+var checkEntropyCmd = &cobra.Command{
+	Use:   "checkEntropy [entropy_hex]",
+	Short: "Run statistical health tests on 16-32 bytes of entropy",
+	Long: "Run repetition count, adaptive proportion, frequency, runs, nibble chi-square and repeating\n" +
+		"pattern tests on 16-32 bytes of entropy, or on the entropy behind a mnemonic (-m).\n" +
+		"Each test fails when its exact p-value is below --alpha, so random entropy fails\n" +
+		"at most 6*alpha of the time. Exits with status 1 if any test fails.",
+	Example: `  gowallet checkEntropy 9e885d952ad362caeb4efe34a8e91bd2
+  gowallet checkEntropy -m "abandon abandon ... about"
+  gowallet checkEntropy --alpha 0.001 aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			if args[0] == "help" {
+				cmd.Help()
+				os.Exit(0)
+			}
+			entropyHex = args[0]
+		}
+		if entropyHex == "" && mnemonicStr == "" {
+			fmt.Println("Error: Entropy is required. Provide it as an argument, or use -e or -m flag")
+			os.Exit(1)
+		}
+
+		var sample []byte
+		var err error
+		if mnemonicStr != "" {
+			mnemonic := secretArg(mnemonicStr)
+			defer mnemonic.Destroy()
+			var decoded *security.SecretBuffer
+			if decoded, err = hdwallet.MnemonicToEntropy(mnemonic); err == nil {
+				defer decoded.Destroy()
+				sample = decoded.Bytes()
+			}
+		} else {
+			sample, _, err = hdwallet.HexEntropy(entropyHex)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer security.ZeroBytes(sample)
+
+		report, err := entropy.Check(sample, entropyAlpha)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(report)
+		if !report.Passed() {
+			security.ZeroBytes(sample)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	checkEntropyCmd.Flags().StringVarP(&entropyHex, "entropy", "e", "", "entropy hex, 16-32 bytes")
+	checkEntropyCmd.Flags().StringVarP(&mnemonicStr, "mnemonic", "m", "", "check the entropy of a BIP39 mnemonic")
+	checkEntropyCmd.Flags().Float64Var(&entropyAlpha, "alpha", entropy.DefaultAlpha, "per-test significance level")
+}
@@ -1,10 +1,19 @@
 package cmd
 
 import (
+	"encoding/hex"
+	"encoding/json"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 // TestCLIBuild tests that the binary builds successfully
@@ -257,3 +266,810 @@ func TestInputValidation(t *testing.T) {
 		})
 	}
 }
+
+// TestNetworkFlag tests the global --network flag with getPath
+func TestNetworkFlag(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+	cmd := exec.Command("go", "run", "../main.go", "--network", "testnet3", "getPath", "-m", mnemonic, "-p", "m/44'/1'/0'/0/0", "-v")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("getPath command failed: %v\nOutput: %s", err, output)
+	}
+
+	outputStr := string(output)
+	for _, want := range []string{"testnet3", "mkpZhYtJu2r87Js3pDiWJDmPte2NRZ8bJV", "tprv", "tpub"} {
+		if !strings.Contains(outputStr, want) {
+			t.Errorf("Expected output to contain '%s', got: %s", want, outputStr)
+		}
+	}
+
+	cmd = exec.Command("go", "run", "../main.go", "--network", "ropsten", "getPath", "-m", mnemonic, "-p", "m/44'/60'/0'/0/0")
+	if output, err := cmd.CombinedOutput(); err == nil {
+		t.Errorf("Expected error for unknown network, got success. Output: %s", output)
+	}
+}
+
+// TestVanityCommand tests the vanity command with an easy pattern
+func TestVanityCommand(t *testing.T) {
+	cmd := exec.Command("go", "run", "../main.go", "vanity", "--prefix", "a", "--workers", "2")
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("vanity command failed: %v\nOutput: %s", err, output)
+	}
+
+	parts := strings.Split(strings.TrimSpace(string(output)), ":")
+	if len(parts) != 2 || !strings.HasPrefix(strings.ToLower(parts[0]), "0xa") {
+		t.Errorf("Unexpected vanity output: %s", output)
+	}
+
+	cmd = exec.Command("go", "run", "../main.go", "vanity", "--prefix", "xyz")
+	if output, err := cmd.CombinedOutput(); err == nil {
+		t.Errorf("Expected error for non-hex prefix, got success. Output: %s", output)
+	}
+}
+
+// TestContractAddressCommand tests CREATE and CREATE2 address prediction
+func TestContractAddressCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "CREATE",
+			args: []string{"contractAddress", "-d", "0x6ac7ea33f8831ea9dcc53393aaa88b25a785dbf0", "-n", "1"},
+			want: "0x343c43A37D37dfF08AE8C4A11544c718AbB4fCF8",
+		},
+		{
+			name: "CREATE2",
+			args: []string{"contractAddress", "-d", "0xdeadbeef00000000000000000000000000000000", "--salt", "0x00", "--init-code", "0x00"},
+			want: "0xB928f69Bb1D91Cd65274e3c79d8986362984fDA3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"run", "../main.go"}, tt.args...)
+			cmd := exec.Command("go", args...)
+			output, err := cmd.CombinedOutput()
+			if err != nil {
+				t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+			}
+			if got := strings.TrimSpace(string(output)); got != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestDeterministicSeedFlag tests that --deterministic-seed reproduces output
+func TestDeterministicSeedFlag(t *testing.T) {
+	run := func(args ...string) string {
+		args = append([]string{"run", "../main.go", "--deterministic-seed", "fixture"}, args...)
+		output, err := exec.Command("go", args...).Output()
+		if err != nil {
+			t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+		}
+		return string(output)
+	}
+
+	if run("genPrivateKey", "3") != run("genPrivateKey", "3") {
+		t.Error("genPrivateKey output differs between runs with the same seed")
+	}
+	if run("genMnemonic", "24") != run("genMnemonic", "24") {
+		t.Error("genMnemonic output differs between runs with the same seed")
+	}
+}
+
+// TestGenMnemonicUserEntropy tests the --dice, --coin and --entropy-hex modes
+func TestGenMnemonicUserEntropy(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "BIP39 vector from hex",
+			args: []string{"--entropy-hex", "9e885d952ad362caeb4efe34a8e91bd2"},
+			want: "ozone drill grab fiber curtain grace pudding thank cruise elder eight picnic",
+		},
+		{
+			name:    "too few dice rolls",
+			args:    []string{"24", "--dice", strings.Repeat("123456", 9)},
+			wantErr: true,
+		},
+		{
+			name:    "mutually exclusive sources",
+			args:    []string{"--dice", "123", "--coin", "HT"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"run", "../main.go", "genMnemonic"}, tt.args...)
+			if tt.wantErr {
+				// Flag errors are printed by cobra without a non-zero exit
+				output, err := exec.Command("go", args...).CombinedOutput()
+				if err == nil && !strings.Contains(string(output), "Error:") {
+					t.Errorf("Expected error, got output %s", output)
+				}
+				return
+			}
+			output, err := exec.Command("go", args...).Output()
+			if err != nil {
+				t.Fatalf("Command failed: %v", err)
+			}
+			if got := strings.TrimSpace(string(output)); got != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestEntropyMnemonicCommands tests entropyToMnemonic and mnemonicToEntropy round trip
+func TestEntropyMnemonicCommands(t *testing.T) {
+	entropy := "9e885d952ad362caeb4efe34a8e91bd2"
+	mnemonic := "ozone drill grab fiber curtain grace pudding thank cruise elder eight picnic"
+
+	output, err := exec.Command("go", "run", "../main.go", "entropyToMnemonic", entropy).Output()
+	if err != nil {
+		t.Fatalf("entropyToMnemonic failed: %v", err)
+	}
+	if got := strings.TrimSpace(string(output)); got != mnemonic {
+		t.Errorf("Expected %s, got %s", mnemonic, got)
+	}
+
+	output, err = exec.Command("go", "run", "../main.go", "mnemonicToEntropy", "-m", mnemonic).Output()
+	if err != nil {
+		t.Fatalf("mnemonicToEntropy failed: %v", err)
+	}
+	if got := strings.TrimSpace(string(output)); got != entropy {
+		t.Errorf("Expected %s, got %s", entropy, got)
+	}
+
+	// Bad checksum and the lossy seed path must fail
+	badChecksum := strings.Repeat("abandon ", 12)
+	if output, err := exec.Command("go", "run", "../main.go", "mnemonicToEntropy", badChecksum).CombinedOutput(); err == nil {
+		t.Errorf("Expected checksum error, got %s", output)
+	}
+	if output, err := exec.Command("go", "run", "../main.go", "seedToMn", "00112233445566778899aabbccddeeff").CombinedOutput(); err == nil {
+		t.Errorf("Expected seedToMn to fail, got %s", output)
+	}
+}
+
+// TestCheckEntropyCommand tests that patterned entropy fails and random entropy passes
+func TestCheckEntropyCommand(t *testing.T) {
+	output, err := exec.Command("go", "run", "../main.go", "checkEntropy", "9e885d952ad362caeb4efe34a8e91bd2").Output()
+	if err != nil {
+		t.Fatalf("checkEntropy failed on random entropy: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(string(output), "false positive rate") {
+		t.Errorf("Expected report, got %s", output)
+	}
+
+	output, err = exec.Command("go", "run", "../main.go", "checkEntropy", strings.Repeat("aa", 16)).Output()
+	if err == nil {
+		t.Errorf("Expected alternating bits to fail, got %s", output)
+	}
+	if !strings.Contains(string(output), "FAIL") {
+		t.Errorf("Expected FAIL in report, got %s", output)
+	}
+}
+
+// TestWeakKeyWarnings tests that known test keys and mnemonics warn on stderr
+func TestWeakKeyWarnings(t *testing.T) {
+	cmd := exec.Command("go", "run", "../main.go", "getAddress", "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("getAddress failed: %v", err)
+	}
+	if got := strings.TrimSpace(string(output)); got != "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266" {
+		t.Errorf("Expected Hardhat account 0 address, got %s", got)
+	}
+	if !strings.Contains(stderr.String(), "Hardhat/Anvil default account #0") {
+		t.Errorf("Expected test key warning, got %q", stderr.String())
+	}
+
+	cmd = exec.Command("go", "run", "../main.go", "getPath", "-m", "test test test test test test test test test test test junk", "-p", "m/44'/60'/0'/0/0")
+	stderr.Reset()
+	cmd.Stderr = &stderr
+	if _, err := cmd.Output(); err != nil {
+		t.Fatalf("getPath failed: %v", err)
+	}
+	if !strings.Contains(stderr.String(), "publicly known mnemonic") {
+		t.Errorf("Expected public mnemonic warning, got %q", stderr.String())
+	}
+}
+
+func TestPaperWalletCommand(t *testing.T) {
+	dir := t.TempDir()
+	mnemonic := "test test test test test test test test test test test junk"
+
+	for _, name := range []string{"wallet.pdf", "wallet.svg", "wallet.html"} {
+		file := filepath.Join(dir, name)
+		output, err := exec.Command("go", "run", "../main.go", "paperWallet", "-m", mnemonic, "-o", file).Output()
+		if err != nil {
+			t.Fatalf("paperWallet -o %s failed: %v", name, err)
+		}
+		if got := strings.TrimSpace(string(output)); got != "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266" {
+			t.Errorf("paperWallet printed %q", got)
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != 0600 {
+			t.Errorf("%s has mode %v, want 0600", name, info.Mode().Perm())
+		}
+		content, _ := os.ReadFile(file)
+		if !strings.Contains(string(content), "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80") {
+			t.Errorf("%s does not contain the private key", name)
+		}
+	}
+
+	if output, err := exec.Command("go", "run", "../main.go", "paperWallet", "-o", filepath.Join(dir, "wallet.txt")).CombinedOutput(); err == nil {
+		t.Errorf("Expected unknown format to fail, got %s", output)
+	}
+
+	output, err := exec.Command("go", "run", "../main.go", "getAddress", "--qr-ascii", "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80").Output()
+	if err != nil {
+		t.Fatalf("getAddress --qr-ascii failed: %v", err)
+	}
+	if !strings.Contains(string(output), "##############") {
+		t.Errorf("Expected an ASCII QR code, got %s", output)
+	}
+}
+
+func TestURCommands(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	frames, err := exec.Command("go", "run", "../main.go", "urEncode", "-t", "hdkey", "-m", mnemonic, "-p", "m/84'/0'/0'").Output()
+	if err != nil {
+		t.Fatalf("urEncode -t hdkey failed: %v", err)
+	}
+	if !strings.HasPrefix(string(frames), "ur:crypto-hdkey/") {
+		t.Fatalf("urEncode printed %q", frames)
+	}
+	decode := exec.Command("go", "run", "../main.go", "urDecode")
+	decode.Stdin = strings.NewReader(string(frames))
+	output, err := decode.Output()
+	if err != nil {
+		t.Fatalf("urDecode failed: %v", err)
+	}
+	// BIP84 test vector account key
+	want := "[73c5da0a/84'/0'/0']xpub6CatWdiZiodmUeTDp8LT5or8nmbKNcuyvz7WyksVFkKB4RHwCD3XyuvPEbvqAQY3rAPshWcMLoP2fMFMKHPJ4ZeZXYVUhLv1VMrjPC7PW6V"
+	if got := strings.TrimSpace(string(output)); got != want {
+		t.Errorf("urDecode printed %q, want %q", got, want)
+	}
+
+	// A multi-part PSBT decodes without its second frame
+	psbt := "cHNidP8BAJoCAAAAAljoeiG1ba8MI76OcHBFbDNvfLqlyHV5JPVFiHuyq911AAAAAAD/////g40EJ9DsZQpoqka7CwmK6kQiwHGyyng1Kgd5WdB86h0BAAAAAP////8CcKrwCAAAAAAWABTYXCtx0AYLCcmIauuBXlCZHdoSTQDh9QUAAAAAFgAUAK6pouXw+HaliN9VRuh0LR2HAI8AAAAAAAA="
+	frames, err = exec.Command("go", "run", "../main.go", "urEncode", "-t", "psbt", "--max-fragment", "40", "--frames", "20", psbt).Output()
+	if err != nil {
+		t.Fatalf("urEncode -t psbt failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(frames)), "\n")
+	if len(lines) != 20 || !strings.HasPrefix(lines[0], "ur:crypto-psbt/1-5/") {
+		t.Fatalf("urEncode printed %d frames: %q", len(lines), lines[0])
+	}
+	args := append([]string{"run", "../main.go", "urDecode", lines[0]}, lines[2:]...)
+	output, err = exec.Command("go", args...).Output()
+	if err != nil {
+		t.Fatalf("urDecode failed: %v", err)
+	}
+	if got := strings.TrimSpace(string(output)); got != psbt {
+		t.Errorf("urDecode printed %q, want %q", got, psbt)
+	}
+
+	output, _ = exec.Command("go", "run", "../main.go", "urDecode", lines[0]).CombinedOutput()
+	if !strings.Contains(string(output), "Error:") {
+		t.Errorf("Expected an incomplete UR error, got %s", output)
+	}
+}
+
+func TestGetPathEd25519Chains(t *testing.T) {
+	// SEP-0005 test 1, first account
+	mnemonic := "illness spike retreat truth genius clock brain pass fit cave bargain toe"
+	output, err := exec.Command("go", "run", "../main.go", "getPath", "--chain", "stellar", "-m", mnemonic).Output()
+	if err != nil {
+		t.Fatalf("getPath --chain stellar failed: %v", err)
+	}
+	want := "GDRXE2BQUC3AZNPVFSCEZ76NJ3WWL25FYFK6RGZGIEKWE4SOOHSUJUJ6:SBGWSG6BTNCKCOB3DIFBGCVMUPQFYPA2G4O34RMTB343OYPXU5DJDVMN"
+	if got := strings.TrimSpace(string(output)); got != want {
+		t.Errorf("getPath --chain stellar printed %q, want %q", got, want)
+	}
+
+	if output, err := exec.Command("go", "run", "../main.go", "getPath", "--chain", "solana", "-m", mnemonic, "-p", "m/44'/501'/0'/0").CombinedOutput(); err == nil {
+		t.Errorf("Expected a non-hardened ed25519 path to fail, got %s", output)
+	}
+	if output, err := exec.Command("go", "run", "../main.go", "getPath", "--chain", "cardano", "-m", mnemonic).CombinedOutput(); err == nil {
+		t.Errorf("Expected an unknown chain to fail, got %s", output)
+	}
+}
+
+func TestTronCommands(t *testing.T) {
+	// Hardhat/Anvil account #0, 0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266
+	key := "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+	output, err := exec.Command("go", "run", "../main.go", "getAddress", "--chain", "tron", key).Output()
+	if err != nil {
+		t.Fatalf("getAddress --chain tron failed: %v", err)
+	}
+	address := strings.TrimSpace(string(output))
+	if !strings.HasPrefix(address, "T") {
+		t.Fatalf("getAddress --chain tron printed %q, want a T... address", address)
+	}
+
+	output, err = exec.Command("go", "run", "../main.go", "tronAddress", address).Output()
+	if err != nil {
+		t.Fatalf("tronAddress failed: %v", err)
+	}
+	if got, want := strings.TrimSpace(string(output)), "41f39fd6e51aad88f6f4ce6ab8827279cfffb92266"; got != want {
+		t.Errorf("tronAddress %s printed %q, want %q", address, got, want)
+	}
+
+	output, err = exec.Command("go", "run", "../main.go", "tronAddress", "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266").Output()
+	if err != nil {
+		t.Fatalf("tronAddress 0x failed: %v", err)
+	}
+	if got := strings.TrimSpace(string(output)); got != address {
+		t.Errorf("tronAddress 0x... printed %q, want %q", got, address)
+	}
+
+	if output, err := exec.Command("go", "run", "../main.go", "tronAddress", "TNPeeaaFB7K9cmo4uQpcU32zGK8G1NYqeM").CombinedOutput(); err == nil {
+		t.Errorf("Expected a bad checksum to fail, got %s", output)
+	}
+}
+
+func TestCosmosCommands(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	output, err := exec.Command("go", "run", "../main.go", "getPath", "--chain", "cosmos", "-m", mnemonic).Output()
+	if err != nil {
+		t.Fatalf("getPath --chain cosmos failed: %v", err)
+	}
+	if got := strings.TrimSpace(string(output)); !strings.HasPrefix(got, "cosmos19rl4cm2hmr8afy4kldpxz3fka4jguq0auqdal4:") {
+		t.Errorf("getPath --chain cosmos printed %q", got)
+	}
+
+	key := "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+	output, err = exec.Command("go", "run", "../main.go", "getAddress", "--chain", "cosmos", "--hrp", "juno", key).Output()
+	if err != nil {
+		t.Fatalf("getAddress --hrp juno failed: %v", err)
+	}
+	if got := strings.TrimSpace(string(output)); !strings.HasPrefix(got, "juno1") {
+		t.Errorf("getAddress --hrp juno printed %q", got)
+	}
+
+	doc := `{"account_number":"0","chain_id":"test","fee":{"amount":[],"gas":"0"},"memo":"","msgs":[],"sequence":"0"}`
+	output, err = exec.Command("go", "run", "../main.go", "cosmosSign", "-k", key, doc).Output()
+	if err != nil {
+		t.Fatalf("cosmosSign failed: %v", err)
+	}
+	var sig struct {
+		PubKey struct {
+			Type string `json:"type"`
+		} `json:"pub_key"`
+		Signature []byte `json:"signature"`
+	}
+	if err := json.Unmarshal(output, &sig); err != nil {
+		t.Fatalf("cosmosSign printed %s: %v", output, err)
+	}
+	if sig.PubKey.Type != "tendermint/PubKeySecp256k1" || len(sig.Signature) != 64 {
+		t.Errorf("cosmosSign printed %s", output)
+	}
+
+	if output, err := exec.Command("go", "run", "../main.go", "cosmosSign", "-k", key, `{"chain_id":"test"}`).CombinedOutput(); err == nil {
+		t.Errorf("Expected an incomplete sign doc to fail, got %s", output)
+	}
+}
+
+func TestUTXOChainAddresses(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--chain", "litecoin"}, "ltc1qjmxnz78nmc8nq77wuxh25n2es7rzm5c2rkk4wh:"},
+		{[]string{"--chain", "litecoin", "--address-type", "p2pkh"}, "LUWPbpM43E2p7ZSh8cyTBEkvpHmr3cB8Ez:"},
+		{[]string{"--chain", "dogecoin"}, "DBus3bamQjgJULBJtYXpEzDWQRwF5iwxgC:"},
+		{[]string{"--chain", "bitcoincash"}, "bitcoincash:qqyx49mu0kkn9ftfj6hje6g2wfer34yfnq5tahq3q6:"},
+	}
+	for _, test := range tests {
+		args := append([]string{"run", "../main.go", "getPath", "-m", mnemonic}, test.args...)
+		output, err := exec.Command("go", args...).Output()
+		if err != nil {
+			t.Fatalf("getPath %v failed: %v", test.args, err)
+		}
+		if got := strings.TrimSpace(string(output)); !strings.HasPrefix(got, test.want) {
+			t.Errorf("getPath %v printed %q, want prefix %q", test.args, got, test.want)
+		}
+	}
+
+	key := "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+	if output, err := exec.Command("go", "run", "../main.go", "getAddress", "--chain", "dogecoin", "--address-type", "p2wpkh", key).CombinedOutput(); err == nil {
+		t.Errorf("Expected an address type Dogecoin lacks to fail, got %s", output)
+	}
+}
+
+func TestValidateAddressCommand(t *testing.T) {
+	output, err := exec.Command("go", "run", "../main.go", "validateAddress", "--to", "checksummed", "0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266").Output()
+	if err != nil {
+		t.Fatalf("validateAddress --to checksummed failed: %v", err)
+	}
+	if got, want := strings.TrimSpace(string(output)), "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"; got != want {
+		t.Errorf("validateAddress --to checksummed printed %q, want %q", got, want)
+	}
+
+	output, err = exec.Command("go", "run", "../main.go", "validateAddress", "--to", "legacy", "bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a").Output()
+	if err != nil {
+		t.Fatalf("validateAddress --to legacy failed: %v", err)
+	}
+	if got, want := strings.TrimSpace(string(output)), "1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu"; got != want {
+		t.Errorf("validateAddress --to legacy printed %q, want %q", got, want)
+	}
+
+	output, err = exec.Command("go", "run", "../main.go", "validateAddress", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5").Output()
+	if err == nil {
+		t.Error("Expected a bad bech32 checksum to fail")
+	}
+	if !strings.Contains(string(output), "position 42") {
+		t.Errorf("validateAddress did not locate the typo: %s", output)
+	}
+
+	if output, err := exec.Command("go", "run", "../main.go", "validateAddress", "--chain", "litecoin", "DBus3bamQjgJULBJtYXpEzDWQRwF5iwxgC").CombinedOutput(); err == nil {
+		t.Errorf("Expected a Dogecoin address to fail --chain litecoin, got %s", output)
+	}
+}
+
+func TestPublicKeyCommands(t *testing.T) {
+	key := "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+	output, err := exec.Command("go", "run", "../main.go", "getPublicKey", "--compressed", key).Output()
+	if err != nil {
+		t.Fatalf("getPublicKey --compressed failed: %v", err)
+	}
+	compressed := strings.TrimSpace(string(output))
+	if compressed != "0x038318535b54105d4a7aae60c08fc45f9687181b4fdfc625bd1a753fa7397fed75" {
+		t.Errorf("getPublicKey --compressed printed %q", compressed)
+	}
+
+	output, err = exec.Command("go", "run", "../main.go", "convertPubKey", "--to", "uncompressed", compressed).Output()
+	if err != nil {
+		t.Fatalf("convertPubKey failed: %v", err)
+	}
+	uncompressed := strings.TrimSpace(string(output))
+	if !strings.HasPrefix(uncompressed, "0x048318535b54") || len(uncompressed) != 132 {
+		t.Errorf("convertPubKey --to uncompressed printed %q", uncompressed)
+	}
+
+	output, err = exec.Command("go", "run", "../main.go", "pubKeyToAddress", uncompressed).Output()
+	if err != nil {
+		t.Fatalf("pubKeyToAddress failed: %v", err)
+	}
+	if got := strings.TrimSpace(string(output)); got != "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266" {
+		t.Errorf("pubKeyToAddress printed %q", got)
+	}
+
+	// BIP173 example key
+	output, err = exec.Command("go", "run", "../main.go", "pubKeyToAddress", "--chain", "bitcoin", "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798").Output()
+	if err != nil {
+		t.Fatalf("pubKeyToAddress --chain bitcoin failed: %v", err)
+	}
+	if got := strings.TrimSpace(string(output)); got != "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4" {
+		t.Errorf("pubKeyToAddress --chain bitcoin printed %q", got)
+	}
+
+	if output, err := exec.Command("go", "run", "../main.go", "pubKeyToAddress", "0x1234").CombinedOutput(); err == nil {
+		t.Errorf("Expected a short public key to fail, got %s", output)
+	}
+
+	// an x-only key has no y parity, so it cannot name this (odd-y) key
+	xOnly := "8318535b54105d4a7aae60c08fc45f9687181b4fdfc625bd1a753fa7397fed75"
+	for _, args := range [][]string{
+		{"pubKeyToAddress", xOnly},
+		{"verifySignature", xOnly, strings.Repeat("00", 32), strings.Repeat("00", 64)},
+	} {
+		output, err := exec.Command("go", append([]string{"run", "../main.go"}, args...)...).CombinedOutput()
+		if err == nil || !strings.Contains(string(output), "parity") {
+			t.Errorf("%s of an x-only key = %q, %v; want a parity error", args[0], output, err)
+		}
+	}
+}
+
+func TestWIFCommands(t *testing.T) {
+	key := "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+	wif := "L2z8PYRmdwz6N8GvkdAJsVtFY7Rt4cQP2DgVppuM7inTuWM3DLVL"
+	output, err := exec.Command("go", "run", "../main.go", "convertPrivKey", key).Output()
+	if err != nil {
+		t.Fatalf("convertPrivKey failed: %v", err)
+	}
+	if got := strings.TrimSpace(string(output)); got != wif {
+		t.Errorf("convertPrivKey printed %q, want %q", got, wif)
+	}
+	output, err = exec.Command("go", "run", "../main.go", "convertPrivKey", wif).Output()
+	if err != nil {
+		t.Fatalf("convertPrivKey of a WIF key failed: %v", err)
+	}
+	if got := strings.TrimSpace(string(output)); got != "0x"+key {
+		t.Errorf("convertPrivKey printed %q, want 0x%s", got, key)
+	}
+
+	// A WIF key without --chain gives the address on its own chain
+	output, err = exec.Command("go", "run", "../main.go", "getAddress", wif).Output()
+	if err != nil {
+		t.Fatalf("getAddress of a WIF key failed: %v", err)
+	}
+	if got := strings.TrimSpace(string(output)); got != "bc1q5428vq2uzwhm3taey9sr9x5vm6tk78ew0wt525" {
+		t.Errorf("getAddress printed %q", got)
+	}
+	output, err = exec.Command("go", "run", "../main.go", "getAddress", "--chain", "ethereum", wif).Output()
+	if err != nil {
+		t.Fatalf("getAddress --chain ethereum failed: %v", err)
+	}
+	if got := strings.TrimSpace(string(output)); got != "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266" {
+		t.Errorf("getAddress --chain ethereum printed %q", got)
+	}
+	// Uncompressed WIF of private key 1
+	output, err = exec.Command("go", "run", "../main.go", "getAddress", "5HpHagT65TZzG1PH3CSu63k8DbpvD8s5ip4nEB3kEsreAnchuDf").Output()
+	if err != nil {
+		t.Fatalf("getAddress of an uncompressed WIF key failed: %v", err)
+	}
+	if got := strings.TrimSpace(string(output)); got != "1EHNa6Q4Jz2uvNExL497mE43ikXhwF6kZm" {
+		t.Errorf("getAddress printed %q", got)
+	}
+
+	output, err = exec.Command("go", "run", "../main.go", "getPath", "-m", "test test test test test test test test test test test junk", "-p", "m/44'/0'/0'/0/0", "--wif").Output()
+	if err != nil {
+		t.Fatalf("getPath --wif failed: %v", err)
+	}
+	if parts := strings.Split(strings.TrimSpace(string(output)), ":"); len(parts) != 2 || parts[0][0] != '1' || len(parts[1]) != 52 {
+		t.Errorf("getPath --wif printed %q", output)
+	}
+}
+
+func TestKeystoreInput(t *testing.T) {
+	privateKey, err := crypto.HexToECDSA("ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyJSON, err := keystore.EncryptKey(&keystore.Key{
+		Address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+		PrivateKey: privateKey,
+	}, "hunter2", keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "keystore.json")
+	passwordFile := filepath.Join(dir, "password.txt")
+	if err := os.WriteFile(keyFile, keyJSON, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(passwordFile, []byte("hunter2\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := exec.Command("go", "run", "../main.go", "getAddress", "--keystore", keyFile, "--password-file", passwordFile).Output()
+	if err != nil {
+		t.Fatalf("getAddress --keystore failed: %v", err)
+	}
+	if got := strings.TrimSpace(string(output)); got != "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266" {
+		t.Errorf("getAddress --keystore printed %q", got)
+	}
+
+	if err := os.WriteFile(passwordFile, []byte("wrong"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if output, err := exec.Command("go", "run", "../main.go", "getAddress", "--keystore", keyFile, "--password-file", passwordFile).CombinedOutput(); err == nil {
+		t.Errorf("Expected a wrong keystore password to fail, got %s", output)
+	}
+}
+
+func TestEncryptDecryptCommands(t *testing.T) {
+	key := "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+	publicKey := "0x038318535b54105d4a7aae60c08fc45f9687181b4fdfc625bd1a753fa7397fed75"
+	dir := t.TempDir()
+	for _, format := range []string{"geth", "eth-crypto"} {
+		plainFile := filepath.Join(dir, format+".txt")
+		encFile := filepath.Join(dir, format+".enc")
+		if err := os.WriteFile(plainFile, []byte("db password\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if output, err := exec.Command("go", "run", "../main.go", "encrypt", "--format", format, "-r", publicKey, "-i", plainFile, "-o", encFile).CombinedOutput(); err != nil {
+			t.Fatalf("encrypt --format %s failed: %v\n%s", format, err, output)
+		}
+
+		cmd := exec.Command("go", "run", "../main.go", "decrypt", "--format", format, "-k", key)
+		f, err := os.Open(encFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cmd.Stdin = f
+		output, err := cmd.Output()
+		f.Close()
+		if err != nil {
+			t.Fatalf("decrypt --format %s failed: %v", format, err)
+		}
+		if string(output) != "db password\n" {
+			t.Errorf("decrypt --format %s printed %q", format, output)
+		}
+
+		// Another key fails
+		other := "59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d"
+		if output, err := exec.Command("go", "run", "../main.go", "decrypt", "--format", format, "-k", other, "-i", encFile).CombinedOutput(); err == nil {
+			t.Errorf("Expected decrypt with another key to fail, got %s", output)
+		}
+	}
+}
+
+func TestECDHCommand(t *testing.T) {
+	// Hardhat accounts #0 and #1
+	alice := "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+	alicePub := "0x038318535b54105d4a7aae60c08fc45f9687181b4fdfc625bd1a753fa7397fed75"
+	bob := "59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d"
+	output, err := exec.Command("go", "run", "../main.go", "getPublicKey", bob).Output()
+	if err != nil {
+		t.Fatalf("getPublicKey failed: %v", err)
+	}
+	bobPub := strings.TrimSpace(string(output))
+
+	run := func(args ...string) string {
+		t.Helper()
+		output, err := exec.Command("go", append([]string{"run", "../main.go", "ecdh"}, args...)...).Output()
+		if err != nil {
+			t.Fatalf("ecdh %v failed: %v", args, err)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	ab := run("-k", alice, bobPub)
+	if len(ab) != 64 {
+		t.Fatalf("ecdh printed %q", ab)
+	}
+	if ba := run("-k", bob, alicePub); ba != ab {
+		t.Errorf("ecdh differs between peers: %s, %s", ab, ba)
+	}
+	if hd := run("-m", "test test test test test test test test test test test junk", "-p", "m/44'/60'/0'/0/1", alicePub); hd != ab {
+		t.Errorf("ecdh from a mnemonic printed %s, want %s", hd, ab)
+	}
+
+	k1 := run("-k", alice, "--info", "orders v1", "--salt", "0x1234", "--length", "16", bobPub)
+	if k2 := run("-k", bob, "--info", "orders v1", "--salt", "0x1234", "--length", "16", alicePub); len(k1) != 32 || k1 != k2 || k1 == ab[:32] {
+		t.Errorf("ecdh --info printed %s and %s", k1, k2)
+	}
+}
+
+func TestSchnorrCommands(t *testing.T) {
+	run := func(args ...string) string {
+		t.Helper()
+		output, err := exec.Command("go", append([]string{"run", "../main.go"}, args...)...).Output()
+		if err != nil {
+			t.Fatalf("%v failed: %v", args, err)
+		}
+		return strings.TrimSpace(string(output))
+	}
+
+	// BIP340 test vector 1
+	msg := "243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89"
+	pub := "DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659"
+	sig := run("schnorrSign", "-k", "B7E151628AED2A6ABF7158809CF4F3C762E7160F38B4DA56A784D9045190CFEF",
+		"--aux", "0000000000000000000000000000000000000000000000000000000000000001", "--hex", msg)
+	if want := "6896bd60eeae296db48a229ff71dfe071bde413e6d43f917dc8dcf8c78de33418906d11ac976abccb20b091292bff4ea897efcb639ea871cfa95f6de339e4b0a"; sig != want {
+		t.Errorf("schnorrSign printed %s, want %s", sig, want)
+	}
+	if got := run("schnorrVerify", "--hex", pub, sig, msg); got != "Valid" {
+		t.Errorf("schnorrVerify printed %q", got)
+	}
+	if output, err := exec.Command("go", "run", "../main.go", "schnorrVerify", pub, sig, "hello").Output(); err == nil || !strings.HasPrefix(string(output), "Invalid") {
+		t.Errorf("schnorrVerify of another message = %q, %v", output, err)
+	}
+
+	// BIP86: a key-path signature verifies under the output key
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	tweak := run("taprootTweak", "cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115")
+	if !strings.Contains(tweak, "Output Key: a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c") ||
+		!strings.Contains(tweak, "Address: bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr") {
+		t.Errorf("taprootTweak printed %q", tweak)
+	}
+	sig = run("schnorrSign", "-m", mnemonic, "-p", "m/86'/0'/0'/0/0", "--taproot", "spend")
+	if got := run("schnorrVerify", "a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c", sig, "spend"); got != "Valid" {
+		t.Errorf("schnorrVerify of a --taproot signature printed %q", got)
+	}
+	tweakedKey := run("taprootTweak", "-m", mnemonic, "-p", "m/86'/0'/0'/0/0")
+	if got := run("getPublicKey", "--compressed", tweakedKey); !strings.HasSuffix(got, "a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c") {
+		t.Errorf("taprootTweak -m printed a key with public key %s", got)
+	}
+
+	if got := run("getPath", "-m", mnemonic, "--chain", "bitcoin", "--address-type", "p2tr"); !strings.HasPrefix(got, "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr:") {
+		t.Errorf("getPath --address-type p2tr printed %q", got)
+	}
+}
+
+func TestSignatureCommands(t *testing.T) {
+	run := func(args ...string) string {
+		t.Helper()
+		output, err := exec.Command("go", append([]string{"run", "../main.go"}, args...)...).Output()
+		if err != nil {
+			t.Fatalf("%v failed: %v", args, err)
+		}
+		return strings.TrimSpace(string(output))
+	}
+
+	// RFC6979 vector: d = 1, SHA-256("Satoshi Nakamoto")
+	key := "0000000000000000000000000000000000000000000000000000000000000001"
+	pub := "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"
+	hash := "a0dc65ffca799873cbea0ac274015b9526505daaaed385155425f7337704883e"
+	r, s := "934b1ea10a4b3c1757e2b0c017d0b6143ce3c9a7e6a4a49860d7a6ab210ee3d8", "2442ce9d2b916064108014783e923ec36b49743e2ffa1c4496f01a512aafd9e5"
+	rsv := run("signHash", "-k", key, hash)
+	if !strings.HasPrefix(rsv, r+s) || len(rsv) != 130 {
+		t.Errorf("signHash printed %s, want %s%s || v", rsv, r, s)
+	}
+	der := run("signHash", "-k", key, "--format", "der", hash)
+	if want := "3045022100" + r + "0220" + s; der != want {
+		t.Errorf("signHash --format der printed %s, want %s", der, want)
+	}
+	if got := run("verifySignature", pub, hash, der); got != "Valid" {
+		t.Errorf("verifySignature printed %q", got)
+	}
+	if got := run("convertSignature", "--to", "rsv", "--pubkey", pub, "--hash", hash, der); got != rsv {
+		t.Errorf("convertSignature --to rsv printed %s, want %s", got, rsv)
+	}
+
+	// the high-S twin (n - s) is rejected unless allowed, and --low-s restores it
+	var high btcec.ModNScalar
+	high.SetByteSlice(common.FromHex(s))
+	high.Negate()
+	highS := high.Bytes()
+	highSig := r + hex.EncodeToString(highS[:])
+	if output, err := exec.Command("go", "run", "../main.go", "verifySignature", pub, hash, highSig).Output(); err == nil || !strings.Contains(string(output), "high S") {
+		t.Errorf("verifySignature of a high S = %q, %v", output, err)
+	}
+	if got := run("verifySignature", "--allow-high-s", pub, hash, highSig); got != "Valid" {
+		t.Errorf("verifySignature --allow-high-s printed %q", got)
+	}
+	if got := run("convertSignature", "--low-s", "--to", "der", highSig); got != der {
+		t.Errorf("convertSignature --low-s printed %s, want %s", got, der)
+	}
+}
+
+func TestEcrecoverCommand(t *testing.T) {
+	run := func(args ...string) string {
+		t.Helper()
+		output, err := exec.Command("go", append([]string{"run", "../main.go"}, args...)...).Output()
+		if err != nil {
+			t.Fatalf("%v failed: %v", args, err)
+		}
+		return strings.TrimSpace(string(output))
+	}
+
+	// EIP-2098 test case: "Hello World" signed by 0x1234...1234
+	address := "Address: 0x2e988A386a799F506693793c6A5AF6B54dfAaBfB"
+	r, s := "68a020a209d3d56c46f38cc50a33f704f4a9a10a59377f8dd762ac66910e9b90", "7e865ad05c4035ab5792787d4a0297a43617ae897930a6fe4d822b8faea52064"
+	for _, args := range [][]string{
+		{"ecrecover", "0x" + r + s + "1b", "Hello World"},
+		{"ecrecover", r + s + "00", "Hello World"},
+		// EIP-2098: v = 0 leaves s as is
+		{"ecrecover", r + s, "Hello World"},
+		// EIP-155 v for chain 1
+		{"ecrecover", "--r", r, "--s", s, "--v", "37", "Hello World"},
+		{"ecrecover", "--hex", r + s + "1b", hex.EncodeToString([]byte("Hello World"))},
+		{"ecrecover", "--hash", hex.EncodeToString(accounts.TextHash([]byte("Hello World"))), r + s + "1b"},
+	} {
+		if got := run(args...); !strings.Contains(got, address) {
+			t.Errorf("%v printed %q", args, got)
+		}
+	}
+
+	// the second EIP-2098 case has v = 1, folded into s
+	got := run("ecrecover", "9328da16089fcba9bececa81663203989f2df5fe1faa6291a45381c81bd17f76939c6d6b623b42da56557e5e734a43dc83345ddfadec52cbe24d0cc64f550793", "It's a small(er) world")
+	if !strings.Contains(got, address) {
+		t.Errorf("ecrecover of an EIP-2098 signature with v = 1 printed %q", got)
+	}
+	if got := run("ecrecover", "--chain", "tron", r+s+"1b", "Hello World"); !strings.Contains(got, "Address: T") {
+		t.Errorf("ecrecover --chain tron printed %q", got)
+	}
+	if output, err := exec.Command("go", "run", "../main.go", "ecrecover", r+s, "--r", r, "Hello World").CombinedOutput(); err == nil {
+		t.Errorf("ecrecover with only --r succeeded: %s", output)
+	}
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spark8899/gowallet/internal/contract"
+	"github.com/spark8899/gowallet/internal/vanity"
+	"github.com/spf13/cobra"
+)
+
+var deployerStr string
+var nonce uint64
+var saltStr string
+var initCodeStr string
+var initCodeHashStr string
+var mineSalt bool
+
+var contractAddressCmd = &cobra.Command{
+	Use:   "contractAddress",
+	Short: "Predict CREATE and CREATE2 contract addresses",
+	Long: "Predict the address of a contract deployed with CREATE (deployer + nonce) or CREATE2\n" +
+		"(deployer + salt + init code hash). With --mine, search salts in parallel for a CREATE2\n" +
+		"address matching --prefix/--suffix/--regex.",
+	Example: `  gowallet contractAddress -d 0x6ac7ea33f8831ea9dcc53393aaa88b25a785dbf0 -n 1
+  gowallet contractAddress -d <factory> --salt 0x01 --init-code-hash <keccak256_hex>
+  gowallet contractAddress -d <factory> --init-code <bytecode_hex> --mine --prefix 0000`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 && args[0] == "help" {
+			cmd.Help()
+			os.Exit(0)
+		}
+		if deployerStr == "" {
+			fmt.Println("Error: Deployer address is required. Use -d flag.")
+			os.Exit(1)
+		}
+		deployer, err := contract.ParseAddress(deployerStr)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		create2 := mineSalt || saltStr != "" || initCodeStr != "" || initCodeHashStr != ""
+		if !create2 {
+			fmt.Println(contract.CreateAddress(deployer, nonce).Hex())
+			return
+		}
+
+		var initCodeHash []byte
+		switch {
+		case initCodeHashStr != "":
+			initCodeHash, err = contract.ParseInitCodeHash(initCodeHashStr)
+		case initCodeStr != "":
+			initCodeHash, err = contract.InitCodeHash(initCodeStr)
+		default:
+			fmt.Println("Error: CREATE2 requires --init-code or --init-code-hash.")
+			os.Exit(1)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if !mineSalt {
+			if saltStr == "" {
+				fmt.Println("Error: CREATE2 requires --salt (or --mine to search for one).")
+				os.Exit(1)
+			}
+			salt, err := contract.ParseSalt(saltStr)
+			if err != nil {
+				log.Fatal(err)
+			}
+			fmt.Println(contract.Create2Address(deployer, salt, initCodeHash).Hex())
+			return
+		}
+
+		matcher, err := vanity.NewMatcher(vanityPrefix, vanitySuffix, vanityRegex, vanityCaseSensitive)
+		if err != nil {
+			log.Fatal(err)
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		cfg := searchConfig(matcher, "salt")
+		result, stats, err := contract.MineSalt(ctx, deployer, initCodeHash, matcher, cfg)
+		printSearchSummary(stats, "salt")
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Printf("%v:%v\n", result.Address.Hex(), hexutil.Encode(result.Salt[:]))
+	},
+}
+
+func init() {
+	contractAddressCmd.Flags().StringVarP(&deployerStr, "deployer", "d", "", "deployer (sender or factory) address")
+	contractAddressCmd.Flags().Uint64VarP(&nonce, "nonce", "n", 0, "deployer nonce for CREATE")
+	contractAddressCmd.Flags().StringVar(&saltStr, "salt", "", "CREATE2 salt (hex, up to 32 bytes)")
+	contractAddressCmd.Flags().StringVar(&initCodeStr, "init-code", "", "CREATE2 init code (hex)")
+	contractAddressCmd.Flags().StringVar(&initCodeHashStr, "init-code-hash", "", "CREATE2 keccak256 hash of the init code (hex)")
+	contractAddressCmd.Flags().BoolVar(&mineSalt, "mine", false, "search for a CREATE2 salt matching --prefix/--suffix/--regex")
+	contractAddressCmd.Flags().StringVar(&vanityPrefix, "prefix", "", "hex prefix the address must start with (with --mine)")
+	contractAddressCmd.Flags().StringVar(&vanitySuffix, "suffix", "", "hex suffix the address must end with (with --mine)")
+	contractAddressCmd.Flags().StringVar(&vanityRegex, "regex", "", "regular expression the address must match (with --mine)")
+	contractAddressCmd.Flags().BoolVar(&vanityCaseSensitive, "case-sensitive", false, "match against the EIP-55 checksummed address (with --mine)")
+	contractAddressCmd.Flags().IntVarP(&vanityWorkers, "workers", "w", 0, "number of worker goroutines (default: number of CPUs)")
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/spark8899/gowallet/internal/ecies"
+	"github.com/spark8899/gowallet/internal/hdwallet"
+	"github.com/spark8899/gowallet/internal/security"
+	"github.com/spf13/cobra"
+)
+
+var hkdfSalt string
+var hkdfInfo string
+var hkdfLength int
+var useHKDF bool
+
+var ecdhCmd = &cobra.Command{
+	Use:   "ecdh [peer_public_key]",
+	Short: "Derive an ECDH shared secret with a peer's public key",
+	Long: "Combine our private key with a peer's secp256k1 public key into a shared secret both sides can\n" +
+		"compute. By default the output is SHA-256 of the compressed shared point (libsecp256k1's\n" +
+		"secp256k1_ecdh); with --hkdf, --salt or --info it is HKDF-SHA256 of the x coordinate instead.\n" +
+		"The private key may be hex, WIF, a keystore file, or a mnemonic or seed with a derivation path.",
+	Example: `  gowallet ecdh -k <private_key_hex> 0x02...
+  gowallet ecdh -m "apple banana ..." -p "m/44'/60'/0'/0/0" 0x03...
+  gowallet ecdh --keystore UTC--... --password-file pass.txt --info "orders-service v1" --salt 0x1234 0x04...`,
+	Run: func(cmd *cobra.Command, args []string) {
+		pub := pubKeyArg(cmd, args)
+		secret := keySecretOrPath()
+		defer secret.Destroy()
+
+		var shared *security.SecretBuffer
+		var err error
+		if useHKDF || hkdfSalt != "" || hkdfInfo != "" {
+			var salt []byte
+			if salt, err = saltArg(hkdfSalt); err != nil {
+				log.Fatal(err)
+			}
+			shared, err = ecies.SharedKeyHKDF(secret, pub, salt, hkdfInfo, hkdfLength)
+		} else {
+			shared, err = ecies.SharedSecret(secret, pub)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer shared.Destroy()
+
+		sharedHex, err := security.NewSecretBuffer(hex.EncodedLen(shared.Len()))
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer sharedHex.Destroy()
+		hex.Encode(sharedHex.Bytes(), shared.Bytes())
+		writeSecretLine(sharedHex)
+	},
+}
+
+// keySecretOrPath returns the private key from -k, --keystore, or -m/-s with -p
+func keySecretOrPath() *security.SecretBuffer {
+	if mnemonicStr == "" && seedStr == "" {
+		if privateKey == "" && keystoreFile == "" {
+			fmt.Println("Error: Private key is required. Use -k, --keystore, or -m/-s with -p.")
+			os.Exit(1)
+		}
+		return keySecret()
+	}
+	if path == "" {
+		fmt.Println("Error: Path is required with a mnemonic or seed. Use -p flag.")
+		os.Exit(1)
+	}
+
+	var keyInfo *hdwallet.KeyInfo
+	var err error
+	if mnemonicStr != "" {
+		mnemonic := secretArg(mnemonicStr)
+		defer mnemonic.Destroy()
+		keyInfo, err = hdwallet.PathFromMnemonic(mnemonic, path, selectedNetwork())
+	} else {
+		seed := secretArg(seedStr)
+		defer seed.Destroy()
+		keyInfo, err = hdwallet.PathFromSeed(seed, path, selectedNetwork())
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	// keep only the private key
+	keyInfo.WIF.Destroy()
+	keyInfo.ExtendedPrivateKey.Destroy()
+	return keyInfo.PrivateKey
+}
+
+// saltArg decodes a 0x-prefixed hex salt, or takes any other text as is
+func saltArg(s string) ([]byte, error) {
+	if s == "" {
+		return nil, nil
+	}
+	if strings.HasPrefix(s, "0x") {
+		salt, err := hex.DecodeString(s[2:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex salt: %w", err)
+		}
+		return salt, nil
+	}
+	return []byte(s), nil
+}
+
+func init() {
+	ecdhCmd.Flags().StringVarP(&privateKey, "key", "k", "", "private key, hex or WIF")
+	ecdhCmd.Flags().StringVar(&keystoreFile, "keystore", "", "read the private key from an Ethereum keystore (v3) file")
+	ecdhCmd.Flags().StringVar(&passwordFile, "password-file", "", "file holding the keystore password")
+	ecdhCmd.Flags().StringVarP(&mnemonicStr, "mnemonic", "m", "", "derive the private key from this mnemonic at -p")
+	ecdhCmd.Flags().StringVarP(&seedStr, "seed", "s", "", "derive the private key from this seed (hex) at -p")
+	ecdhCmd.Flags().StringVarP(&path, "path", "p", "", "derivation path of the private key, e.g. \"m/44'/60'/0'/0/0\"")
+	ecdhCmd.Flags().BoolVar(&useHKDF, "hkdf", false, "derive the output with HKDF-SHA256 (implied by --salt and --info)")
+	ecdhCmd.Flags().StringVar(&hkdfSalt, "salt", "", "HKDF salt, text or 0x-prefixed hex")
+	ecdhCmd.Flags().StringVar(&hkdfInfo, "info", "", "HKDF info binding the key to its purpose")
+	ecdhCmd.Flags().IntVar(&hkdfLength, "length", 32, "HKDF output length in bytes")
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spark8899/gowallet/internal/ecies"
+	"github.com/spark8899/gowallet/internal/hdwallet"
+	"github.com/spark8899/gowallet/internal/security"
+	"github.com/spf13/cobra"
+)
+
+var recipientKey string
+var eciesFormat string
+var eciesInput string
+var eciesHex bool
+
+var encryptCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Encrypt a message to a public key (ECIES)",
+	Long: "Encrypt a file or stdin to a secp256k1 public key with ECIES, so only the holder of its private key\n" +
+		"can read it. --format geth (default) writes go-ethereum crypto/ecies binary ciphertext (--hex for\n" +
+		"text); eth-crypto writes the hex string of eth-crypto's cipher.stringify. The recipient key may be\n" +
+		"in any form convertPubKey accepts; getPublicKey prints it from a private key.",
+	Example: `  gowallet encrypt -r 0x04... -i secret.txt -o secret.enc
+  echo -n "db password" | gowallet encrypt -r 0x02... --hex
+  gowallet encrypt --format eth-crypto -r 0x04... -i secret.txt`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 && args[0] == "help" {
+			cmd.Help()
+			os.Exit(0)
+		}
+		if recipientKey == "" {
+			fmt.Println("Error: Recipient public key is required. Use -r flag.")
+			os.Exit(1)
+		}
+		pub, err := hdwallet.ParsePublicKey(recipientKey)
+		if err != nil {
+			log.Fatal(err)
+		}
+		format, err := ecies.FormatByName(eciesFormat)
+		if err != nil {
+			log.Fatal(err)
+		}
+		plaintext := readEciesInput()
+		defer plaintext.Destroy()
+
+		ciphertext, err := ecies.Encrypt(randomSource(), pub, plaintext.Bytes(), format)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if eciesHex && format == ecies.Geth {
+			ciphertext = []byte(hex.EncodeToString(ciphertext) + "\n")
+		} else if format == ecies.EthCrypto && outputFile == "" {
+			ciphertext = append(ciphertext, '\n')
+		}
+		writeEciesOutput(ciphertext)
+	},
+}
+
+var decryptCmd = &cobra.Command{
+	Use:   "decrypt",
+	Short: "Decrypt an ECIES message with a private key",
+	Long: "Decrypt a message from encrypt, go-ethereum crypto/ecies or eth-crypto with a hex, WIF or keystore\n" +
+		"private key. Geth ciphertexts may be binary or hex; eth-crypto ones the stringified hex or the\n" +
+		"encrypted object's JSON. Output files are created with mode 0600.",
+	Example: `  gowallet decrypt -k <private_key_hex> -i secret.enc
+  gowallet decrypt --keystore UTC--... --password-file pass.txt -i secret.enc -o secret.txt
+  gowallet decrypt --format eth-crypto -k <private_key_hex> < message.txt`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 && args[0] == "help" {
+			cmd.Help()
+			os.Exit(0)
+		}
+		if privateKey == "" && keystoreFile == "" {
+			fmt.Println("Error: Private key is required. Use -k flag or --keystore.")
+			os.Exit(1)
+		}
+		format, err := ecies.FormatByName(eciesFormat)
+		if err != nil {
+			log.Fatal(err)
+		}
+		ciphertext := readEciesInput()
+		defer ciphertext.Destroy()
+		secret := keySecret()
+		defer secret.Destroy()
+
+		plaintext, err := ecies.Decrypt(secret, ciphertext.Bytes(), format)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer plaintext.Destroy()
+		writeEciesOutput(plaintext.Bytes())
+	},
+}
+
+// readEciesInput reads all of -i, or stdin without it, straight into a
+// SecretBuffer: a file into one sized by Stat, stdin into one that grows
+// and wipes what it outgrows
+func readEciesInput() *security.SecretBuffer {
+	f := os.Stdin
+	if eciesInput != "" && eciesInput != "-" {
+		var err error
+		if f, err = os.Open(eciesInput); err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+	}
+	size := 0
+	if info, err := f.Stat(); err == nil && info.Mode().IsRegular() {
+		size = int(info.Size())
+	}
+	data, err := security.ReadSecret(f, size)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return data
+}
+
+// writeEciesOutput writes data to -o (mode 0600), or stdout without it
+func writeEciesOutput(data []byte) {
+	if outputFile == "" {
+		if _, err := os.Stdout.Write(data); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if err := os.WriteFile(outputFile, data, 0600); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func init() {
+	encryptCmd.Flags().StringVarP(&recipientKey, "recipient", "r", "", "recipient public key (compressed, uncompressed, raw or xpub)")
+	encryptCmd.Flags().StringVar(&eciesFormat, "format", "geth", "ciphertext format: geth or eth-crypto")
+	encryptCmd.Flags().StringVarP(&eciesInput, "input", "i", "", "read the message from a file (default: stdin)")
+	encryptCmd.Flags().StringVarP(&outputFile, "output", "o", "", "write the ciphertext to a file (default: stdout)")
+	encryptCmd.Flags().BoolVar(&eciesHex, "hex", false, "geth: print the ciphertext as hex instead of binary")
+	decryptCmd.Flags().StringVarP(&privateKey, "key", "k", "", "private key, hex or WIF")
+	decryptCmd.Flags().StringVar(&keystoreFile, "keystore", "", "read the private key from an Ethereum keystore (v3) file")
+	decryptCmd.Flags().StringVar(&passwordFile, "password-file", "", "file holding the keystore password")
+	decryptCmd.Flags().StringVar(&eciesFormat, "format", "geth", "ciphertext format: geth or eth-crypto")
+	decryptCmd.Flags().StringVarP(&eciesInput, "input", "i", "", "read the ciphertext from a file (default: stdin)")
+	decryptCmd.Flags().StringVarP(&outputFile, "output", "o", "", "write the message to a file (mode 0600, default: stdout)")
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/spark8899/gowallet/internal/commonPrivateKey"
+	"github.com/spf13/cobra"
+)
+
+var sigR string
+var sigS string
+var sigV string
+
+var ecrecoverCmd = &cobra.Command{
+	Use:   "ecrecover [signature] [message]",
+	Short: "Recover the signer's public key and address from a signature",
+	Long: "Recover the public key and address that signed a message. The message is hashed as an EIP-191\n" +
+		"personal_sign message (\"\\x19Ethereum Signed Message:\\n\" + length), and read as with schnorrSign;\n" +
+		"--hash recovers from a raw 32-byte hash instead. The signature may be 65 bytes r || s || v (v as\n" +
+		"0/1, 27/28 or EIP-155), 64 bytes EIP-2098 compact, or given split as --r, --s and --v, in which case\n" +
+		"the first argument is the message. --chain prints the signer's address on another chain.",
+	Example: `  gowallet ecrecover 0x68a020a2...1b "Hello World"
+  gowallet ecrecover --hash 0x8144a6fa... 0x68a020a2...1b
+  gowallet ecrecover --r 0x68a020a2... --s 0x7e865ad0... --v 27 "Hello World"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 && args[0] == "help" {
+			cmd.Help()
+			os.Exit(0)
+		}
+
+		var sig *commonPrivateKey.Signature
+		var err error
+		if sigR != "" || sigS != "" || sigV != "" {
+			sig, err = splitSignatureArg()
+		} else {
+			if len(args) == 0 {
+				fmt.Println("Error: Signature is required. Provide it as an argument or use --r, --s and --v.")
+				os.Exit(1)
+			}
+			sig, err = recoverableSignatureArg(args[0])
+			args = args[1:]
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		var hash []byte
+		if signatureHash != "" {
+			if hash, err = hashArg(signatureHash); err != nil {
+				log.Fatal(err)
+			}
+		} else {
+			hash = accounts.TextHash(messageArg(args))
+		}
+
+		pub, err := sig.RecoverPublicKey(hash)
+		if err != nil {
+			log.Fatal(err)
+		}
+		address, err := pubKeyAddress(pub)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if !sig.IsLowS() {
+			fmt.Fprintln(os.Stderr, "WARNING: signature has a high S; Ethereum transactions and most verifiers reject it.")
+		}
+		fmt.Printf("Public Key: 0x%x\n", pub.SerializeUncompressed())
+		fmt.Printf("Compressed: 0x%x\n", pub.SerializeCompressed())
+		fmt.Printf("Address: %s\n", address)
+	},
+}
+
+// recoverableSignatureArg parses a 65-byte r || s || v or a 64-byte EIP-2098
+// signature
+func recoverableSignatureArg(s string) (*commonPrivateKey.Signature, error) {
+	raw, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature hex: %w", err)
+	}
+	switch len(raw) {
+	case 64:
+		return commonPrivateKey.ParseEIP2098(raw)
+	case 65:
+		return commonPrivateKey.ParseSignature(raw)
+	}
+	return nil, fmt.Errorf("%w: %d bytes, want 65 (r || s || v) or 64 (EIP-2098)", commonPrivateKey.ErrInvalidSignature, len(raw))
+}
+
+// splitSignatureArg builds a signature from --r, --s (hex, up to 32 bytes)
+// and --v (decimal or 0x hex)
+func splitSignatureArg() (*commonPrivateKey.Signature, error) {
+	if sigR == "" || sigS == "" || sigV == "" {
+		return nil, fmt.Errorf("--r, --s and --v must be given together")
+	}
+	rs := make([]byte, 64)
+	for i, part := range []string{sigR, sigS} {
+		b, err := hex.DecodeString(strings.TrimPrefix(part, "0x"))
+		if err != nil || len(b) > 32 {
+			return nil, fmt.Errorf("%w: %q is not a 32-byte hex integer", commonPrivateKey.ErrInvalidSignature, part)
+		}
+		copy(rs[32*(i+1)-len(b):], b)
+	}
+	v, err := strconv.ParseUint(sigV, 0, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid v %q", commonPrivateKey.ErrInvalidSignature, sigV)
+	}
+	sig, err := commonPrivateKey.ParseSignature(rs)
+	if err != nil {
+		return nil, err
+	}
+	if sig.V, err = commonPrivateKey.RecoveryID(v); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+func init() {
+	ecrecoverCmd.Flags().StringVar(&signatureHash, "hash", "", "recover from this 32-byte hash, hex, instead of an EIP-191 message")
+	ecrecoverCmd.Flags().BoolVar(&messageHex, "hex", false, "the message is hex")
+	ecrecoverCmd.Flags().StringVarP(&messageFile, "input", "i", "", "read the message from a file (default: the argument, or stdin)")
+	ecrecoverCmd.Flags().StringVar(&sigR, "r", "", "signature r, hex (with --s and --v)")
+	ecrecoverCmd.Flags().StringVar(&sigS, "s", "", "signature s, hex (with --r and --v)")
+	ecrecoverCmd.Flags().StringVar(&sigV, "v", "", "signature v: 0/1, 27/28 or EIP-155, decimal or 0x hex (with --r and --s)")
+	ecrecoverCmd.Flags().StringVar(&chainName, "chain", "", "print the address of a secp256k1 chain: "+strings.Join(secp256k1ChainNames(), ", "))
+}
@@ -1,23 +1,42 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"os/signal"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/spark8899/gowallet/internal/commonPrivateKey"
+	"github.com/spark8899/gowallet/internal/hdwallet"
+	"github.com/spark8899/gowallet/internal/paperwallet"
+	"github.com/spark8899/gowallet/internal/security"
 	"github.com/spf13/cobra"
 )
 
 var number int
 var privateKey string
+var outputFile string
+var genWorkers int
+var showProgress bool
+var dictionaryFile string
+var showQR bool
+var qrASCII bool
+var compressedKey bool
+var keystoreFile string
+var passwordFile string
 
 var genPrivateKeyCmd = &cobra.Command{
-	Use:     "genPrivateKey [count]",
-	Short:   "Generate a new random private key",
-	Long:    "Generate a secure, random private key using crypto/rand.",
-	Example: `  gowallet genPrivateKey 5`,
+	Use:   "genPrivateKey [count]",
+	Short: "Generate a new random private key",
+	Long:  "Generate secure, random private keys using crypto/rand. Keys are generated concurrently\nand streamed to stdout or a file, one address:privateKey line per key.",
+	Example: `  gowallet genPrivateKey 5
+  gowallet genPrivateKey -n 50000 -o keys.txt --progress`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) > 0 {
 			if args[0] == "help" {
@@ -29,15 +48,57 @@ var genPrivateKeyCmd = &cobra.Command{
 				number = n
 			}
 		}
-		commonPrivateKey.GetGenerateKey(number)
+
+		var w io.Writer = os.Stdout
+		if outputFile != "" {
+			f, err := os.OpenFile(outputFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer f.Close()
+			w = f
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		opts := commonPrivateKey.GenerateOptions{Workers: genWorkers, Rand: randomSource()}
+		if showProgress {
+			start := time.Now()
+			last := start
+			opts.Progress = func(done, total int) {
+				if now := time.Now(); now.Sub(last) >= time.Second || done == total {
+					last = now
+					fmt.Fprintf(os.Stderr, "\rGenerated %d/%d keys (%.0f keys/s)", done, total, float64(done)/now.Sub(start).Seconds())
+				}
+			}
+		}
+
+		err := commonPrivateKey.GetGenerateKey(ctx, w, number, opts)
+		if showProgress {
+			fmt.Fprintln(os.Stderr)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
 	},
 }
 
 var getAddressCmd = &cobra.Command{
-	Use:     "getAddress [private_key]",
-	Short:   "Derive a wallet address from a private key",
-	Long:    "Derive a wallet address from a given private key.",
-	Example: `  gowallet getAddress <private_key_hex>`,
+	Use:   "getAddress [private_key]",
+	Short: "Derive a wallet address from a private key",
+	Long: "Derive a wallet address from a given private key (Ethereum, or another secp256k1 chain with --chain).\n" +
+		"The key may be hex, WIF or an Ethereum keystore file. Without --chain, a WIF key gives the address on\n" +
+		"the chain of its version byte, and an uncompressed WIF key its P2PKH address of the uncompressed key.\n" +
+		"Warns on stderr if the key is a known test key, a brainwallet or has a suspicious bit pattern.",
+	Example: `  gowallet getAddress <private_key_hex>
+  gowallet getAddress --chain tron <private_key_hex>
+  gowallet getAddress --chain osmosis <private_key_hex>
+  gowallet getAddress --chain dogecoin <private_key_hex>
+  gowallet getAddress L1aW4aubDFB7yfras2S1mN3bqg9nwySY8nkoLmJebSLD5BWv3ENZ
+  gowallet getAddress --keystore UTC--2024-01-01T00-00-00Z--f39fd6e5... --password-file pass.txt
+  gowallet getAddress --dictionary passwords.txt <private_key_hex>
+  gowallet getAddress --qr <private_key_hex>`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) > 0 {
 			if args[0] == "help" {
@@ -46,23 +107,45 @@ var getAddressCmd = &cobra.Command{
 			}
 			privateKey = args[0]
 		}
-		if privateKey == "" {
-			fmt.Println("Error: Private key is required. Provide it as an argument or use -k flag.")
+		if privateKey == "" && keystoreFile == "" {
+			fmt.Println("Error: Private key is required. Provide it as an argument, use -k flag or --keystore.")
 			os.Exit(1)
 		}
-		address, err := commonPrivateKey.AddressHex(privateKey)
+		secret := keySecret()
+		defer secret.Destroy()
+		address, err := chainAddress(secret)
 		if err != nil {
 			log.Fatal(err)
 		}
+		if dictionaryFile != "" {
+			f, err := os.Open(dictionaryFile)
+			if err != nil {
+				log.Fatal(err)
+			}
+			err = commonPrivateKey.LoadBrainwalletDictionary(f)
+			f.Close()
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+		warnWeakKey(secret)
 		fmt.Println(address)
+		if showQR || qrASCII {
+			code, err := paperwallet.NewQR(address)
+			if err != nil {
+				log.Fatal(err)
+			}
+			fmt.Print(code.Terminal(qrASCII))
+		}
 	},
 }
 
 var getPublicKeyCmd = &cobra.Command{
-	Use:     "getPublicKey [private_key]",
-	Short:   "Derive a public key from a private key",
-	Long:    "Derive a public key from a given private key.",
-	Example: `  gowallet getPublicKey <private_key_hex>`,
+	Use:   "getPublicKey [private_key]",
+	Short: "Derive a public key from a private key",
+	Long:  "Derive a public key from a given private key, uncompressed (65 bytes) by default.",
+	Example: `  gowallet getPublicKey <private_key_hex>
+  gowallet getPublicKey --compressed <private_key_hex>`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) > 0 {
 			if args[0] == "help" {
@@ -71,11 +154,17 @@ var getPublicKeyCmd = &cobra.Command{
 			}
 			privateKey = args[0]
 		}
-		if privateKey == "" {
-			fmt.Println("Error: Private key is required. Provide it as an argument or use -k flag.")
+		if privateKey == "" && keystoreFile == "" {
+			fmt.Println("Error: Private key is required. Provide it as an argument, use -k flag or --keystore.")
 			os.Exit(1)
 		}
-		publicKey, err := commonPrivateKey.PublicKeyHex(privateKey)
+		secret := keySecret()
+		defer secret.Destroy()
+		publicKeyHex := commonPrivateKey.PublicKeyHex
+		if compressedKey || compressedWIF(secret) {
+			publicKeyHex = commonPrivateKey.CompressedPublicKeyHex
+		}
+		publicKey, err := publicKeyHex(secret)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -83,8 +172,133 @@ var getPublicKeyCmd = &cobra.Command{
 	},
 }
 
+// chainAddress returns the address of the private key in secret on the
+// --chain chain, Ethereum by default. Without --chain, a WIF key selects
+// the chain of its version byte.
+func chainAddress(secret *security.SecretBuffer) (string, error) {
+	raw, version, compressed, err := commonPrivateKey.DecodeWIF(secret)
+	if err != nil {
+		// hex, or a malformed WIF whose error PublicKeyBytes reports below
+		compressed = true
+	} else {
+		raw.Destroy()
+	}
+	if chainName == "" && err != nil {
+		return commonPrivateKey.AddressHex(secret)
+	}
+
+	var chain *hdwallet.Chain
+	if chainName == "" {
+		if chain, err = hdwallet.ChainForWIF(version, selectedNetwork()); err == nil && addressType != "" {
+			chain, err = chain.WithAddressType(hdwallet.AddressType(addressType))
+		}
+	} else {
+		chain, err = selectedChain()
+	}
+	if err != nil {
+		return "", err
+	}
+
+	publicKey, err := commonPrivateKey.PublicKeyBytes(secret)
+	if err != nil {
+		return "", err
+	}
+	pub, err := btcec.ParsePubKey(publicKey)
+	if err != nil {
+		return "", err
+	}
+	if !compressed && chain.UTXO != nil {
+		t := hdwallet.AddressType(addressType)
+		if addressType == "" {
+			t = hdwallet.P2PKH
+		}
+		return chain.UTXO.UncompressedAddress(pub, t)
+	}
+	return chain.Address(pub), nil
+}
+
+// compressedWIF reports whether secret is a WIF key for the compressed
+// public key
+func compressedWIF(secret *security.SecretBuffer) bool {
+	raw, _, compressed, err := commonPrivateKey.DecodeWIF(secret)
+	if err != nil {
+		return false
+	}
+	raw.Destroy()
+	return compressed
+}
+
+// keySecret returns the private key given as hex or WIF (argument or -k),
+// or decrypted from the --keystore file with the password in
+// --password-file
+func keySecret() *security.SecretBuffer {
+	if keystoreFile == "" {
+		return secretArg(privateKey)
+	}
+	if passwordFile == "" {
+		fmt.Println("Error: --password-file is required with --keystore.")
+		os.Exit(1)
+	}
+	keyJSON, err := os.ReadFile(keystoreFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	data, err := os.ReadFile(passwordFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	password, err := security.NewSecretBufferFrom(data)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer password.Destroy()
+	secret, err := commonPrivateKey.DecryptKeystore(keyJSON, password)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return secret
+}
+
+// pubKeyAddress returns the address of pub on the --chain chain, Ethereum
+// by default
+func pubKeyAddress(pub *btcec.PublicKey) (string, error) {
+	if chainName == "" {
+		return hdwallet.Ethereum.Address(pub), nil
+	}
+	chain, err := selectedChain()
+	if err != nil {
+		return "", err
+	}
+	return chain.Address(pub), nil
+}
+
+// warnWeakKey prints a warning to stderr for each known weakness of the key
+func warnWeakKey(privateKey *security.SecretBuffer) {
+	weaknesses, err := commonPrivateKey.CheckWeakKeyHex(privateKey)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, w := range weaknesses {
+		fmt.Fprintf(os.Stderr, "WARNING: weak private key (%s). Do not fund this address.\n", w)
+	}
+}
+
 func init() {
 	genPrivateKeyCmd.Flags().IntVarP(&number, "num", "n", 1, "generated quantity")
-	getAddressCmd.Flags().StringVarP(&privateKey, "key", "k", "", "private key")
-	getPublicKeyCmd.Flags().StringVarP(&privateKey, "key", "k", "", "private key")
+	genPrivateKeyCmd.Flags().StringVarP(&outputFile, "output", "o", "", "write keys to file (created with mode 0600) instead of stdout")
+	genPrivateKeyCmd.Flags().IntVarP(&genWorkers, "workers", "w", 0, "number of worker goroutines (default: GOMAXPROCS)")
+	genPrivateKeyCmd.Flags().BoolVar(&showProgress, "progress", false, "report progress on stderr")
+	getAddressCmd.Flags().StringVar(&dictionaryFile, "dictionary", "", "extra brainwallet passphrases to check, one per line")
+	getAddressCmd.Flags().StringVarP(&privateKey, "key", "k", "", "private key, hex or WIF")
+	getAddressCmd.Flags().StringVar(&keystoreFile, "keystore", "", "read the private key from an Ethereum keystore (v3) file")
+	getAddressCmd.Flags().StringVar(&passwordFile, "password-file", "", "file holding the keystore password")
+	getAddressCmd.Flags().StringVar(&chainName, "chain", "", "address format of a secp256k1 chain: "+strings.Join(secp256k1ChainNames(), ", "))
+	getAddressCmd.Flags().StringVar(&bech32HRP, "hrp", "", "bech32 address prefix replacing the Cosmos SDK chain's own (e.g. juno)")
+	getAddressCmd.Flags().StringVar(&addressType, "address-type", "", "bitcoin, litecoin: p2pkh, p2sh (nested SegWit) or p2wpkh (default); bitcoin also p2tr (Taproot)")
+	getAddressCmd.Flags().BoolVar(&showQR, "qr", false, "print the address as a QR code (Unicode, for dark terminals)")
+	getAddressCmd.Flags().BoolVar(&qrASCII, "qr-ascii", false, "print the address as an ASCII QR code (for light backgrounds)")
+	getPublicKeyCmd.Flags().StringVarP(&privateKey, "key", "k", "", "private key, hex or WIF")
+	getPublicKeyCmd.Flags().StringVar(&keystoreFile, "keystore", "", "read the private key from an Ethereum keystore (v3) file")
+	getPublicKeyCmd.Flags().StringVar(&passwordFile, "password-file", "", "file holding the keystore password")
+	getPublicKeyCmd.Flags().BoolVar(&compressedKey, "compressed", false, "print the 33-byte compressed key")
 }
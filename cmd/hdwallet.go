@@ -1,14 +1,18 @@
 package cmd
 
 import (
+	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"strconv"
 	"strings"
 
 	"github.com/spark8899/gowallet/internal/hdwallet"
+	"github.com/spark8899/gowallet/internal/security"
 	"github.com/spf13/cobra"
 )
 
@@ -16,12 +20,26 @@ var size int
 var mnemonicStr string
 var seedStr string
 var path string
+var verbose bool
+var showWIF bool
+var diceRolls string
+var coinFlips string
+var entropyHex string
+var xorRandom bool
+var chainName string
+var bech32HRP string
+var addressType string
 
 var genMnemonicCmd = &cobra.Command{
-	Use:     "genMnemonic [size]",
-	Short:   "Generate a BIP39 mnemonic phrase",
-	Long:    "Generate a BIP39 mnemonic phrase. Supported sizes: 12, 15, 18, 21, 24 words.",
-	Example: `  gowallet genMnemonic 12`,
+	Use:   "genMnemonic [size]",
+	Short: "Generate a BIP39 mnemonic phrase",
+	Long: "Generate a BIP39 mnemonic phrase. Supported sizes: 12, 15, 18, 21, 24 words.\n" +
+		"Instead of crypto/rand, entropy can come from dice rolls (Coldcard method: SHA256 of the rolls),\n" +
+		"coin flips or raw hex, optionally XORed with crypto/rand.",
+	Example: `  gowallet genMnemonic 12
+  gowallet genMnemonic 24 --dice "3561 2245 ..."   (at least 100 rolls for 24 words)
+  gowallet genMnemonic 12 --coin "HTTHHT..." --xor-rand
+  gowallet genMnemonic --entropy-hex 00112233445566778899aabbccddeeff`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) > 0 {
 			if args[0] == "help" {
@@ -34,11 +52,45 @@ var genMnemonicCmd = &cobra.Command{
 			}
 		}
 		bitSize := size*11 - size/3
-		mnemonic, err := hdwallet.Bip39GenMnemonic(bitSize)
+
+		var entropy []byte
+		var contributed float64
+		var err error
+		switch {
+		case diceRolls != "":
+			entropy, contributed, err = hdwallet.DiceEntropy(diceRolls, bitSize)
+		case coinFlips != "":
+			entropy, contributed, err = hdwallet.CoinEntropy(coinFlips, bitSize)
+		case entropyHex != "":
+			entropy, contributed, err = hdwallet.HexEntropy(entropyHex)
+		default:
+			mnemonic, err := hdwallet.Bip39GenMnemonic(bitSize, randomSource())
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer mnemonic.Destroy()
+			writeSecretLine(mnemonic)
+			return
+		}
 		if err != nil {
 			log.Fatal(err)
 		}
-		fmt.Println(mnemonic)
+		fmt.Fprintf(os.Stderr, "User entropy: %.1f bits contributed, %d bits used\n", contributed, len(entropy)*8)
+
+		var mix io.Reader
+		if xorRandom {
+			mix = randomSource()
+			if mix == nil {
+				mix = rand.Reader
+			}
+			fmt.Fprintln(os.Stderr, "User entropy XORed with random entropy")
+		}
+		mnemonic, err := hdwallet.MnemonicFromUserEntropy(entropy, mix)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer mnemonic.Destroy()
+		writeSecretLine(mnemonic)
 	},
 }
 
@@ -59,26 +111,46 @@ var mnToSeedCmd = &cobra.Command{
 			fmt.Println("Error: Mnemonic is required. Provide it as an argument or use -m flag.")
 			os.Exit(1)
 		}
-		seedBts, err := hdwallet.Bip39MnemonicToSeed(mnemonicStr, "")
+		mnemonic := secretArg(mnemonicStr)
+		defer mnemonic.Destroy()
+		seed, err := hdwallet.Bip39MnemonicToSeed(mnemonic, "")
 		if err != nil {
 			log.Fatal(err)
 		}
-		fmt.Println(hex.EncodeToString(seedBts))
+		defer seed.Destroy()
+		seedHex, err := security.NewSecretBuffer(hex.EncodedLen(seed.Len()))
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer seedHex.Destroy()
+		hex.Encode(seedHex.Bytes(), seed.Bytes())
+		writeSecretLine(seedHex)
 	},
 }
 
 var getPathCmd = &cobra.Command{
 	Use:   "getPath",
 	Short: "Derive keys/addresses from a derivation path",
-	Long:  "Derive private key or address from a mnemonic or seed using a derivation path (e.g., m/44'/60'/0'/0/0).",
+	Long: "Derive private key or address from a mnemonic or seed using a derivation path (e.g., m/44'/60'/0'/0/0).\n" +
+		"With --wif, print the P2PKH address and WIF key for --network instead of the EVM address and hex key.\n" +
+		"With --chain, print the account in that chain's address and private key formats (WIF for the\n" +
+		"Bitcoin-family chains); -p then defaults to the chain's standard path. ed25519 chains are derived\n" +
+		"with SLIP-10 (hardened levels only).\n" +
+		"Chains: " + strings.Join(chainNames(), ", ") + ".",
 	Example: `  gowallet getPath -m "apple banana ..." -p "m/44'/60'/0'/0/0"
-  gowallet getPath -s <seed_hex> -p "m/44'/60'/0'/0/0"`,
+  gowallet getPath -s <seed_hex> -p "m/44'/60'/0'/0/0"
+  gowallet --network testnet3 getPath -m "apple banana ..." -p "m/44'/1'/0'/0/0" -v
+  gowallet getPath -m "apple banana ..." -p "m/44'/0'/0'/0/0" --wif
+  gowallet getPath --chain solana -m "apple banana ..." -p "m/44'/501'/1'/0'"
+  gowallet getPath --chain tron -m "apple banana ..."
+  gowallet getPath --chain cosmos --hrp juno -m "apple banana ..."
+  gowallet getPath --chain litecoin --address-type p2sh -m "apple banana ..."`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) > 0 && args[0] == "help" {
 			cmd.Help()
 			os.Exit(0)
 		}
-		if path == "" {
+		if path == "" && chainName == "" {
 			fmt.Println("Error: Path is required. Use -p flag.")
 			os.Exit(1)
 		}
@@ -86,56 +158,250 @@ var getPathCmd = &cobra.Command{
 			fmt.Println("Error: Either seed (-s) or mnemonic (-m) is required.")
 			os.Exit(1)
 		}
+		if chainName != "" {
+			getChainPath()
+			return
+		}
+
+		net := selectedNetwork()
 
 		if mnemonicStr != "" {
-			KeyInfo, err := hdwallet.PathFromMnemonic(mnemonicStr, path)
+			mnemonic := secretArg(mnemonicStr)
+			defer mnemonic.Destroy()
+			warnPublicMnemonic(mnemonic)
+			keyInfo, err := hdwallet.PathFromMnemonic(mnemonic, path, net)
 			if err != nil {
 				log.Fatal(err)
 			}
-			fmt.Println(KeyInfo)
+			defer keyInfo.Destroy()
+			warnWeakKey(keyInfo.PrivateKey)
+			printKeyInfo(keyInfo)
 		}
 
 		if seedStr != "" {
-			privateKeyInfo, err := hdwallet.PathFromSeed(seedStr, path)
+			seed := secretArg(seedStr)
+			defer seed.Destroy()
+			keyInfo, err := hdwallet.PathFromSeed(seed, path, net)
 			if err != nil {
 				log.Fatal(err)
 			}
-			fmt.Println(privateKeyInfo)
+			defer keyInfo.Destroy()
+			warnWeakKey(keyInfo.PrivateKey)
+			printKeyInfo(keyInfo)
 		}
 	},
 }
 
 var seedToMnCmd = &cobra.Command{
-	Use:     "seedToMn [seed_hex]",
-	Short:   "Generate a mnemonic from a seed (entropy) hex string",
-	Long:    "Generate a BIP39 mnemonic phrase from a provided seed/entropy hex string.",
-	Example: `  gowallet seedToMn <seed_hex>`,
+	Use:   "seedToMn [seed_hex]",
+	Short: "Deprecated: a seed cannot be converted back to a mnemonic",
+	Long: "A BIP39 seed is a one-way PBKDF2 hash of the mnemonic and passphrase, so it cannot be\n" +
+		"turned back into the mnemonic. Use entropyToMnemonic with the 16-32 byte entropy instead.",
+	Example: `  gowallet entropyToMnemonic <entropy_hex>`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 && args[0] == "help" {
+			cmd.Help()
+			os.Exit(0)
+		}
+		if _, err := hdwallet.MnemonicFromSeed(seedStr); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var entropyToMnemonicCmd = &cobra.Command{
+	Use:     "entropyToMnemonic [entropy_hex]",
+	Aliases: []string{"entropyToMn"},
+	Short:   "Encode 16-32 bytes of entropy as a BIP39 mnemonic",
+	Long:    "Encode 16, 20, 24, 28 or 32 bytes of hex entropy as a 12, 15, 18, 21 or 24 word BIP39 mnemonic.",
+	Example: `  gowallet entropyToMnemonic 00000000000000000000000000000000
+  gowallet entropyToMnemonic -e 9e885d952ad362caeb4efe34a8e91bd2`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			if args[0] == "help" {
+				cmd.Help()
+				os.Exit(0)
+			}
+			entropyHex = args[0]
+		}
+		if entropyHex == "" {
+			fmt.Println("Error: Entropy is required. Provide it as an argument or use -e flag")
+			os.Exit(1)
+		}
+
+		entropy := secretArg(entropyHex)
+		defer entropy.Destroy()
+		mnemonic, err := hdwallet.EntropyToMnemonic(entropy)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer mnemonic.Destroy()
+		writeSecretLine(mnemonic)
+	},
+}
+
+var mnemonicToEntropyCmd = &cobra.Command{
+	Use:     "mnemonicToEntropy [mnemonic]",
+	Aliases: []string{"mnToEntropy"},
+	Short:   "Decode a BIP39 mnemonic back to its entropy",
+	Long:    "Decode a BIP39 mnemonic back to its entropy hex, verifying the word list and checksum.",
+	Example: `  gowallet mnemonicToEntropy "abandon abandon ... about"
+  gowallet mnemonicToEntropy -m "abandon abandon ... about"`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) > 0 {
 			if args[0] == "help" {
 				cmd.Help()
 				os.Exit(0)
 			}
-			seedStr = args[0]
+			mnemonicStr = strings.Join(args, " ")
 		}
-		if seedStr == "" {
-			fmt.Println("Error: Seed is required. Provide it as an argument or use -s flag")
+		if mnemonicStr == "" {
+			fmt.Println("Error: Mnemonic is required. Provide it as an argument or use -m flag.")
 			os.Exit(1)
 		}
 
-		mnemonicInfo, err := hdwallet.MnemonicFromSeed(seedStr)
+		mnemonic := secretArg(mnemonicStr)
+		defer mnemonic.Destroy()
+		entropy, err := hdwallet.MnemonicToEntropyHex(mnemonic)
 		if err != nil {
 			log.Fatal(err)
 		}
-		fmt.Println(mnemonicInfo)
+		defer entropy.Destroy()
+		writeSecretLine(entropy)
 	},
 }
 
+// chainKeyInfo is the output of getPath --chain for either key type
+type chainKeyInfo interface {
+	WriteTo(w io.Writer) (int64, error)
+	WriteDetails(w io.Writer) (int64, error)
+	Destroy()
+}
+
+// getChainPath runs getPath for the secp256k1 or ed25519 chain --chain
+func getChainPath() {
+	var keyInfo chainKeyInfo
+	if chain, err := selectedChain(); err == nil {
+		if showWIF && chain.UTXO == nil {
+			log.Fatalf("--wif needs a Bitcoin-family chain; %s keys are not WIF", chain.Name)
+		}
+		net := selectedNetwork()
+		if mnemonicStr != "" {
+			mnemonic := secretArg(mnemonicStr)
+			defer mnemonic.Destroy()
+			warnPublicMnemonic(mnemonic)
+			keyInfo, err = hdwallet.ChainPathFromMnemonic(mnemonic, path, chain, net)
+		} else {
+			seed := secretArg(seedStr)
+			defer seed.Destroy()
+			keyInfo, err = hdwallet.ChainPathFromSeed(seed, path, chain, net)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+	} else if !errors.Is(err, hdwallet.ErrUnknownChain) {
+		log.Fatal(err)
+	} else {
+		chain, err := hdwallet.Ed25519ChainByName(chainName)
+		if err != nil {
+			log.Fatalf("%v (supported: %s)", err, strings.Join(chainNames(), ", "))
+		}
+		if showWIF {
+			log.Fatalf("--wif needs a Bitcoin-family chain; %s keys are not WIF", chain.Name)
+		}
+		if mnemonicStr != "" {
+			mnemonic := secretArg(mnemonicStr)
+			defer mnemonic.Destroy()
+			warnPublicMnemonic(mnemonic)
+			keyInfo, err = hdwallet.Ed25519PathFromMnemonic(mnemonic, path, chain)
+		} else {
+			seed := secretArg(seedStr)
+			defer seed.Destroy()
+			keyInfo, err = hdwallet.Ed25519PathFromSeed(seed, path, chain)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	defer keyInfo.Destroy()
+	if verbose {
+		keyInfo.WriteDetails(os.Stdout)
+	} else {
+		keyInfo.WriteTo(os.Stdout)
+	}
+	fmt.Println()
+}
+
+// selectedChain returns the secp256k1 chain --chain, with the bech32 prefix
+// replaced by --hrp and the address type by --address-type if set
+func selectedChain() (*hdwallet.Chain, error) {
+	chain, err := hdwallet.ChainForNetwork(chainName, selectedNetwork())
+	if err != nil {
+		return nil, err
+	}
+	if bech32HRP != "" {
+		if chain, err = chain.WithHRP(bech32HRP); err != nil {
+			return nil, err
+		}
+	}
+	if addressType != "" {
+		return chain.WithAddressType(hdwallet.AddressType(addressType))
+	}
+	return chain, nil
+}
+
+func secp256k1ChainNames() []string {
+	names := []string{"bitcoin"}
+	for _, c := range hdwallet.Chains {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+// chainNames lists the secp256k1 chains followed by the ed25519 chains
+func chainNames() []string {
+	names := secp256k1ChainNames()
+	for _, c := range hdwallet.Ed25519Chains {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+// warnPublicMnemonic prints a warning to stderr if the mnemonic is publicly known
+func warnPublicMnemonic(mnemonic *security.SecretBuffer) {
+	if source, ok := hdwallet.CheckPublicMnemonic(mnemonic); ok {
+		fmt.Fprintf(os.Stderr, "WARNING: publicly known mnemonic (%s). Do not fund its addresses.\n", source)
+	}
+}
+
+func printKeyInfo(keyInfo *hdwallet.KeyInfo) {
+	if verbose {
+		keyInfo.WriteDetails(os.Stdout)
+	} else if showWIF {
+		keyInfo.WriteWIFTo(os.Stdout)
+	} else {
+		keyInfo.WriteTo(os.Stdout)
+	}
+	fmt.Println()
+}
+
 func init() {
 	genMnemonicCmd.Flags().IntVarP(&size, "size", "s", 12, "size is the word number of mnemonic, support: 12, 15, 18, 21, 24")
+	genMnemonicCmd.Flags().StringVar(&diceRolls, "dice", "", "use dice rolls (digits 1-6) as entropy")
+	genMnemonicCmd.Flags().StringVar(&coinFlips, "coin", "", "use coin flips (0/1 or H/T) as entropy")
+	genMnemonicCmd.Flags().StringVar(&entropyHex, "entropy-hex", "", "use raw hex entropy (16-32 bytes); the size is taken from its length")
+	genMnemonicCmd.Flags().BoolVar(&xorRandom, "xor-rand", false, "XOR user entropy with crypto/rand")
+	genMnemonicCmd.MarkFlagsMutuallyExclusive("dice", "coin", "entropy-hex")
 	mnToSeedCmd.Flags().StringVarP(&mnemonicStr, "mnemonic", "m", "", "mnemonic is mnemonic string")
 	getPathCmd.Flags().StringVarP(&seedStr, "seed", "s", "", "seed is string")
 	getPathCmd.Flags().StringVarP(&path, "path", "p", "", "path is string, For example \"m/44'/60'/0'/0/0\"")
 	getPathCmd.Flags().StringVarP(&mnemonicStr, "mnemonic", "m", "", "mnemonic is mnemonic string")
+	getPathCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "print network, WIF, BTC address and extended keys")
+	getPathCmd.Flags().BoolVar(&showWIF, "wif", false, "print \"btcAddress:wif\" (P2PKH, compressed WIF for --network)")
+	getPathCmd.Flags().StringVar(&chainName, "chain", "", "print the account for this chain: "+strings.Join(chainNames(), ", "))
+	getPathCmd.Flags().StringVar(&bech32HRP, "hrp", "", "bech32 address prefix replacing the Cosmos SDK chain's own (e.g. juno)")
+	getPathCmd.Flags().StringVar(&addressType, "address-type", "", "bitcoin, litecoin: p2pkh, p2sh (nested SegWit) or p2wpkh, bitcoin also p2tr (Taproot); -p then defaults to the BIP44/49/84/86 path")
 	seedToMnCmd.Flags().StringVarP(&seedStr, "seed", "s", "", "seed is string")
+	entropyToMnemonicCmd.Flags().StringVarP(&entropyHex, "entropy", "e", "", "entropy hex, 16-32 bytes")
+	mnemonicToEntropyCmd.Flags().StringVarP(&mnemonicStr, "mnemonic", "m", "", "mnemonic is mnemonic string")
 }
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/spark8899/gowallet/internal/commonPrivateKey"
+	"github.com/spark8899/gowallet/internal/hdwallet"
+	"github.com/spark8899/gowallet/internal/paperwallet"
+	"github.com/spark8899/gowallet/internal/security"
+	"github.com/spf13/cobra"
+)
+
+var paperFormat string
+var paperPath string
+
+var paperWalletCmd = &cobra.Command{
+	Use:   "paperWallet",
+	Short: "Write a printable paper wallet with QR codes",
+	Long: "Write a self-contained HTML, SVG or PDF paper wallet with the address, private key,\n" +
+		"mnemonic and derivation path, each with a QR code. Without -k or -m a new mnemonic is generated.\n" +
+		"Everything is rendered locally; the file is created with mode 0600.",
+	Example: `  gowallet paperWallet -o wallet.pdf
+  gowallet paperWallet -m "apple banana ..." -p "m/44'/60'/0'/0/1" -o wallet.html
+  gowallet paperWallet -k <private_key_hex> -o wallet.svg`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 && args[0] == "help" {
+			cmd.Help()
+			os.Exit(0)
+		}
+		if outputFile == "" {
+			fmt.Println("Error: Output file is required. Use -o flag.")
+			os.Exit(1)
+		}
+
+		format, err := paperwallet.FormatFromPath(outputFile)
+		if paperFormat != "" {
+			format, err = paperwallet.ParseFormat(paperFormat)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		net := selectedNetwork()
+		wallet := &paperwallet.Wallet{Network: net.Name, Created: time.Now()}
+
+		if privateKey != "" {
+			secret := secretArg(privateKey)
+			defer secret.Destroy()
+			address, err := commonPrivateKey.AddressHex(secret)
+			if err != nil {
+				log.Fatal(err)
+			}
+			keyHex, err := commonPrivateKey.PrivateKeyHex(secret)
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer keyHex.Destroy()
+			warnWeakKey(secret)
+			wallet.Address = address
+			wallet.PrivateKey = keyHex
+		} else {
+			var mnemonic *security.SecretBuffer
+			if mnemonicStr == "" {
+				if mnemonic, err = hdwallet.Bip39GenMnemonic(size*11-size/3, randomSource()); err != nil {
+					log.Fatal(err)
+				}
+			} else {
+				mnemonic = secretArg(mnemonicStr)
+				warnPublicMnemonic(mnemonic)
+			}
+			defer mnemonic.Destroy()
+			keyInfo, err := hdwallet.PathFromMnemonic(mnemonic, paperPath, net)
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer keyInfo.Destroy()
+			warnWeakKey(keyInfo.PrivateKey)
+			wallet.Address = keyInfo.Address
+			wallet.PrivateKey = keyInfo.PrivateKey
+			wallet.Mnemonic = mnemonic
+			wallet.Path = paperPath
+		}
+
+		f, err := os.OpenFile(outputFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := paperwallet.Render(f, wallet, format); err != nil {
+			f.Close()
+			log.Fatal(err)
+		}
+		if err := f.Close(); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Fprintf(os.Stderr, "Paper wallet written to %s (%s)\n", outputFile, format)
+		fmt.Println(wallet.Address)
+	},
+}
+
+func init() {
+	paperWalletCmd.Flags().StringVarP(&outputFile, "output", "o", "", "output file; the format follows the extension (.html, .svg, .pdf)")
+	paperWalletCmd.Flags().StringVar(&paperFormat, "format", "", "output format overriding the extension: html, svg or pdf")
+	paperWalletCmd.Flags().StringVarP(&privateKey, "key", "k", "", "private key")
+	paperWalletCmd.Flags().StringVarP(&mnemonicStr, "mnemonic", "m", "", "mnemonic is mnemonic string")
+	paperWalletCmd.Flags().StringVarP(&paperPath, "path", "p", "m/44'/60'/0'/0/0", "derivation path used with a mnemonic")
+	paperWalletCmd.Flags().IntVar(&size, "size", 12, "words in a generated mnemonic: 12, 15, 18, 21, 24")
+	paperWalletCmd.MarkFlagsMutuallyExclusive("key", "mnemonic")
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/spark8899/gowallet/internal/commonPrivateKey"
+	"github.com/spark8899/gowallet/internal/hdwallet"
+	"github.com/spf13/cobra"
+)
+
+var uncompressedKey bool
+
+var convertPrivKeyCmd = &cobra.Command{
+	Use:   "convertPrivKey [private_key]",
+	Short: "Convert a private key between hex and WIF",
+	Long: "Convert a private key between hex and Wallet Import Format (WIF). Without --to, WIF input is\n" +
+		"printed as hex and anything else as WIF. The WIF version byte is that of --chain (a Bitcoin-family\n" +
+		"chain), Bitcoin on --network by default; keys are compressed unless --uncompressed is set.\n" +
+		"The input may also be an Ethereum keystore file (--keystore).",
+	Example: `  gowallet convertPrivKey <private_key_hex>
+  gowallet --network testnet4 convertPrivKey <private_key_hex>
+  gowallet convertPrivKey --chain litecoin <private_key_hex>
+  gowallet convertPrivKey --uncompressed <private_key_hex>
+  gowallet convertPrivKey <wif>
+  gowallet convertPrivKey --to wif --keystore UTC--... --password-file pass.txt`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			if args[0] == "help" {
+				cmd.Help()
+				os.Exit(0)
+			}
+			privateKey = args[0]
+		}
+		if privateKey == "" && keystoreFile == "" {
+			fmt.Println("Error: Private key is required. Provide it as an argument, use -k flag or --keystore.")
+			os.Exit(1)
+		}
+		secret := keySecret()
+		defer secret.Destroy()
+
+		to := strings.ToLower(convertTo)
+		if to == "" {
+			to = "wif"
+			if commonPrivateKey.IsWIF(secret) {
+				to = "hex"
+			}
+		}
+		switch to {
+		case "hex":
+			out, err := commonPrivateKey.PrivateKeyHex(secret)
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer out.Destroy()
+			writeSecretLine(out)
+		case "wif":
+			chain := hdwallet.BitcoinChain(selectedNetwork())
+			if chainName != "" {
+				var err error
+				if chain, err = selectedChain(); err != nil {
+					log.Fatal(err)
+				}
+			}
+			if chain.UTXO == nil {
+				log.Fatalf("%s has no WIF keys (supported: bitcoin, litecoin, dogecoin, dash, bitcoincash)", chain.Name)
+			}
+			out, err := commonPrivateKey.EncodeWIF(secret, chain.UTXO.PrivateKeyID, !uncompressedKey)
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer out.Destroy()
+			writeSecretLine(out)
+		default:
+			log.Fatalf("unknown private key form %q (want hex or wif)", convertTo)
+		}
+	},
+}
+
+func init() {
+	convertPrivKeyCmd.Flags().StringVarP(&privateKey, "key", "k", "", "private key, hex or WIF")
+	convertPrivKeyCmd.Flags().StringVar(&keystoreFile, "keystore", "", "read the private key from an Ethereum keystore (v3) file")
+	convertPrivKeyCmd.Flags().StringVar(&passwordFile, "password-file", "", "file holding the keystore password")
+	convertPrivKeyCmd.Flags().StringVar(&convertTo, "to", "", "output form: hex or wif")
+	convertPrivKeyCmd.Flags().StringVar(&chainName, "chain", "", "WIF version byte of a Bitcoin-family chain: bitcoin (default), litecoin, dogecoin, dash, bitcoincash")
+	convertPrivKeyCmd.Flags().BoolVar(&uncompressedKey, "uncompressed", false, "encode a WIF key for the uncompressed public key")
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/spark8899/gowallet/internal/hdwallet"
+	"github.com/spf13/cobra"
+)
+
+var allChains bool
+
+var convertPubKeyCmd = &cobra.Command{
+	Use:   "convertPubKey [public_key]",
+	Short: "Convert a public key between compressed, uncompressed, raw and x-only forms",
+	Long: "Convert a secp256k1 public key between its compressed (33 bytes), uncompressed (65 bytes), raw\n" +
+		"X||Y (64 bytes) and BIP340 x-only (32 bytes) hex forms. The input may be any of these but x-only,\n" +
+		"which lacks the parity of y, or an extended public key (xpub, ypub, zpub, tpub, ...), whose key\n" +
+		"at its own depth is used.",
+	Example: `  gowallet convertPubKey 0x04...
+  gowallet convertPubKey --to compressed 0x04...
+  gowallet convertPubKey xpub6C...`,
+	Run: func(cmd *cobra.Command, args []string) {
+		pub := pubKeyArg(cmd, args)
+		forms := hdwallet.PublicKeyForms(pub)
+		if convertTo == "" {
+			for _, f := range forms {
+				fmt.Printf("%s: %s\n", strings.ToUpper(f.Name[:1])+f.Name[1:], f.Hex)
+			}
+			return
+		}
+		for _, f := range forms {
+			if strings.EqualFold(f.Name, convertTo) {
+				fmt.Println(f.Hex)
+				return
+			}
+		}
+		log.Fatalf("unknown public key form %q (want compressed, uncompressed, raw or x-only)", convertTo)
+	},
+}
+
+var pubKeyToAddressCmd = &cobra.Command{
+	Use:   "pubKeyToAddress [public_key]",
+	Short: "Derive an address from a public key",
+	Long: "Derive the address of a secp256k1 public key (any form convertPubKey accepts) on Ethereum or,\n" +
+		"with --chain, another chain; --all lists every chain. Bitcoin and the other Bitcoin-family\n" +
+		"chains always hash the compressed key, as modern wallets do.",
+	Example: `  gowallet pubKeyToAddress 0x02...
+  gowallet pubKeyToAddress --chain bitcoin --address-type p2pkh 0x02...
+  gowallet --network testnet4 pubKeyToAddress --chain bitcoin tpubDC...
+  gowallet pubKeyToAddress --all 0x04...`,
+	Run: func(cmd *cobra.Command, args []string) {
+		pub := pubKeyArg(cmd, args)
+		if !allChains {
+			address, err := pubKeyAddress(pub)
+			if err != nil {
+				log.Fatal(err)
+			}
+			fmt.Println(address)
+			return
+		}
+		fmt.Printf("bitcoin: %s\n", hdwallet.BitcoinChain(selectedNetwork()).Address(pub))
+		for _, c := range hdwallet.Chains {
+			fmt.Printf("%s: %s\n", c.Name, c.Address(pub))
+		}
+	},
+}
+
+// pubKeyArg parses the public key argument, exiting if it is missing or
+// invalid
+func pubKeyArg(cmd *cobra.Command, args []string) *btcec.PublicKey {
+	if len(args) == 0 {
+		fmt.Println("Error: Public key is required.")
+		os.Exit(1)
+	}
+	if args[0] == "help" {
+		cmd.Help()
+		os.Exit(0)
+	}
+	pub, err := hdwallet.ParsePublicKey(args[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+	return pub
+}
+
+func init() {
+	convertPubKeyCmd.Flags().StringVar(&convertTo, "to", "", "print only this form: compressed, uncompressed, raw or x-only")
+	pubKeyToAddressCmd.Flags().StringVar(&chainName, "chain", "", "address format of a secp256k1 chain: "+strings.Join(secp256k1ChainNames(), ", "))
+	pubKeyToAddressCmd.Flags().StringVar(&bech32HRP, "hrp", "", "bech32 address prefix replacing the Cosmos SDK chain's own (e.g. juno)")
+	pubKeyToAddressCmd.Flags().StringVar(&addressType, "address-type", "", "bitcoin, litecoin: p2pkh, p2sh (nested SegWit) or p2wpkh (default); bitcoin also p2tr (Taproot)")
+	pubKeyToAddressCmd.Flags().BoolVar(&allChains, "all", false, "print the address on every supported chain")
+}
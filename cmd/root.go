@@ -1,9 +1,20 @@
 package cmd
 
 import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/spark8899/gowallet/internal/hdwallet"
+	"github.com/spark8899/gowallet/internal/security"
 	"github.com/spf13/cobra"
 )
 
+var networkName string
+var deterministicSeed string
+
 var rootCmd = &cobra.Command{
 	Use:   "gowallet",
 	Short: "A comprehensive cryptocurrency wallet tool",
@@ -14,8 +25,54 @@ func Execute() error {
 	return rootCmd.Execute()
 }
 
+// selectedNetwork resolves the global --network flag, exiting on unknown names
+func selectedNetwork() *hdwallet.Network {
+	net, err := hdwallet.NetworkByName(networkName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if !net.IsMainNet() {
+		fmt.Fprintf(os.Stderr, "Network: %s (EVM chain ID %s)\n", net.Name, net.ChainID)
+	}
+	return net
+}
+
+// randomSource returns the randomness source for key and mnemonic
+// generation: nil (crypto/rand) unless --deterministic-seed is set
+func randomSource() io.Reader {
+	if deterministicSeed == "" {
+		return nil
+	}
+	fmt.Fprintln(os.Stderr, "WARNING: --deterministic-seed is set. Output is reproducible by anyone who knows the seed.")
+	fmt.Fprintln(os.Stderr, "WARNING: Use it for test fixtures only and NEVER for wallets holding real funds.")
+	r, err := security.NewDeterministicReader([]byte(deterministicSeed))
+	if err != nil {
+		log.Fatal(err)
+	}
+	return r
+}
+
+// secretArg copies a secret command line argument into a SecretBuffer. The
+// argument string itself cannot be wiped, but everything derived from it is.
+func secretArg(arg string) *security.SecretBuffer {
+	secret, err := security.NewSecretBufferFromString(arg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return secret
+}
+
+// writeSecretLine writes secret and a newline to stdout without converting
+// it to a string
+func writeSecretLine(secret *security.SecretBuffer) {
+	os.Stdout.Write(secret.Bytes())
+	os.Stdout.Write([]byte("\n"))
+}
+
 func init() {
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.PersistentFlags().StringVar(&deterministicSeed, "deterministic-seed", "", "UNSAFE, developer only: derive all randomness from this seed (ChaCha20 DRBG) for reproducible test fixtures")
+	rootCmd.PersistentFlags().StringVar(&networkName, "network", "mainnet", "network to use: "+strings.Join(hdwallet.NetworkNames(), ", "))
 
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(genPrivateKeyCmd)
@@ -25,4 +82,28 @@ func init() {
 	rootCmd.AddCommand(mnToSeedCmd)
 	rootCmd.AddCommand(getPathCmd)
 	rootCmd.AddCommand(seedToMnCmd)
+	rootCmd.AddCommand(entropyToMnemonicCmd)
+	rootCmd.AddCommand(mnemonicToEntropyCmd)
+	rootCmd.AddCommand(checkEntropyCmd)
+	rootCmd.AddCommand(vanityCmd)
+	rootCmd.AddCommand(contractAddressCmd)
+	rootCmd.AddCommand(paperWalletCmd)
+	rootCmd.AddCommand(urEncodeCmd)
+	rootCmd.AddCommand(urDecodeCmd)
+	rootCmd.AddCommand(tronAddressCmd)
+	rootCmd.AddCommand(cosmosSignCmd)
+	rootCmd.AddCommand(validateAddressCmd)
+	rootCmd.AddCommand(convertPubKeyCmd)
+	rootCmd.AddCommand(pubKeyToAddressCmd)
+	rootCmd.AddCommand(convertPrivKeyCmd)
+	rootCmd.AddCommand(encryptCmd)
+	rootCmd.AddCommand(decryptCmd)
+	rootCmd.AddCommand(ecdhCmd)
+	rootCmd.AddCommand(schnorrSignCmd)
+	rootCmd.AddCommand(schnorrVerifyCmd)
+	rootCmd.AddCommand(taprootTweakCmd)
+	rootCmd.AddCommand(signHashCmd)
+	rootCmd.AddCommand(verifySignatureCmd)
+	rootCmd.AddCommand(convertSignatureCmd)
+	rootCmd.AddCommand(ecrecoverCmd)
 }
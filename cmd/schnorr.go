@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/spark8899/gowallet/internal/hdwallet"
+	"github.com/spark8899/gowallet/internal/schnorr"
+	"github.com/spf13/cobra"
+)
+
+var messageHex bool
+var messageFile string
+var auxRandHex string
+var taprootKey bool
+var merkleRootHex string
+
+var schnorrSignCmd = &cobra.Command{
+	Use:   "schnorrSign [message]",
+	Short: "Sign a message with a BIP340 Schnorr signature",
+	Long: "Sign a message of any length with BIP340 and print the 64-byte signature as hex. Taproot\n" +
+		"signs 32-byte sighashes; pass those with --hex. --taproot signs with the BIP341 output key\n" +
+		"instead (a key-path spend), tweaked by --merkle-root if the output commits to scripts.\n" +
+		"The private key may be hex, WIF, a keystore file, or a mnemonic or seed with a derivation path.",
+	Example: `  gowallet schnorrSign -k <private_key_hex> "hello"
+  gowallet schnorrSign -k <private_key_hex> --hex 243f6a8885a308d313198a2e03707344a4093822299f31d0082efa98ec4e6c89
+  gowallet schnorrSign -m "apple banana ..." -p "m/86'/0'/0'/0/0" --taproot --hex <sighash>`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 && args[0] == "help" {
+			cmd.Help()
+			os.Exit(0)
+		}
+		msg := messageArg(args)
+		aux, err := auxRandArg()
+		if err != nil {
+			log.Fatal(err)
+		}
+		secret := keySecretOrPath()
+		defer secret.Destroy()
+
+		var sig []byte
+		if taprootKey || merkleRootHex != "" {
+			root, err := merkleRootArg()
+			if err != nil {
+				log.Fatal(err)
+			}
+			sig, err = schnorr.SignTaproot(secret, root, msg, aux)
+		} else {
+			sig, err = schnorr.Sign(secret, msg, aux)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(hex.EncodeToString(sig))
+	},
+}
+
+var schnorrVerifyCmd = &cobra.Command{
+	Use:   "schnorrVerify [public_key] [signature] [message]",
+	Short: "Verify a BIP340 Schnorr signature",
+	Long: "Verify a BIP340 signature of a message by a public key. The key may be x-only (32 bytes) or in\n" +
+		"any form convertPubKey accepts; only its x coordinate is used. The message is read as with\n" +
+		"schnorrSign. Exits with status 1 if the signature is invalid.",
+	Example: `  gowallet schnorrVerify 0x8318... <signature_hex> "hello"
+  gowallet schnorrVerify --hex <x_only_key> <signature_hex> <sighash>`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 && args[0] == "help" {
+			cmd.Help()
+			os.Exit(0)
+		}
+		if len(args) < 2 {
+			fmt.Println("Error: Public key and signature are required.")
+			os.Exit(1)
+		}
+		pub, err := xOnlyPubKeyArg(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		sig, err := hex.DecodeString(strings.TrimPrefix(args[1], "0x"))
+		if err != nil {
+			log.Fatalf("invalid signature hex: %v", err)
+		}
+		msg := messageArg(args[2:])
+
+		if err := schnorr.Verify(schnorr.XOnlyPublicKey(pub), msg, sig); err != nil {
+			fmt.Printf("Invalid: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("Valid")
+	},
+}
+
+var taprootTweakCmd = &cobra.Command{
+	Use:   "taprootTweak [internal_public_key]",
+	Short: "Tweak a key into its Taproot (BIP341) output key",
+	Long: "Print the x-only output key and P2TR address of an internal public key (x-only, or any form\n" +
+		"convertPubKey accepts) tweaked by --merkle-root, the root of the output's script tree; without it\n" +
+		"the output is key-path only, as in BIP86.\n" +
+		"With a private key (-k, --keystore, or -m/-s with -p) print the tweaked private key instead, which\n" +
+		"signs key-path spends with any BIP340 signer.",
+	Example: `  gowallet taprootTweak 0x02...
+  gowallet --network testnet4 taprootTweak --merkle-root 5b75adec... 0x187791b6...
+  gowallet taprootTweak -k <private_key_hex>`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 && args[0] == "help" {
+			cmd.Help()
+			os.Exit(0)
+		}
+		root, err := merkleRootArg()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if privateKey != "" || keystoreFile != "" || mnemonicStr != "" || seedStr != "" {
+			secret := keySecretOrPath()
+			defer secret.Destroy()
+			tweaked, err := schnorr.TweakPrivateKey(secret, root)
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer tweaked.Destroy()
+			writeSecretLine(tweaked)
+			return
+		}
+
+		if len(args) == 0 {
+			fmt.Println("Error: Public key is required.")
+			os.Exit(1)
+		}
+		internal, err := xOnlyPubKeyArg(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		output, err := schnorr.TweakPublicKey(internal, root)
+		if err != nil {
+			log.Fatal(err)
+		}
+		address, err := hdwallet.BitcoinChain(selectedNetwork()).UTXO.TaprootAddress(internal, root)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Internal Key: %x\n", schnorr.XOnlyPublicKey(internal))
+		fmt.Printf("Output Key: %x\n", schnorr.XOnlyPublicKey(output))
+		fmt.Printf("Address: %s\n", address)
+	},
+}
+
+// xOnlyPubKeyArg parses a BIP340 x-only key (32 bytes hex), or any form
+// hdwallet.ParsePublicKey accepts, where only the x coordinate matters
+func xOnlyPubKeyArg(s string) (*btcec.PublicKey, error) {
+	raw, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(s), "0x"))
+	if err == nil && len(raw) == 32 {
+		return schnorr.ParseXOnlyPublicKey(raw)
+	}
+	return hdwallet.ParsePublicKey(s)
+}
+
+// messageArg returns the message from -i or the first argument, decoded
+// from hex with --hex. With neither, the message is read from stdin.
+func messageArg(args []string) []byte {
+	var msg []byte
+	switch {
+	case messageFile != "":
+		data, err := os.ReadFile(messageFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		msg = data
+	case len(args) > 0:
+		msg = []byte(args[0])
+	default:
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			log.Fatal(err)
+		}
+		msg = data
+	}
+	if !messageHex {
+		return msg
+	}
+	decoded, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(msg)), "0x"))
+	if err != nil {
+		log.Fatalf("invalid message hex: %v", err)
+	}
+	return decoded
+}
+
+// auxRandArg decodes --aux, or draws it from the --deterministic-seed DRBG.
+// nil leaves the choice to crypto/rand.
+func auxRandArg() ([]byte, error) {
+	if auxRandHex != "" {
+		aux, err := hex.DecodeString(strings.TrimPrefix(auxRandHex, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid --aux hex: %w", err)
+		}
+		return aux, nil
+	}
+	if r := randomSource(); r != nil {
+		aux := make([]byte, 32)
+		if _, err := io.ReadFull(r, aux); err != nil {
+			return nil, err
+		}
+		return aux, nil
+	}
+	return nil, nil
+}
+
+// merkleRootArg decodes --merkle-root; empty means no script tree
+func merkleRootArg() ([]byte, error) {
+	if merkleRootHex == "" {
+		return nil, nil
+	}
+	root, err := hex.DecodeString(strings.TrimPrefix(merkleRootHex, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid --merkle-root hex: %w", err)
+	}
+	return root, nil
+}
+
+func init() {
+	schnorrSignCmd.Flags().StringVarP(&privateKey, "key", "k", "", "private key, hex or WIF")
+	schnorrSignCmd.Flags().StringVar(&keystoreFile, "keystore", "", "read the private key from an Ethereum keystore (v3) file")
+	schnorrSignCmd.Flags().StringVar(&passwordFile, "password-file", "", "file holding the keystore password")
+	schnorrSignCmd.Flags().StringVarP(&mnemonicStr, "mnemonic", "m", "", "derive the private key from this mnemonic at -p")
+	schnorrSignCmd.Flags().StringVarP(&seedStr, "seed", "s", "", "derive the private key from this seed (hex) at -p")
+	schnorrSignCmd.Flags().StringVarP(&path, "path", "p", "", "derivation path of the private key, e.g. \"m/86'/0'/0'/0/0\"")
+	schnorrSignCmd.Flags().BoolVar(&messageHex, "hex", false, "the message is hex, e.g. a 32-byte sighash")
+	schnorrSignCmd.Flags().StringVarP(&messageFile, "input", "i", "", "read the message from a file (default: the argument, or stdin)")
+	schnorrSignCmd.Flags().StringVar(&auxRandHex, "aux", "", "32 bytes of auxiliary randomness, hex (default: random)")
+	schnorrSignCmd.Flags().BoolVar(&taprootKey, "taproot", false, "sign with the BIP341 output key of the private key (key-path spend)")
+	schnorrSignCmd.Flags().StringVar(&merkleRootHex, "merkle-root", "", "script tree merkle root, hex; implies --taproot")
+	schnorrVerifyCmd.Flags().BoolVar(&messageHex, "hex", false, "the message is hex, e.g. a 32-byte sighash")
+	schnorrVerifyCmd.Flags().StringVarP(&messageFile, "input", "i", "", "read the message from a file (default: the argument, or stdin)")
+	taprootTweakCmd.Flags().StringVar(&merkleRootHex, "merkle-root", "", "script tree merkle root, hex (default: none, as in BIP86)")
+	taprootTweakCmd.Flags().StringVarP(&privateKey, "key", "k", "", "tweak this private key, hex or WIF")
+	taprootTweakCmd.Flags().StringVar(&keystoreFile, "keystore", "", "tweak the private key of an Ethereum keystore (v3) file")
+	taprootTweakCmd.Flags().StringVar(&passwordFile, "password-file", "", "file holding the keystore password")
+	taprootTweakCmd.Flags().StringVarP(&mnemonicStr, "mnemonic", "m", "", "tweak the private key of this mnemonic at -p")
+	taprootTweakCmd.Flags().StringVarP(&seedStr, "seed", "s", "", "tweak the private key of this seed (hex) at -p")
+	taprootTweakCmd.Flags().StringVarP(&path, "path", "p", "", "derivation path of the private key, e.g. \"m/86'/0'/0'/0/0\"")
+}
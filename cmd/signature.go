@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/spark8899/gowallet/internal/commonPrivateKey"
+	"github.com/spark8899/gowallet/internal/hdwallet"
+	"github.com/spf13/cobra"
+)
+
+var signatureFormat string
+var allowHighS bool
+var lowS bool
+var signaturePubKey string
+var signatureHash string
+
+var signHashCmd = &cobra.Command{
+	Use:   "signHash [hash]",
+	Short: "Sign a 32-byte hash with a deterministic (RFC6979) ECDSA signature",
+	Long: "Sign a 32-byte hash with secp256k1 ECDSA and print the signature as hex. The nonce is RFC6979,\n" +
+		"so the same key and hash always give the same signature, and s is low (BIP62, EIP-2).\n" +
+		"--format selects the encoding: rsv (65 bytes, as crypto.Sign; v is 0 or 1), compact (64 bytes),\n" +
+		"der or eip2098 (64 bytes with v in the top bit of s). The private key may be hex, WIF, a keystore\n" +
+		"file, or a mnemonic or seed with a path.",
+	Example: `  gowallet signHash -k <private_key_hex> 0x8f43...
+  gowallet signHash -k <private_key_wif> --format der 0x8f43...`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 && args[0] == "help" {
+			cmd.Help()
+			os.Exit(0)
+		}
+		if len(args) == 0 {
+			fmt.Println("Error: Hash is required.")
+			os.Exit(1)
+		}
+		hash, err := hashArg(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		secret := keySecretOrPath()
+		defer secret.Destroy()
+
+		sig, err := commonPrivateKey.SignHashRFC6979(secret, hash)
+		if err != nil {
+			log.Fatal(err)
+		}
+		out, err := encodeSignature(sig, signatureFormat)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(out)
+	},
+}
+
+var verifySignatureCmd = &cobra.Command{
+	Use:   "verifySignature [public_key] [hash] [signature]",
+	Short: "Verify an ECDSA signature of a 32-byte hash",
+	Long: "Verify a secp256k1 ECDSA signature of a 32-byte hash by a public key (any form convertPubKey\n" +
+		"accepts). The signature may be compact (64 bytes), r || s || v (65 bytes, v 0/1 or 27/28) or\n" +
+		"DER; a given v must recover the key. High-S signatures are rejected as malleable unless\n" +
+		"--allow-high-s is set. Exits with status 1 if the signature is invalid.",
+	Example: `  gowallet verifySignature 0x02... 0x8f43... 0x3045...
+  gowallet verifySignature --allow-high-s 0x04... 0x8f43... <r||s_hex>`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 && args[0] == "help" {
+			cmd.Help()
+			os.Exit(0)
+		}
+		if len(args) < 3 {
+			fmt.Println("Error: Public key, hash and signature are required.")
+			os.Exit(1)
+		}
+		pub, err := hdwallet.ParsePublicKey(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		hash, err := hashArg(args[1])
+		if err != nil {
+			log.Fatal(err)
+		}
+		sig, err := hex.DecodeString(strings.TrimPrefix(args[2], "0x"))
+		if err != nil {
+			log.Fatalf("invalid signature hex: %v", err)
+		}
+
+		if err := commonPrivateKey.VerifySignature(pub.SerializeCompressed(), hash, sig, allowHighS); err != nil {
+			fmt.Printf("Invalid: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("Valid")
+	},
+}
+
+var convertSignatureCmd = &cobra.Command{
+	Use:   "convertSignature [signature]",
+	Short: "Convert an ECDSA signature between DER, compact and r || s || v",
+	Long: "Convert a secp256k1 ECDSA signature between DER, compact r || s (64 bytes), r || s || v\n" +
+		"(65 bytes) and EIP-2098. DER and compact carry no recovery id; pass --pubkey and --hash to\n" +
+		"compute v for rsv and eip2098.\n" +
+		"--low-s replaces a high s with n - s (flipping v), as Bitcoin and Ethereum require.\n" +
+		"Without --to every available form is printed.",
+	Example: `  gowallet convertSignature 0x3045...
+  gowallet convertSignature --to rsv --pubkey 0x02... --hash 0x8f43... 0x3045...
+  gowallet convertSignature --low-s --to der <r||s||v_hex>`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 && args[0] == "help" {
+			cmd.Help()
+			os.Exit(0)
+		}
+		if len(args) == 0 {
+			fmt.Println("Error: Signature is required.")
+			os.Exit(1)
+		}
+		raw, err := hex.DecodeString(strings.TrimPrefix(args[0], "0x"))
+		if err != nil {
+			log.Fatalf("invalid signature hex: %v", err)
+		}
+		sig, err := commonPrivateKey.ParseSignature(raw)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if lowS {
+			sig.NormalizeS()
+		}
+		if signaturePubKey != "" || signatureHash != "" {
+			if signaturePubKey == "" || signatureHash == "" {
+				fmt.Println("Error: --pubkey and --hash must be given together.")
+				os.Exit(1)
+			}
+			pub, err := hdwallet.ParsePublicKey(signaturePubKey)
+			if err != nil {
+				log.Fatal(err)
+			}
+			hash, err := hashArg(signatureHash)
+			if err != nil {
+				log.Fatal(err)
+			}
+			if err := sig.SetRecoveryID(hash, pub); err != nil {
+				log.Fatal(err)
+			}
+		}
+
+		if convertTo != "" {
+			out, err := encodeSignature(sig, convertTo)
+			if err != nil {
+				log.Fatal(err)
+			}
+			fmt.Println(out)
+			return
+		}
+		fmt.Printf("DER: %s\n", hex.EncodeToString(sig.DER()))
+		fmt.Printf("Compact: %s\n", hex.EncodeToString(sig.Compact()))
+		if rsv, err := sig.RSV(); err == nil {
+			fmt.Printf("RSV: %s\n", hex.EncodeToString(rsv))
+		}
+		if compact, err := sig.EIP2098(); err == nil {
+			fmt.Printf("EIP-2098: %s\n", hex.EncodeToString(compact))
+		}
+		fmt.Printf("Low S: %t\n", sig.IsLowS())
+	},
+}
+
+// hashArg decodes a 32-byte hex hash
+func hashArg(s string) ([]byte, error) {
+	hash, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid hash hex: %w", err)
+	}
+	if len(hash) != 32 {
+		return nil, commonPrivateKey.ErrHashLength
+	}
+	return hash, nil
+}
+
+// encodeSignature returns sig as hex in the named encoding
+func encodeSignature(sig *commonPrivateKey.Signature, format string) (string, error) {
+	switch strings.ToLower(format) {
+	case "rsv":
+		rsv, err := sig.RSV()
+		if err != nil {
+			return "", fmt.Errorf("%w; pass --pubkey and --hash to compute it", err)
+		}
+		return hex.EncodeToString(rsv), nil
+	case "compact":
+		return hex.EncodeToString(sig.Compact()), nil
+	case "der":
+		return hex.EncodeToString(sig.DER()), nil
+	case "eip2098":
+		compact, err := sig.EIP2098()
+		if err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(compact), nil
+	}
+	return "", fmt.Errorf("unknown signature format %q (want rsv, compact, der or eip2098)", format)
+}
+
+func init() {
+	signHashCmd.Flags().StringVarP(&privateKey, "key", "k", "", "private key, hex or WIF")
+	signHashCmd.Flags().StringVar(&keystoreFile, "keystore", "", "read the private key from an Ethereum keystore (v3) file")
+	signHashCmd.Flags().StringVar(&passwordFile, "password-file", "", "file holding the keystore password")
+	signHashCmd.Flags().StringVarP(&mnemonicStr, "mnemonic", "m", "", "derive the private key from this mnemonic at -p")
+	signHashCmd.Flags().StringVarP(&seedStr, "seed", "s", "", "derive the private key from this seed (hex) at -p")
+	signHashCmd.Flags().StringVarP(&path, "path", "p", "", "derivation path of the private key, e.g. \"m/44'/60'/0'/0/0\"")
+	signHashCmd.Flags().StringVar(&signatureFormat, "format", "rsv", "signature encoding: rsv, compact, der or eip2098")
+	verifySignatureCmd.Flags().BoolVar(&allowHighS, "allow-high-s", false, "accept a high-S (malleable) signature")
+	convertSignatureCmd.Flags().StringVar(&convertTo, "to", "", "print only this encoding: rsv, compact, der or eip2098")
+	convertSignatureCmd.Flags().BoolVar(&lowS, "low-s", false, "normalize s to the lower half of the curve order")
+	convertSignatureCmd.Flags().StringVar(&signaturePubKey, "pubkey", "", "signer's public key, to compute v (with --hash)")
+	convertSignatureCmd.Flags().StringVar(&signatureHash, "hash", "", "signed 32-byte hash, hex, to compute v (with --pubkey)")
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/spark8899/gowallet/internal/hdwallet"
+	"github.com/spf13/cobra"
+)
+
+var tronAddressCmd = &cobra.Command{
+	Use:   "tronAddress [address]",
+	Short: "Convert a Tron address between base58 and hex",
+	Long: "Convert a Tron address between its base58 form (T...) and its hex form (41...).\n" +
+		"A 0x Ethereum address is converted to the Tron address of the same key.",
+	Example: `  gowallet tronAddress TNPeeaaFB7K9cmo4uQpcU32zGK8G1NYqeL
+  gowallet tronAddress 418840e6c55b9ada326d211d818c34a994aeced808
+  gowallet tronAddress 0x8840E6C55B9ADA326D211D818C34A994AECED808`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			fmt.Println("Error: Address is required.")
+			os.Exit(1)
+		}
+		if args[0] == "help" {
+			cmd.Help()
+			os.Exit(0)
+		}
+
+		var converted string
+		var err error
+		if strings.HasPrefix(args[0], "T") {
+			converted, err = hdwallet.TronToHex(args[0])
+		} else {
+			converted, err = hdwallet.TronFromHex(args[0])
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(converted)
+	},
+}
@@ -0,0 +1,427 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spark8899/gowallet/internal/hdwallet"
+	"github.com/spark8899/gowallet/internal/paperwallet"
+	"github.com/spark8899/gowallet/internal/ur"
+	"github.com/spf13/cobra"
+)
+
+var urType string
+var urInput string
+var urMaxFragment int
+var urFrames int
+var urPath string
+var urFingerprint string
+var urDataType string
+var urChainID uint64
+var urAddress string
+var urOrigin string
+var urHex bool
+
+var urEncodeCmd = &cobra.Command{
+	Use:   "urEncode [data]",
+	Short: "Encode a PSBT, xpub or Ethereum sign request as UR (animated QR) frames",
+	Long: "Encode data as Blockchain Commons UR frames for air-gapped wallets such as Keystone and Sparrow.\n" +
+		"Types: psbt (crypto-psbt; base64, hex or binary), hdkey (crypto-hdkey; an xpub, or the account\n" +
+		"xpub of -m at -p), eth-sign-request (hex sign data or a file) and bytes (hex or a file).\n" +
+		"Payloads larger than --max-fragment are fountain coded: the first frames carry one fragment each\n" +
+		"and --frames beyond that let a scanner recover from missed frames. One frame is printed per line.",
+	Example: `  gowallet urEncode -t psbt -i unsigned.psbt
+  gowallet urEncode -t hdkey -m "apple banana ..." -p "m/84'/0'/0'"
+  gowallet urEncode -t eth-sign-request --fingerprint 12345678 -p "m/44'/60'/0'/0/0" 02f86c...
+  gowallet urEncode -t psbt --qr cHNidP8BAH...`,
+	Run: func(cmd *cobra.Command, args []string) {
+		data := ""
+		if len(args) > 0 {
+			if args[0] == "help" {
+				cmd.Help()
+				os.Exit(0)
+			}
+			data = args[0]
+		}
+
+		var u ur.UR
+		var err error
+		switch urType {
+		case "psbt":
+			var psbt []byte
+			if psbt, err = decodePSBTText(urPayload(data, false)); err == nil {
+				u, err = ur.NewPSBT(psbt)
+			}
+		case "hdkey":
+			u, err = hdkeyUR(data)
+		case "eth-sign-request":
+			u, err = ethSignRequestUR(urPayload(data, true))
+		case "bytes":
+			u = ur.NewBytes(urPayload(data, true))
+		default:
+			err = fmt.Errorf("unknown UR type %q (want psbt, hdkey, eth-sign-request or bytes)", urType)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		encoder, err := ur.NewEncoder(u, urMaxFragment)
+		if err != nil {
+			log.Fatal(err)
+		}
+		frames := urFrames
+		if frames <= 0 || encoder.SinglePart() {
+			frames = encoder.SeqLen()
+		}
+		for i := 1; i <= frames; i++ {
+			frame := encoder.NextPart()
+			if !showQR && !qrASCII {
+				fmt.Println(frame)
+				continue
+			}
+			// Upper case fits QR alphanumeric mode, which is denser
+			code, err := paperwallet.NewQR(strings.ToUpper(frame))
+			if err != nil {
+				log.Fatal(err)
+			}
+			fmt.Fprintf(os.Stderr, "Frame %d/%d\n", i, frames)
+			fmt.Print(code.Terminal(qrASCII))
+		}
+	},
+}
+
+var urDecodeCmd = &cobra.Command{
+	Use:   "urDecode [frames...]",
+	Short: "Decode UR (animated QR) frames back to a PSBT, xpub or Ethereum request",
+	Long: "Decode Blockchain Commons UR frames, as scanned from Keystone, Sparrow or urEncode.\n" +
+		"Frames come from the arguments, or one per line from -i or stdin, in any order and with\n" +
+		"duplicates or gaps; decoding stops as soon as enough fountain-coded frames have arrived.\n" +
+		"A crypto-psbt is printed as base64 (or hex, or written to -o as binary), a crypto-hdkey as\n" +
+		"[fingerprint/path]xpub, and eth-sign-request / eth-signature as their fields.",
+	Example: `  gowallet urDecode ur:crypto-psbt/hdosjojkidjyzm...
+  gowallet urDecode -i frames.txt -o signed.psbt
+  zbarcam --raw | gowallet urDecode`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 && args[0] == "help" {
+			cmd.Help()
+			os.Exit(0)
+		}
+
+		decoder := ur.NewDecoder()
+		receive := func(frame string) {
+			frame = strings.TrimSpace(frame)
+			if frame == "" || decoder.Complete() {
+				return
+			}
+			if err := decoder.Receive(frame); err != nil {
+				if errors.Is(err, ur.ErrMessageChecksum) {
+					log.Fatal(err)
+				}
+				fmt.Fprintf(os.Stderr, "Skipping frame: %v\n", err)
+				return
+			}
+			if n := decoder.ExpectedParts(); n > 1 {
+				fmt.Fprintf(os.Stderr, "Received %.0f%% of %d fragments\n", decoder.Progress()*100, n)
+			}
+		}
+
+		if len(args) > 0 {
+			for _, frame := range args {
+				receive(frame)
+			}
+		} else {
+			var r io.Reader = os.Stdin
+			if urInput != "" {
+				f, err := os.Open(urInput)
+				if err != nil {
+					log.Fatal(err)
+				}
+				defer f.Close()
+				r = f
+			}
+			scanner := bufio.NewScanner(r)
+			scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+			for scanner.Scan() && !decoder.Complete() {
+				receive(scanner.Text())
+			}
+			if err := scanner.Err(); err != nil {
+				log.Fatal(err)
+			}
+		}
+
+		result, err := decoder.Result()
+		if err != nil {
+			fmt.Printf("Error: %v (%.0f%% received). Provide more frames.\n", err, decoder.Progress()*100)
+			os.Exit(1)
+		}
+		if err := printUR(result); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// urPayload reads -i as raw bytes, or else the argument as hex if isHex
+func urPayload(arg string, isHex bool) []byte {
+	if urInput != "" {
+		data, err := os.ReadFile(urInput)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return data
+	}
+	if arg == "" {
+		fmt.Println("Error: Input is required. Provide it as an argument or use -i flag.")
+		os.Exit(1)
+	}
+	if !isHex {
+		return []byte(arg)
+	}
+	data, err := hex.DecodeString(strings.TrimPrefix(arg, "0x"))
+	if err != nil {
+		log.Fatalf("invalid hex input: %v", err)
+	}
+	return data
+}
+
+// decodePSBTText accepts a binary PSBT, or its base64 or hex encoding
+func decodePSBTText(data []byte) ([]byte, error) {
+	if bytes.HasPrefix(data, []byte("psbt\xff")) {
+		return data, nil
+	}
+	text := string(bytes.TrimSpace(data))
+	if psbt, err := base64.StdEncoding.DecodeString(text); err == nil {
+		return psbt, nil
+	}
+	if psbt, err := hex.DecodeString(text); err == nil {
+		return psbt, nil
+	}
+	return nil, ur.ErrNotPSBT
+}
+
+// urKeypath parses -p and --fingerprint into a key origin
+func urKeypath(defaultPath string) (ur.Keypath, error) {
+	pathStr := urPath
+	if pathStr == "" {
+		pathStr = defaultPath
+	}
+	derivation, err := accounts.ParseDerivationPath(pathStr)
+	if err != nil {
+		return ur.Keypath{}, fmt.Errorf("failed to parse derivation path: %w", err)
+	}
+	keypath := ur.Keypath{Path: derivation}
+	if urFingerprint != "" {
+		fp, err := strconv.ParseUint(strings.TrimPrefix(urFingerprint, "0x"), 16, 32)
+		if err != nil || len(strings.TrimPrefix(urFingerprint, "0x")) != 8 {
+			return ur.Keypath{}, fmt.Errorf("fingerprint must be 8 hex digits")
+		}
+		keypath.SourceFingerprint = uint32(fp)
+	}
+	return keypath, nil
+}
+
+// hdkeyUR builds a crypto-hdkey from the account key of -m at -p, or from
+// an xpub whose origin is given by -p and --fingerprint
+func hdkeyUR(xpub string) (ur.UR, error) {
+	origin, err := urKeypath("m/84'/0'/0'")
+	if err != nil {
+		return ur.UR{}, err
+	}
+	if mnemonicStr != "" {
+		mnemonic := secretArg(mnemonicStr)
+		defer mnemonic.Destroy()
+		warnPublicMnemonic(mnemonic)
+		keyInfo, err := hdwallet.PathFromMnemonic(mnemonic, origin.Path.String(), selectedNetwork())
+		if err != nil {
+			return ur.UR{}, err
+		}
+		defer keyInfo.Destroy()
+		xpub = keyInfo.ExtendedPublicKey
+		origin.SourceFingerprint = keyInfo.MasterFingerprint
+	} else if xpub == "" {
+		fmt.Println("Error: Extended public key is required. Provide it as an argument or use -m flag.")
+		os.Exit(1)
+	}
+
+	key, err := hdkeychain.NewKeyFromString(xpub)
+	if err != nil {
+		return ur.UR{}, err
+	}
+	if key.IsPrivate() {
+		return ur.UR{}, errors.New("refusing to put an extended private key in a QR code; use the xpub")
+	}
+	var keyOrigin *ur.Keypath
+	if mnemonicStr != "" || urPath != "" {
+		keyOrigin = &origin
+	}
+	hd, err := ur.HDKeyFromExtended(key, keyOrigin)
+	if err != nil {
+		return ur.UR{}, err
+	}
+	if keyOrigin != nil && len(origin.Path) > 1 && origin.Path[1] >= hdkeychain.HardenedKeyStart {
+		hd.CoinType = origin.Path[1] - hdkeychain.HardenedKeyStart
+	}
+	return hd.UR(), nil
+}
+
+func ethSignRequestUR(signData []byte) (ur.UR, error) {
+	dataType, ok := ur.EthDataTypes[urDataType]
+	if !ok {
+		return ur.UR{}, fmt.Errorf("unknown data type %q (want transaction, typed-data, personal-message or typed-transaction)", urDataType)
+	}
+	keypath, err := urKeypath("m/44'/60'/0'/0/0")
+	if err != nil {
+		return ur.UR{}, err
+	}
+	if keypath.SourceFingerprint == 0 {
+		fmt.Fprintln(os.Stderr, "WARNING: no --fingerprint; signers such as Keystone reject requests without it.")
+	}
+
+	request := ur.EthSignRequest{
+		RequestID: make([]byte, 16),
+		SignData:  signData,
+		DataType:  dataType,
+		ChainID:   urChainID,
+		Path:      keypath,
+		Origin:    urOrigin,
+	}
+	if request.ChainID == 0 {
+		request.ChainID = selectedNetwork().ChainID.Uint64()
+	}
+	if urAddress != "" {
+		if !common.IsHexAddress(urAddress) {
+			return ur.UR{}, fmt.Errorf("invalid address %q", urAddress)
+		}
+		request.Address = common.HexToAddress(urAddress).Bytes()
+	}
+
+	random := randomSource()
+	if random == nil {
+		random = rand.Reader
+	}
+	if _, err := io.ReadFull(random, request.RequestID); err != nil {
+		return ur.UR{}, err
+	}
+	// RFC 4122 version 4, variant 1
+	request.RequestID[6] = request.RequestID[6]&0x0f | 0x40
+	request.RequestID[8] = request.RequestID[8]&0x3f | 0x80
+	fmt.Fprintf(os.Stderr, "Request ID: %s\n", formatUUID(request.RequestID))
+	return request.UR(), nil
+}
+
+func formatUUID(id []byte) string {
+	h := hex.EncodeToString(id)
+	return h[:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+}
+
+// printUR writes a decoded UR to stdout, or for a PSBT to -o if set
+func printUR(u ur.UR) error {
+	switch u.Type {
+	case ur.TypePSBT:
+		psbt, err := ur.ParsePSBT(u)
+		if err != nil {
+			return err
+		}
+		switch {
+		case outputFile != "":
+			if err := os.WriteFile(outputFile, psbt, 0600); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "PSBT written to %s\n", outputFile)
+		case urHex:
+			fmt.Println(hex.EncodeToString(psbt))
+		default:
+			fmt.Println(base64.StdEncoding.EncodeToString(psbt))
+		}
+	case ur.TypeHDKey:
+		hd, err := ur.ParseHDKey(u)
+		if err != nil {
+			return err
+		}
+		key, err := hd.Extended()
+		if err != nil {
+			return err
+		}
+		if hd.Origin != nil && hd.Origin.SourceFingerprint != 0 {
+			fmt.Printf("[%s]%s\n", hd.Origin, key)
+		} else {
+			fmt.Println(key)
+		}
+		if hd.Name != "" {
+			fmt.Fprintf(os.Stderr, "Name: %s\n", hd.Name)
+		}
+	case ur.TypeEthSignRequest:
+		request, err := ur.ParseEthSignRequest(u)
+		if err != nil {
+			return err
+		}
+		if request.RequestID != nil {
+			fmt.Printf("request-id: %s\n", formatUUID(request.RequestID))
+		}
+		fmt.Printf("data-type: %s\n", request.DataType)
+		if request.ChainID != 0 {
+			fmt.Printf("chain-id: %d\n", request.ChainID)
+		}
+		fmt.Printf("path: %s\n", request.Path)
+		if request.Address != nil {
+			fmt.Printf("address: %s\n", common.BytesToAddress(request.Address).Hex())
+		}
+		if request.Origin != "" {
+			fmt.Printf("origin: %s\n", request.Origin)
+		}
+		fmt.Printf("sign-data: 0x%x\n", request.SignData)
+	case ur.TypeEthSignature:
+		signature, err := ur.ParseEthSignature(u)
+		if err != nil {
+			return err
+		}
+		if signature.RequestID != nil {
+			fmt.Printf("request-id: %s\n", formatUUID(signature.RequestID))
+		}
+		fmt.Printf("signature: 0x%x\n", signature.Signature)
+		if signature.Origin != "" {
+			fmt.Printf("origin: %s\n", signature.Origin)
+		}
+	case ur.TypeBytes:
+		data, err := ur.ParseBytes(u)
+		if err != nil {
+			return err
+		}
+		fmt.Println(hex.EncodeToString(data))
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown UR type %s, printing its CBOR\n", u.Type)
+		fmt.Println(hex.EncodeToString(u.CBOR))
+	}
+	return nil
+}
+
+func init() {
+	urEncodeCmd.Flags().StringVarP(&urType, "type", "t", "psbt", "payload type: psbt, hdkey, eth-sign-request or bytes")
+	urEncodeCmd.Flags().StringVarP(&urInput, "input", "i", "", "read the payload from a file instead of the argument")
+	urEncodeCmd.Flags().IntVar(&urMaxFragment, "max-fragment", 200, "maximum bytes per frame; smaller frames give sparser QR codes")
+	urEncodeCmd.Flags().IntVar(&urFrames, "frames", 0, "frames to print (default: one per fragment); extra frames are fountain coded")
+	urEncodeCmd.Flags().StringVarP(&mnemonicStr, "mnemonic", "m", "", "hdkey: export the account xpub of this mnemonic")
+	urEncodeCmd.Flags().StringVarP(&urPath, "path", "p", "", "hdkey: account path (default m/84'/0'/0'); eth-sign-request: signing key path (default m/44'/60'/0'/0/0)")
+	urEncodeCmd.Flags().StringVar(&urFingerprint, "fingerprint", "", "master key fingerprint (8 hex digits) of the path")
+	urEncodeCmd.Flags().StringVar(&urDataType, "data-type", "typed-transaction", "eth-sign-request: transaction, typed-data, personal-message or typed-transaction")
+	urEncodeCmd.Flags().Uint64Var(&urChainID, "chain-id", 0, "eth-sign-request: chain ID (default: from --network)")
+	urEncodeCmd.Flags().StringVar(&urAddress, "address", "", "eth-sign-request: expected signer address")
+	urEncodeCmd.Flags().StringVar(&urOrigin, "origin", "gowallet", "eth-sign-request: requesting wallet name shown by the signer")
+	urEncodeCmd.Flags().BoolVar(&showQR, "qr", false, "print each frame as a QR code (Unicode, for dark terminals)")
+	urEncodeCmd.Flags().BoolVar(&qrASCII, "qr-ascii", false, "print each frame as an ASCII QR code (for light backgrounds)")
+	urDecodeCmd.Flags().StringVarP(&urInput, "input", "i", "", "read frames from a file, one per line (default: stdin)")
+	urDecodeCmd.Flags().StringVarP(&outputFile, "output", "o", "", "write a decoded PSBT to this file as binary")
+	urDecodeCmd.Flags().BoolVar(&urHex, "hex", false, "print a decoded PSBT as hex instead of base64")
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spark8899/gowallet/internal/hdwallet"
+	"github.com/spf13/cobra"
+)
+
+var expectChain string
+var convertTo string
+
+var validateAddressCmd = &cobra.Command{
+	Use:   "validateAddress [address]",
+	Short: "Check an address offline and convert it between equivalent forms",
+	Long: "Detect the chain, network and type of an address and verify its checksum: EIP-55 (Ethereum),\n" +
+		"base58check and bech32/bech32m (Bitcoin, Litecoin, Dogecoin, Dash), CashAddr (Bitcoin Cash), Tron\n" +
+		"and Cosmos SDK bech32. A checksum error names the position of a single mistyped character when it\n" +
+		"can be located. Equivalent forms (checksummed Ethereum, legacy / CashAddr, Tron hex) are listed.\n" +
+		"Exits with status 1 if the address is invalid or not valid on --chain.",
+	Example: `  gowallet validateAddress 0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266
+  gowallet validateAddress --chain litecoin ltc1qjmxnz78nmc8nq77wuxh25n2es7rzm5c2rkk4wh
+  gowallet validateAddress --to cashaddr 1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			fmt.Println("Error: Address is required.")
+			os.Exit(1)
+		}
+		if args[0] == "help" {
+			cmd.Help()
+			os.Exit(0)
+		}
+
+		info, err := hdwallet.DetectAddress(args[0])
+		if err != nil {
+			fmt.Printf("Invalid: %v\n", err)
+			os.Exit(1)
+		}
+		if expectChain != "" && !info.ValidOn(expectChain) {
+			fmt.Printf("Invalid: %v: this is a %s address, not %s\n", hdwallet.ErrWrongNetwork, strings.Join(info.Chains, " / "), expectChain)
+			os.Exit(1)
+		}
+		for _, w := range info.Warnings {
+			fmt.Fprintf(os.Stderr, "WARNING: %s\n", w)
+		}
+
+		if convertTo != "" {
+			converted, ok := info.Form(convertTo)
+			if !ok {
+				names := make([]string, len(info.Forms))
+				for i, f := range info.Forms {
+					names[i] = f.Name
+				}
+				fmt.Printf("Error: No %s form for this address (available: %s).\n", convertTo, strings.Join(names, ", "))
+				os.Exit(1)
+			}
+			fmt.Println(converted)
+			return
+		}
+
+		fmt.Printf("Address: %s\n", info.Address)
+		fmt.Printf("Chain: %s\n", strings.Join(info.Chains, ", "))
+		fmt.Printf("Network: %s\n", info.Network)
+		fmt.Printf("Type: %s\n", info.Type)
+		fmt.Printf("Encoding: %s\n", info.Encoding)
+		for _, f := range info.Forms {
+			fmt.Printf("Form %s: %s\n", f.Name, f.Address)
+		}
+	},
+}
+
+func init() {
+	validateAddressCmd.Flags().StringVar(&expectChain, "chain", "", "also require the address to be valid on this chain (e.g. litecoin)")
+	validateAddressCmd.Flags().StringVar(&convertTo, "to", "", "print only this form: checksummed, lowercase, cashaddr, legacy, hex, base58, ethereum, ...")
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"runtime"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spark8899/gowallet/internal/security"
+	"github.com/spark8899/gowallet/internal/vanity"
+	"github.com/spf13/cobra"
+)
+
+var vanityPrefix string
+var vanitySuffix string
+var vanityRegex string
+var vanityCaseSensitive bool
+var vanityWorkers int
+
+var vanityCmd = &cobra.Command{
+	Use:   "vanity",
+	Short: "Search for an Ethereum address matching a pattern",
+	Long: "Search for an Ethereum address matching a prefix, suffix and/or regex using a pool of workers.\n" +
+		"Use --case-sensitive to match the EIP-55 checksummed form. Press Ctrl+C to stop the search.",
+	Example: `  gowallet vanity --prefix dead
+  gowallet vanity --prefix C0FFEE --case-sensitive --workers 8
+  gowallet vanity --regex "^0{4}.*f{2}$"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 && args[0] == "help" {
+			cmd.Help()
+			os.Exit(0)
+		}
+
+		matcher, err := vanity.NewMatcher(vanityPrefix, vanitySuffix, vanityRegex, vanityCaseSensitive)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		cfg := searchConfig(matcher, "addr")
+		result, stats, err := vanity.Search(ctx, matcher, cfg)
+		printSearchSummary(stats, "addr")
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer security.ZeroPrivateKey(result.PrivateKey)
+
+		fmt.Printf("%v:%v\n", result.Address.Hex(), hexutil.Encode(crypto.FromECDSA(result.PrivateKey)))
+	},
+}
+
+// searchConfig prints the search difficulty and returns a worker pool
+// configuration reporting progress on stderr
+func searchConfig(matcher *vanity.Matcher, unit string) vanity.Config {
+	workers := vanityWorkers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	difficulty := matcher.Difficulty()
+	fmt.Fprintf(os.Stderr, "Difficulty: %.0f (50%% chance after %.0f attempts), workers: %d\n",
+		difficulty, matcher.AttemptsForProbability(0.5), workers)
+
+	return vanity.Config{
+		Workers:  workers,
+		Interval: 2 * time.Second,
+		Progress: func(s vanity.Stats) {
+			fmt.Fprintf(os.Stderr, "\r%d attempts, %.0f %s/s, expected %s   ",
+				s.Attempts, s.Rate(), unit, s.Expected(difficulty).Round(time.Second))
+		},
+	}
+}
+
+func printSearchSummary(stats vanity.Stats, unit string) {
+	fmt.Fprintf(os.Stderr, "\r%d attempts in %s (%.0f %s/s)\n",
+		stats.Attempts, stats.Elapsed.Round(time.Millisecond), stats.Rate(), unit)
+}
+
+func init() {
+	vanityCmd.Flags().StringVar(&vanityPrefix, "prefix", "", "hex prefix the address must start with")
+	vanityCmd.Flags().StringVar(&vanitySuffix, "suffix", "", "hex suffix the address must end with")
+	vanityCmd.Flags().StringVar(&vanityRegex, "regex", "", "regular expression the address (40 hex chars, no 0x) must match")
+	vanityCmd.Flags().BoolVar(&vanityCaseSensitive, "case-sensitive", false, "match against the EIP-55 checksummed address")
+	vanityCmd.Flags().IntVarP(&vanityWorkers, "workers", "w", 0, "number of worker goroutines (default: number of CPUs)")
+}
@@ -1,96 +1,106 @@
 package commonPrivateKey
 
 import (
+	"bytes"
 	"crypto/ecdsa"
+	"encoding/hex"
 	"errors"
 	"fmt"
-	"log"
 	"math/big"
-	"strings"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spark8899/gowallet/internal/security"
 )
 
-func generateKey() {
-	// Generate ECDSA private key
-	privateKey, err := crypto.GenerateKey()
-	if err != nil {
-		log.Fatal(err)
-	}
+// ErrInvalidPrivateKeyLength indicates hex input that is not 32 bytes
+var ErrInvalidPrivateKeyLength = errors.New("invalid private key length, need 256 bits")
 
-	// Validate private key strength
-	// Note: This is a defense-in-depth measure. crypto.GenerateKey() should
-	// always produce valid keys, but we verify anyway for security.
-	if err := ValidatePrivateKey(privateKey); err != nil {
-		log.Fatalf("Generated key failed validation: %v", err)
-	}
+// zeroPrivateKey wipes a parsed key once an operation is done with it. It is
+// a variable so tests can check that every parsed key is wiped.
+var zeroPrivateKey = security.ZeroPrivateKey
 
-	publicKey := privateKey.Public()
-	ecdsaPublicKey, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		log.Fatal("failed to cast public key to ECDSA")
+// decodePrivateKey decodes the hex (optionally 0x prefixed) or WIF private
+// key in secret, surrounded by optional whitespace, into a new 32-byte
+// SecretBuffer without passing through a string
+func decodePrivateKey(secret *security.SecretBuffer) (*security.SecretBuffer, error) {
+	if secret.Destroyed() {
+		return nil, security.ErrSecretDestroyed
 	}
-
-	// Convert private key to hex
-	privateKeyHex := hexutil.Encode(crypto.FromECDSA(privateKey))
-
-	// Derive address from public key
-	address := crypto.PubkeyToAddress(*ecdsaPublicKey)
-
-	fmt.Printf("%v:%v\n", address.Hex(), privateKeyHex)
-}
-
-func GetGenerateKey(num int) {
-	if num <= 0 {
-		log.Println("Warning: number of keys must be positive, defaulting to 1")
-		num = 1
+	text := bytes.TrimSpace(secret.Bytes())
+	if isWIF(text) {
+		raw, _, _, err := decodeWIF(text)
+		return raw, err
+	}
+	if len(text) >= 2 && text[0] == '0' && (text[1] == 'x' || text[1] == 'X') {
+		text = text[2:]
 	}
-	if num > 1000 {
-		log.Println("Warning: limiting key generation to 1000 keys")
-		num = 1000
+	if len(text) != 64 {
+		return nil, ErrInvalidPrivateKeyLength
+	}
+
+	raw, err := security.NewSecretBuffer(32)
+	if err != nil {
+		return nil, err
 	}
-	for i := 0; i < num; i++ {
-		generateKey()
+	if _, err := hex.Decode(raw.Bytes(), text); err != nil {
+		raw.Destroy()
+		return nil, fmt.Errorf("invalid private key hex: %w", err)
 	}
+	return raw, nil
 }
 
-func PrivateKey(privateKeyStr string) (*ecdsa.PrivateKey, error) {
-	privateKeyWithoutPrefix := strings.TrimPrefix(privateKeyStr, "0x")
-	privateKey, err := crypto.HexToECDSA(privateKeyWithoutPrefix)
+// PrivateKey parses the hex or WIF private key held in secret. secret is not
+// modified or destroyed; the caller should wipe the returned key with
+// security.ZeroPrivateKey.
+func PrivateKey(secret *security.SecretBuffer) (*ecdsa.PrivateKey, error) {
+	raw, err := decodePrivateKey(secret)
 	if err != nil {
 		return nil, err
 	}
+	defer raw.Destroy()
 
-	return privateKey, nil
+	return crypto.ToECDSA(raw.Bytes())
 }
 
-func PrivateKeyBytes(privateKeyStr string) ([]byte, error) {
-	privateKey, err := PrivateKey(privateKeyStr)
+// PrivateKeyBytes returns the 32 raw key bytes in a new SecretBuffer
+func PrivateKeyBytes(secret *security.SecretBuffer) (*security.SecretBuffer, error) {
+	privateKey, err := PrivateKey(secret)
 	if err != nil {
 		return nil, err
 	}
+	defer zeroPrivateKey(privateKey)
 
-	return crypto.FromECDSA(privateKey), nil
+	return security.NewSecretBufferFrom(crypto.FromECDSA(privateKey))
 }
 
-func PrivateKeyHex(privateKeyStr string) (string, error) {
-	privateKeyBytes, err := PrivateKeyBytes(privateKeyStr)
+// PrivateKeyHex returns the canonical 0x-prefixed lowercase hex key in a
+// new SecretBuffer
+func PrivateKeyHex(secret *security.SecretBuffer) (*security.SecretBuffer, error) {
+	raw, err := PrivateKeyBytes(secret)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	defer raw.Destroy()
 
-	return hexutil.Encode(privateKeyBytes), nil
+	out, err := security.NewSecretBuffer(2 + hex.EncodedLen(raw.Len()))
+	if err != nil {
+		return nil, err
+	}
+	copy(out.Bytes(), "0x")
+	hex.Encode(out.Bytes()[2:], raw.Bytes())
+	return out, nil
 }
 
-func PublicKey(privateKeyStr string) (*ecdsa.PublicKey, error) {
-	privateKey, err := PrivateKey(privateKeyStr)
+func PublicKey(secret *security.SecretBuffer) (*ecdsa.PublicKey, error) {
+	privateKey, err := PrivateKey(secret)
 	if err != nil {
 		return nil, err
 	}
+	defer zeroPrivateKey(privateKey)
 
 	publicKey := privateKey.Public()
 	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
@@ -101,8 +111,8 @@ func PublicKey(privateKeyStr string) (*ecdsa.PublicKey, error) {
 	return publicKeyECDSA, nil
 }
 
-func PublicKeyBytes(privateKeyStr string) ([]byte, error) {
-	publicKey, err := PublicKey(privateKeyStr)
+func PublicKeyBytes(secret *security.SecretBuffer) ([]byte, error) {
+	publicKey, err := PublicKey(secret)
 	if err != nil {
 		return nil, err
 	}
@@ -110,8 +120,28 @@ func PublicKeyBytes(privateKeyStr string) ([]byte, error) {
 	return crypto.FromECDSAPub(publicKey), nil
 }
 
-func PublicKeyHex(privateKeyStr string) (string, error) {
-	publicKeyBytes, err := PublicKeyBytes(privateKeyStr)
+func PublicKeyHex(secret *security.SecretBuffer) (string, error) {
+	publicKeyBytes, err := PublicKeyBytes(secret)
+	if err != nil {
+		return "", err
+	}
+
+	return hexutil.Encode(publicKeyBytes), nil
+}
+
+// CompressedPublicKeyBytes returns the 33-byte SEC1 compressed public key
+func CompressedPublicKeyBytes(secret *security.SecretBuffer) ([]byte, error) {
+	publicKey, err := PublicKey(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return crypto.CompressPubkey(publicKey), nil
+}
+
+// CompressedPublicKeyHex returns the 0x-prefixed compressed public key
+func CompressedPublicKeyHex(secret *security.SecretBuffer) (string, error) {
+	publicKeyBytes, err := CompressedPublicKeyBytes(secret)
 	if err != nil {
 		return "", err
 	}
@@ -119,11 +149,12 @@ func PublicKeyHex(privateKeyStr string) (string, error) {
 	return hexutil.Encode(publicKeyBytes), nil
 }
 
-func Address(privateKeyStr string) (common.Address, error) {
-	privateKey, err := PrivateKey(privateKeyStr)
+func Address(secret *security.SecretBuffer) (common.Address, error) {
+	privateKey, err := PrivateKey(secret)
 	if err != nil {
 		return common.Address{}, err
 	}
+	defer zeroPrivateKey(privateKey)
 
 	publicKey := privateKey.Public()
 	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
@@ -134,36 +165,40 @@ func Address(privateKeyStr string) (common.Address, error) {
 	return crypto.PubkeyToAddress(*publicKeyECDSA), nil
 }
 
-func AddressBytes(privateKeyStr string) ([]byte, error) {
-	address, err := Address(privateKeyStr)
+func AddressBytes(secret *security.SecretBuffer) ([]byte, error) {
+	address, err := Address(secret)
 	if err != nil {
 		return nil, err
 	}
 	return address.Bytes(), nil
 }
 
-func AddressHex(privateKeyStr string) (string, error) {
-	address, err := Address(privateKeyStr)
+func AddressHex(secret *security.SecretBuffer) (string, error) {
+	address, err := Address(secret)
 	if err != nil {
 		return "", err
 	}
 	return address.Hex(), nil
 }
 
-func SignHash(privateKeyStr string, hash []byte) ([]byte, error) {
-	privateKey, err := PrivateKey(privateKeyStr)
+// SignHash signs a 32-byte hash and returns r || s || v with v 0 or 1. The
+// nonce is RFC6979 and s is low, see SignHashRFC6979.
+func SignHash(secret *security.SecretBuffer, hash []byte) ([]byte, error) {
+	privateKey, err := PrivateKey(secret)
 	if err != nil {
 		return nil, err
 	}
+	defer zeroPrivateKey(privateKey)
 
 	return crypto.Sign(hash, privateKey)
 }
 
-func SignTxEIP155(privateKeyStr string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
-	privateKey, err := PrivateKey(privateKeyStr)
+func SignTxEIP155(secret *security.SecretBuffer, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	privateKey, err := PrivateKey(secret)
 	if err != nil {
 		return nil, err
 	}
+	defer zeroPrivateKey(privateKey)
 
 	signer := types.NewEIP155Signer(chainID)
 	// Sign the transaction and verify the sender to avoid hardware fault surprises
@@ -177,7 +212,7 @@ func SignTxEIP155(privateKeyStr string, tx *types.Transaction, chainID *big.Int)
 		return nil, err
 	}
 
-	address, err := Address(privateKeyStr)
+	address, err := Address(secret)
 	if err != nil {
 		return nil, err
 	}
@@ -189,11 +224,12 @@ func SignTxEIP155(privateKeyStr string, tx *types.Transaction, chainID *big.Int)
 	return signedTx, nil
 }
 
-func SignTx(privateKeyStr string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
-	privateKey, err := PrivateKey(privateKeyStr)
+func SignTx(secret *security.SecretBuffer, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	privateKey, err := PrivateKey(secret)
 	if err != nil {
 		return nil, err
 	}
+	defer zeroPrivateKey(privateKey)
 
 	signer := types.LatestSignerForChainID(chainID)
 
@@ -208,7 +244,7 @@ func SignTx(privateKeyStr string, tx *types.Transaction, chainID *big.Int) (*typ
 		return nil, err
 	}
 
-	address, err := Address(privateKeyStr)
+	address, err := Address(secret)
 	if err != nil {
 		return nil, err
 	}
@@ -220,10 +256,10 @@ func SignTx(privateKeyStr string, tx *types.Transaction, chainID *big.Int) (*typ
 	return signedTx, nil
 }
 
-func SignData(privateKeyStr string, mimeType string, data []byte) ([]byte, error) {
-	return SignHash(privateKeyStr, crypto.Keccak256(data))
+func SignData(secret *security.SecretBuffer, mimeType string, data []byte) ([]byte, error) {
+	return SignHash(secret, crypto.Keccak256(data))
 }
 
-func SignText(privateKeyStr string, text []byte) ([]byte, error) {
-	return SignHash(privateKeyStr, accounts.TextHash(text))
+func SignText(secret *security.SecretBuffer, text []byte) ([]byte, error) {
+	return SignHash(secret, accounts.TextHash(text))
 }
@@ -1,19 +1,34 @@
 package commonPrivateKey
 
 import (
+	"crypto/ecdsa"
+	"errors"
 	"math/big"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spark8899/gowallet/internal/security"
 )
 
+// secretKey wraps a test key in a SecretBuffer destroyed at the end of the test
+func secretKey(t *testing.T, key string) *security.SecretBuffer {
+	t.Helper()
+	secret, err := security.NewSecretBufferFromString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(secret.Destroy)
+	return secret
+}
+
 func TestPrivateKey(t *testing.T) {
 	privateKeyStr := "0x63e21d10fd50155dbba0e7d3f7431a400b84b4c2ac1ee38872f82448fe3ecfb9"
-	privateKey, err := PrivateKey(privateKeyStr)
+	privateKey, err := PrivateKey(secretKey(t, privateKeyStr))
 	if err != nil {
 		t.Error(err)
 	}
@@ -29,12 +44,14 @@ func TestPrivateKey(t *testing.T) {
 
 func TestPrivateKeyBytes(t *testing.T) {
 	privateKeyStr := "0x63e21d10fd50155dbba0e7d3f7431a400b84b4c2ac1ee38872f82448fe3ecfb9"
-	privateKeyBytes, err := PrivateKeyBytes(privateKeyStr)
+	privateKeyBytes, err := PrivateKeyBytes(secretKey(t, privateKeyStr))
 	if err != nil {
 		t.Error(err)
 	}
 
-	privateKeyHex := hexutil.Encode(privateKeyBytes)
+	defer privateKeyBytes.Destroy()
+
+	privateKeyHex := hexutil.Encode(privateKeyBytes.Bytes())
 	if privateKeyStr != privateKeyHex {
 		t.Errorf("expected match")
 	} else {
@@ -44,12 +61,13 @@ func TestPrivateKeyBytes(t *testing.T) {
 
 func TestPrivateKeyHex(t *testing.T) {
 	privateKeyStr := "0x63e21d10fd50155dbba0e7d3f7431a400b84b4c2ac1ee38872f82448fe3ecfb9"
-	privateKeyHex, err := PrivateKeyHex(privateKeyStr)
+	privateKeyHex, err := PrivateKeyHex(secretKey(t, privateKeyStr))
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
 	}
+	defer privateKeyHex.Destroy()
 
-	if privateKeyStr != privateKeyHex {
+	if privateKeyStr != string(privateKeyHex.Bytes()) {
 		t.Errorf("expected match")
 	} else {
 		t.Log("ok")
@@ -59,7 +77,7 @@ func TestPrivateKeyHex(t *testing.T) {
 func TestPublicKey(t *testing.T) {
 	privateKeyStr := "0x63e21d10fd50155dbba0e7d3f7431a400b84b4c2ac1ee38872f82448fe3ecfb9"
 	publicKeyStr := "0x046005c86a6718f66221713a77073c41291cc3abbfcd03aa4955e9b2b50dbf7f9b6672dad0d46ade61e382f79888a73ea7899d9419becf1d6c9ec2087c1188fa18"
-	publicKey, err := PublicKey(privateKeyStr)
+	publicKey, err := PublicKey(secretKey(t, privateKeyStr))
 	if err != nil {
 		t.Error(err)
 	}
@@ -76,7 +94,7 @@ func TestPublicKey(t *testing.T) {
 func TestPublicKeyBytes(t *testing.T) {
 	privateKeyStr := "0x63e21d10fd50155dbba0e7d3f7431a400b84b4c2ac1ee38872f82448fe3ecfb9"
 	publicKeyStr := "0x046005c86a6718f66221713a77073c41291cc3abbfcd03aa4955e9b2b50dbf7f9b6672dad0d46ade61e382f79888a73ea7899d9419becf1d6c9ec2087c1188fa18"
-	publicKeyBytes, err := PublicKeyBytes(privateKeyStr)
+	publicKeyBytes, err := PublicKeyBytes(secretKey(t, privateKeyStr))
 	if err != nil {
 		t.Error(err)
 	}
@@ -92,7 +110,7 @@ func TestPublicKeyBytes(t *testing.T) {
 func TestPublicKeyHex(t *testing.T) {
 	privateKeyStr := "0x63e21d10fd50155dbba0e7d3f7431a400b84b4c2ac1ee38872f82448fe3ecfb9"
 	publicKeyStr := "0x046005c86a6718f66221713a77073c41291cc3abbfcd03aa4955e9b2b50dbf7f9b6672dad0d46ade61e382f79888a73ea7899d9419becf1d6c9ec2087c1188fa18"
-	publicKeyHex, err := PublicKeyHex(privateKeyStr)
+	publicKeyHex, err := PublicKeyHex(secretKey(t, privateKeyStr))
 	if err != nil {
 		t.Error(err)
 	}
@@ -104,10 +122,23 @@ func TestPublicKeyHex(t *testing.T) {
 	}
 }
 
+func TestCompressedPublicKeyHex(t *testing.T) {
+	privateKeyStr := "0x63e21d10fd50155dbba0e7d3f7431a400b84b4c2ac1ee38872f82448fe3ecfb9"
+	// y of the uncompressed key above ends in 0x18, so the prefix is 02
+	publicKeyStr := "0x026005c86a6718f66221713a77073c41291cc3abbfcd03aa4955e9b2b50dbf7f9b"
+	publicKeyHex, err := CompressedPublicKeyHex(secretKey(t, privateKeyStr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if publicKeyStr != publicKeyHex {
+		t.Errorf("CompressedPublicKeyHex = %s, want %s", publicKeyHex, publicKeyStr)
+	}
+}
+
 func TestAddress(t *testing.T) {
 	privateKeyStr := "0x63e21d10fd50155dbba0e7d3f7431a400b84b4c2ac1ee38872f82448fe3ecfb9"
 	addressStr := "0xC49926C4124cEe1cbA0Ea94Ea31a6c12318df947"
-	address, err := Address(privateKeyStr)
+	address, err := Address(secretKey(t, privateKeyStr))
 	if err != nil {
 		t.Error(err)
 	}
@@ -123,7 +154,7 @@ func TestAddress(t *testing.T) {
 func TestAddressBytes(t *testing.T) {
 	privateKeyStr := "0x63e21d10fd50155dbba0e7d3f7431a400b84b4c2ac1ee38872f82448fe3ecfb9"
 	addressStr := "0xC49926C4124cEe1cbA0Ea94Ea31a6c12318df947"
-	addressBytes, err := AddressBytes(privateKeyStr)
+	addressBytes, err := AddressBytes(secretKey(t, privateKeyStr))
 	if err != nil {
 		t.Error(err)
 	}
@@ -139,7 +170,7 @@ func TestAddressBytes(t *testing.T) {
 func TestAddressHex(t *testing.T) {
 	privateKeyStr := "0x63e21d10fd50155dbba0e7d3f7431a400b84b4c2ac1ee38872f82448fe3ecfb9"
 	addressStr := "0xC49926C4124cEe1cbA0Ea94Ea31a6c12318df947"
-	addressHex, err := AddressHex(privateKeyStr)
+	addressHex, err := AddressHex(secretKey(t, privateKeyStr))
 	if err != nil {
 		t.Error(err)
 	}
@@ -155,7 +186,7 @@ func TestSignHash(t *testing.T) {
 	privateKeyStr := "0x63e21d10fd50155dbba0e7d3f7431a400b84b4c2ac1ee38872f82448fe3ecfb9"
 	data := []byte("hello")
 	hash := crypto.Keccak256Hash(data)
-	sig, err := SignHash(privateKeyStr, hash.Bytes())
+	sig, err := SignHash(secretKey(t, privateKeyStr), hash.Bytes())
 	if err != nil {
 		t.Error(err)
 	}
@@ -177,7 +208,7 @@ func TestSignTxEIP155(t *testing.T) {
 
 	tx := types.NewTransaction(nonce, toAddress, value, gasLimit, gasPrice, data)
 
-	signedTx, err := SignTxEIP155(privateKeyStr, tx, big.NewInt(42))
+	signedTx, err := SignTxEIP155(secretKey(t, privateKeyStr), tx, big.NewInt(42))
 	if err != nil {
 		t.Error(err)
 	}
@@ -207,7 +238,7 @@ func TestSignTx(t *testing.T) {
 
 	tx := types.NewTransaction(nonce, toAddress, value, gasLimit, gasPrice, data)
 
-	signedTx, err := SignTx(privateKeyStr, tx, big.NewInt(42))
+	signedTx, err := SignTx(secretKey(t, privateKeyStr), tx, big.NewInt(42))
 	if err != nil {
 		t.Error(err)
 	}
@@ -231,7 +262,7 @@ func TestSignData(t *testing.T) {
 	mimeType := "text/plain"
 	data := []byte("hello world")
 
-	signedData, err := SignData(privateKeyStr, mimeType, data)
+	signedData, err := SignData(secretKey(t, privateKeyStr), mimeType, data)
 	if err != nil {
 		t.Error(err)
 	}
@@ -247,7 +278,7 @@ func TestSignText(t *testing.T) {
 	privateKeyStr := "0x63e21d10fd50155dbba0e7d3f7431a400b84b4c2ac1ee38872f82448fe3ecfb9"
 	data := []byte("hello world")
 
-	signedTextData, err := SignText(privateKeyStr, data)
+	signedTextData, err := SignText(secretKey(t, privateKeyStr), data)
 	if err != nil {
 		t.Error(err)
 	}
@@ -258,3 +289,115 @@ func TestSignText(t *testing.T) {
 		t.Log("ok")
 	}
 }
+
+func TestPrivateKey_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{"too short", "0x63e21d10"},
+		{"too long", "0x63e21d10fd50155dbba0e7d3f7431a400b84b4c2ac1ee38872f82448fe3ecfb900"},
+		{"invalid hex", "0x" + strings.Repeat("zz", 32)},
+		{"zero", strings.Repeat("00", 32)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := PrivateKey(secretKey(t, tt.key)); err == nil {
+				t.Error("PrivateKey() expected error")
+			}
+		})
+	}
+
+	destroyed := secretKey(t, "0x63e21d10fd50155dbba0e7d3f7431a400b84b4c2ac1ee38872f82448fe3ecfb9")
+	destroyed.Destroy()
+	if _, err := PrivateKey(destroyed); err != security.ErrSecretDestroyed {
+		t.Errorf("PrivateKey() error = %v, want %v", err, security.ErrSecretDestroyed)
+	}
+}
+
+func TestPrivateKey_LeavesInputIntact(t *testing.T) {
+	key := "  0X63e21d10fd50155dbba0e7d3f7431a400b84b4c2ac1ee38872f82448fe3ecfb9\n"
+	secret := secretKey(t, key)
+	if _, err := AddressHex(secret); err != nil {
+		t.Fatalf("AddressHex() error: %v", err)
+	}
+	if string(secret.Bytes()) != key {
+		t.Error("AddressHex() modified the caller's secret")
+	}
+}
+
+func TestPrivateKeyHex_Wiped(t *testing.T) {
+	hexKey, err := PrivateKeyHex(secretKey(t, "63e21d10fd50155dbba0e7d3f7431a400b84b4c2ac1ee38872f82448fe3ecfb9"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hexKey.Locked() {
+		t.Log("secret memory is not mlock'd on this system")
+	}
+	hexKey.Destroy()
+	if hexKey.Bytes() != nil {
+		t.Error("destroyed buffer still exposes the key")
+	}
+}
+
+// captureWipes records every key passed to zeroPrivateKey during the test
+func captureWipes(t *testing.T) *[]*ecdsa.PrivateKey {
+	t.Helper()
+	var mu sync.Mutex
+	var wiped []*ecdsa.PrivateKey
+	orig := zeroPrivateKey
+	zeroPrivateKey = func(k *ecdsa.PrivateKey) {
+		mu.Lock()
+		wiped = append(wiped, k)
+		mu.Unlock()
+		orig(k)
+	}
+	t.Cleanup(func() { zeroPrivateKey = orig })
+	return &wiped
+}
+
+func TestOperations_WipeParsedKey(t *testing.T) {
+	key := "63e21d10fd50155dbba0e7d3f7431a400b84b4c2ac1ee38872f82448fe3ecfb9"
+	tx := types.NewTransaction(0, common.HexToAddress("0x1"), big.NewInt(1), 21000, big.NewInt(1), nil)
+	tests := []struct {
+		name string
+		run  func(secret *security.SecretBuffer) error
+	}{
+		{"PrivateKeyBytes", func(s *security.SecretBuffer) error {
+			b, err := PrivateKeyBytes(s)
+			b.Destroy()
+			return err
+		}},
+		{"PublicKeyHex", func(s *security.SecretBuffer) error { _, err := PublicKeyHex(s); return err }},
+		{"CompressedPublicKeyHex", func(s *security.SecretBuffer) error { _, err := CompressedPublicKeyHex(s); return err }},
+		{"AddressHex", func(s *security.SecretBuffer) error { _, err := AddressHex(s); return err }},
+		{"SignHash", func(s *security.SecretBuffer) error { _, err := SignHash(s, make([]byte, 32)); return err }},
+		{"SignHash error", func(s *security.SecretBuffer) error {
+			if _, err := SignHash(s, []byte("short")); err == nil {
+				return errors.New("expected error for short hash")
+			}
+			return nil
+		}},
+		{"SignHashRFC6979", func(s *security.SecretBuffer) error { _, err := SignHashRFC6979(s, make([]byte, 32)); return err }},
+		{"SignTx", func(s *security.SecretBuffer) error { _, err := SignTx(s, tx, big.NewInt(1)); return err }},
+		{"SignTxEIP155", func(s *security.SecretBuffer) error { _, err := SignTxEIP155(s, tx, big.NewInt(1)); return err }},
+		{"CheckWeakKeyHex", func(s *security.SecretBuffer) error { _, err := CheckWeakKeyHex(s); return err }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wiped := captureWipes(t)
+			if err := tt.run(secretKey(t, key)); err != nil {
+				t.Fatal(err)
+			}
+			if len(*wiped) == 0 {
+				t.Fatal("no parsed key was wiped")
+			}
+			for _, k := range *wiped {
+				if k.D.Sign() != 0 {
+					t.Errorf("parsed key still holds D = %x", k.D)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package commonPrivateKey
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spark8899/gowallet/internal/security"
+)
+
+// ErrInvalidKeyCount indicates a non-positive number of keys was requested
+var ErrInvalidKeyCount = errors.New("number of keys must be positive")
+
+// KeyPair is a generated private key and its Ethereum address. Call
+// Destroy once the key has been used.
+type KeyPair struct {
+	Address    common.Address
+	PrivateKey *ecdsa.PrivateKey
+}
+
+// String returns the "address:privateKey" form printed by genPrivateKey
+func (k *KeyPair) String() string {
+	return fmt.Sprintf("%v:%v", k.Address.Hex(), hexutil.Encode(crypto.FromECDSA(k.PrivateKey)))
+}
+
+// WriteTo writes the String form and a newline to w, encoding the key in a
+// buffer that is wiped afterwards instead of building a string
+func (k *KeyPair) WriteTo(w io.Writer) (int64, error) {
+	var raw [32]byte
+	var line [42 + 1 + 66 + 1]byte
+	defer security.ZeroBytes(raw[:])
+	defer security.ZeroBytes(line[:])
+
+	k.PrivateKey.D.FillBytes(raw[:])
+	n := copy(line[:], k.Address.Hex())
+	n += copy(line[n:], ":0x")
+	n += hex.Encode(line[n:], raw[:])
+	line[n] = '\n'
+	written, err := w.Write(line[:n+1])
+	return int64(written), err
+}
+
+// Destroy wipes the private key
+func (k *KeyPair) Destroy() {
+	zeroPrivateKey(k.PrivateKey)
+}
+
+// GenerateOptions controls bulk key generation
+type GenerateOptions struct {
+	// Workers is the number of generating goroutines, defaulting to GOMAXPROCS
+	Workers int
+	// Progress, if set, is called after each key is written with the
+	// number of keys written so far and the total
+	Progress func(done, total int)
+	// Rand is the randomness source, defaulting to crypto/rand. Setting it
+	// forces a single worker so output order is reproducible.
+	Rand io.Reader
+}
+
+// newPrivateKey reads 32-byte candidates from random until one is a valid
+// secp256k1 scalar in [1, n-1]. Unlike ecdsa.GenerateKey it consumes the
+// reader deterministically, so a seeded source always yields the same key.
+func newPrivateKey(random io.Reader) (*ecdsa.PrivateKey, error) {
+	candidate := make([]byte, 32)
+	defer security.ZeroBytes(candidate)
+
+	for {
+		if _, err := io.ReadFull(random, candidate); err != nil {
+			return nil, err
+		}
+		privateKey, err := crypto.ToECDSA(candidate)
+		if err == nil {
+			return privateKey, nil
+		}
+	}
+}
+
+func generateKey(random io.Reader) (*KeyPair, error) {
+	// Generate ECDSA private key
+	privateKey, err := newPrivateKey(random)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	// Validate private key strength
+	// Note: This is a defense-in-depth measure. newPrivateKey() should
+	// always produce valid keys, but we verify anyway for security.
+	if err := ValidatePrivateKey(privateKey); err != nil {
+		zeroPrivateKey(privateKey)
+		return nil, fmt.Errorf("generated key failed validation: %w", err)
+	}
+
+	// Derive address from public key
+	address := crypto.PubkeyToAddress(privateKey.PublicKey)
+
+	return &KeyPair{Address: address, PrivateKey: privateKey}, nil
+}
+
+// GenerateKeys generates num keys using a pool of workers and streams them
+// through the returned channel, which is closed when all keys have been
+// produced, an error occurs or ctx is cancelled. The error channel receives
+// at most one error and is closed afterwards.
+//
+// A nil random uses crypto/rand. A caller supplied reader is shared by all
+// workers and must be safe for concurrent use when workers > 1.
+func GenerateKeys(ctx context.Context, num int, workers int, random io.Reader) (<-chan *KeyPair, <-chan error) {
+	keys := make(chan *KeyPair, 64)
+	errc := make(chan error, 1)
+
+	if num <= 0 {
+		errc <- ErrInvalidKeyCount
+		close(keys)
+		close(errc)
+		return keys, errc
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > num {
+		workers = num
+	}
+	if random == nil {
+		random = rand.Reader
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	jobs := make(chan struct{})
+	var once sync.Once
+	fail := func(err error) {
+		once.Do(func() {
+			errc <- err
+			cancel()
+		})
+	}
+
+	// Feed one job per key so the total is exact regardless of worker count
+	go func() {
+		defer close(jobs)
+		for i := 0; i < num; i++ {
+			select {
+			case jobs <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range jobs {
+				key, err := generateKey(random)
+				if err != nil {
+					fail(err)
+					return
+				}
+				select {
+				case keys <- key:
+				case <-ctx.Done():
+					key.Destroy()
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		if err := ctx.Err(); err != nil {
+			fail(err)
+		}
+		cancel()
+		close(keys)
+		close(errc)
+	}()
+
+	return keys, errc
+}
+
+// GetGenerateKey generates num keys concurrently and writes them to w, one
+// "address:privateKey" line per key. Keys are written in completion order.
+// If generation fails or ctx is canceled, the keys generated so far are
+// still written, and the error tells how many made it to w.
+func GetGenerateKey(ctx context.Context, w io.Writer, num int, opts GenerateOptions) (err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	workers := opts.Workers
+	if opts.Rand != nil {
+		workers = 1
+	}
+	keys, errc := GenerateKeys(ctx, num, workers, opts.Rand)
+
+	bw := &wipingWriter{w: w, buf: make([]byte, 0, 4096)}
+	defer bw.wipe()
+	defer func() {
+		if flushErr := bw.Flush(); err == nil && flushErr != nil {
+			err = fmt.Errorf("failed to write key: %w", flushErr)
+		}
+		if err != nil {
+			err = fmt.Errorf("%w (%d of %d keys written)", err, bw.lines, num)
+		}
+	}()
+	done := 0
+	for key := range keys {
+		_, err := key.WriteTo(bw)
+		key.Destroy()
+		if err != nil {
+			cancel()
+			for key := range keys {
+				key.Destroy()
+			}
+			return fmt.Errorf("failed to write key: %w", err)
+		}
+		done++
+		if opts.Progress != nil {
+			opts.Progress(done, num)
+		}
+	}
+	return <-errc
+}
+
+// wipingWriter buffers output like bufio.Writer, but its buffer can be
+// wiped once the keys written through it are no longer needed. It counts
+// the complete lines that reached w.
+type wipingWriter struct {
+	w     io.Writer
+	buf   []byte
+	lines int
+}
+
+func (b *wipingWriter) Write(p []byte) (int, error) {
+	if len(b.buf)+len(p) > cap(b.buf) {
+		if err := b.Flush(); err != nil {
+			return 0, err
+		}
+	}
+	if len(p) > cap(b.buf) {
+		n, err := b.w.Write(p)
+		b.lines += bytes.Count(p[:n], []byte{'\n'})
+		return n, err
+	}
+	b.buf = append(b.buf, p...)
+	return len(p), nil
+}
+
+// Flush writes the buffered data and wipes the buffer
+func (b *wipingWriter) Flush() error {
+	if len(b.buf) == 0 {
+		return nil
+	}
+	n, err := b.w.Write(b.buf)
+	b.lines += bytes.Count(b.buf[:n], []byte{'\n'})
+	security.ZeroBytes(b.buf)
+	b.buf = b.buf[:0]
+	return err
+}
+
+func (b *wipingWriter) wipe() {
+	security.ZeroBytes(b.buf[:cap(b.buf)])
+}
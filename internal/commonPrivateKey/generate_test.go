@@ -0,0 +1,294 @@
+package commonPrivateKey
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spark8899/gowallet/internal/security"
+)
+
+func TestGenerateKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		num     int
+		workers int
+	}{
+		{"single key", 1, 0},
+		{"more workers than keys", 3, 8},
+		{"above old 1000 cap", 1500, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, errc := GenerateKeys(context.Background(), tt.num, tt.workers, nil)
+
+			seen := make(map[string]bool)
+			for key := range keys {
+				if crypto.PubkeyToAddress(key.PrivateKey.PublicKey) != key.Address {
+					t.Fatalf("address %s does not match private key", key.Address.Hex())
+				}
+				if seen[key.Address.Hex()] {
+					t.Fatalf("duplicate address %s", key.Address.Hex())
+				}
+				seen[key.Address.Hex()] = true
+			}
+			if err := <-errc; err != nil {
+				t.Fatalf("GenerateKeys() error: %v", err)
+			}
+			if len(seen) != tt.num {
+				t.Errorf("GenerateKeys() produced %d keys, want %d", len(seen), tt.num)
+			}
+		})
+	}
+}
+
+func TestGenerateKeys_InvalidCount(t *testing.T) {
+	for _, num := range []int{0, -1} {
+		keys, errc := GenerateKeys(context.Background(), num, 1, nil)
+		for range keys {
+			t.Fatal("expected no keys")
+		}
+		if err := <-errc; !errors.Is(err, ErrInvalidKeyCount) {
+			t.Errorf("GenerateKeys(%d) error = %v, want %v", num, err, ErrInvalidKeyCount)
+		}
+	}
+}
+
+func TestGenerateKeys_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	keys, errc := GenerateKeys(ctx, 1000000, 2, nil)
+
+	<-keys
+	cancel()
+	for range keys {
+	}
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Errorf("GenerateKeys() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestGetGenerateKey(t *testing.T) {
+	var buf bytes.Buffer
+	var lastDone, lastTotal int
+	err := GetGenerateKey(context.Background(), &buf, 25, GenerateOptions{
+		Workers: 3,
+		Progress: func(done, total int) {
+			lastDone, lastTotal = done, total
+		},
+	})
+	if err != nil {
+		t.Fatalf("GetGenerateKey() error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 25 {
+		t.Errorf("GetGenerateKey() wrote %d lines, want 25", len(lines))
+	}
+	for _, line := range lines {
+		parts := strings.Split(line, ":")
+		if len(parts) != 2 || len(parts[0]) != 42 || len(parts[1]) != 66 {
+			t.Errorf("invalid line format: %s", line)
+		}
+	}
+	if lastDone != 25 || lastTotal != 25 {
+		t.Errorf("Progress last called with (%d, %d), want (25, 25)", lastDone, lastTotal)
+	}
+}
+
+func TestGetGenerateKey_CancelFlushes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var buf bytes.Buffer
+	generated := 0
+	err := GetGenerateKey(ctx, &buf, 1000000, GenerateOptions{
+		Workers: 2,
+		Progress: func(done, total int) {
+			// far fewer keys than fill the write buffer
+			if generated = done; done == 1 {
+				cancel()
+			}
+		},
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("GetGenerateKey() error = %v, want %v", err, context.Canceled)
+	}
+	lines := strings.Count(buf.String(), "\n")
+	if lines == 0 || lines != generated {
+		t.Errorf("GetGenerateKey() wrote %d lines after generating %d keys", lines, generated)
+	}
+	if want := fmt.Sprintf("(%d of 1000000 keys written)", lines); !strings.Contains(err.Error(), want) {
+		t.Errorf("GetGenerateKey() error = %q, want it to contain %q", err, want)
+	}
+}
+
+// shortWriter accepts n bytes, then fails
+type shortWriter struct {
+	buf bytes.Buffer
+	n   int
+}
+
+func (w *shortWriter) Write(p []byte) (int, error) {
+	if w.buf.Len()+len(p) > w.n {
+		n, _ := w.buf.Write(p[:w.n-w.buf.Len()])
+		return n, io.ErrShortWrite
+	}
+	return w.buf.Write(p)
+}
+
+func TestGetGenerateKey_PartialWrite(t *testing.T) {
+	w := &shortWriter{n: 10000}
+	err := GetGenerateKey(context.Background(), w, 1000, GenerateOptions{Workers: 2})
+	if !errors.Is(err, io.ErrShortWrite) {
+		t.Fatalf("GetGenerateKey() error = %v, want %v", err, io.ErrShortWrite)
+	}
+	lines := strings.Count(w.buf.String(), "\n")
+	if want := fmt.Sprintf("(%d of 1000 keys written)", lines); !strings.Contains(err.Error(), want) {
+		t.Errorf("GetGenerateKey() error = %q, want it to contain %q", err, want)
+	}
+}
+
+func TestGetGenerateKey_Deterministic(t *testing.T) {
+	generate := func(seed string) string {
+		r, err := security.NewDeterministicReader([]byte(seed))
+		if err != nil {
+			t.Fatalf("NewDeterministicReader() error: %v", err)
+		}
+		var buf bytes.Buffer
+		// Workers is ignored when Rand is set
+		if err := GetGenerateKey(context.Background(), &buf, 10, GenerateOptions{Workers: 4, Rand: r}); err != nil {
+			t.Fatalf("GetGenerateKey() error: %v", err)
+		}
+		return buf.String()
+	}
+
+	first := generate("fixture")
+	if second := generate("fixture"); first != second {
+		t.Error("same seed produced different keys")
+	}
+	if other := generate("other"); first == other {
+		t.Error("different seeds produced the same keys")
+	}
+}
+
+func TestNewPrivateKey_RejectsOutOfRange(t *testing.T) {
+	// Zero and the curve order are invalid, the third candidate is 1
+	candidates := make([]byte, 0, 96)
+	candidates = append(candidates, make([]byte, 32)...)
+	candidates = append(candidates, curveOrder.Bytes()...)
+	one := make([]byte, 32)
+	one[31] = 1
+	candidates = append(candidates, one...)
+
+	key, err := newPrivateKey(bytes.NewReader(candidates))
+	if err != nil {
+		t.Fatalf("newPrivateKey() error: %v", err)
+	}
+	if key.D.Int64() != 1 {
+		t.Errorf("newPrivateKey() = %v, want 1", key.D)
+	}
+
+	if _, err := newPrivateKey(bytes.NewReader(nil)); err == nil {
+		t.Error("newPrivateKey() expected error on exhausted reader")
+	}
+}
+
+func TestGetGenerateKey_WipesKeys(t *testing.T) {
+	wiped := captureWipes(t)
+	var buf bytes.Buffer
+	if err := GetGenerateKey(context.Background(), &buf, 50, GenerateOptions{Workers: 4}); err != nil {
+		t.Fatalf("GetGenerateKey() error: %v", err)
+	}
+	if len(*wiped) != 50 {
+		t.Fatalf("wiped %d keys, want 50", len(*wiped))
+	}
+	for _, k := range *wiped {
+		if k.D.Sign() != 0 {
+			t.Errorf("generated key still holds D = %x", k.D)
+		}
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 50 {
+		t.Errorf("wrote %d lines, want 50", lines)
+	}
+}
+
+func TestKeyPair_WriteTo(t *testing.T) {
+	keys, errc := GenerateKeys(context.Background(), 1, 1, nil)
+	key := <-keys
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if _, err := key.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != key.String()+"\n" {
+		t.Errorf("WriteTo() = %q, want %q", buf.String(), key.String()+"\n")
+	}
+	key.Destroy()
+	if key.PrivateKey.D.Sign() != 0 {
+		t.Error("Destroy() did not wipe the key")
+	}
+}
+
+func TestWipingWriter(t *testing.T) {
+	var out bytes.Buffer
+	w := &wipingWriter{w: &out, buf: make([]byte, 0, 8)}
+	for _, p := range []string{"abc", "defgh", "ijklmnopqrst", "uv"} {
+		if _, err := w.Write([]byte(p)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	w.wipe()
+	if out.String() != "abcdefghijklmnopqrstuv" {
+		t.Errorf("output = %q", out.String())
+	}
+	if !bytes.Equal(w.buf[:cap(w.buf)], make([]byte, cap(w.buf))) {
+		t.Errorf("buffer not wiped: %q", w.buf[:cap(w.buf)])
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestGetGenerateKey_WriteError(t *testing.T) {
+	// Enough keys to overflow the bufio buffer
+	err := GetGenerateKey(context.Background(), failingWriter{}, 200, GenerateOptions{Workers: 2})
+	if err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Errorf("GetGenerateKey() error = %v, want write error", err)
+	}
+}
+
+// BenchmarkGetGenerateKey uses GOMAXPROCS workers; run with
+// -cpu 1,2,4,8 to see how throughput scales.
+func BenchmarkGetGenerateKey(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if err := GetGenerateKey(context.Background(), io.Discard, 100, GenerateOptions{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetGenerateKey_Workers(b *testing.B) {
+	for workers := 1; workers <= runtime.GOMAXPROCS(0); workers *= 2 {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if err := GetGenerateKey(context.Background(), io.Discard, 100, GenerateOptions{Workers: workers}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package commonPrivateKey
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spark8899/gowallet/internal/security"
+)
+
+// DecryptKeystore decrypts a Web3 Secret Storage (Ethereum keystore v3) JSON
+// file and returns its key as 0x-prefixed hex in a new SecretBuffer, the
+// same form the other functions in this package accept. A trailing newline
+// in password, as left by a password file, is ignored. The keystore package
+// only takes the password as a string, so it passes through one.
+func DecryptKeystore(keyJSON []byte, password *security.SecretBuffer) (*security.SecretBuffer, error) {
+	if password.Destroyed() {
+		return nil, security.ErrSecretDestroyed
+	}
+	key, err := keystore.DecryptKey(keyJSON, string(bytes.TrimRight(password.Bytes(), "\r\n")))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore: %w", err)
+	}
+	defer zeroPrivateKey(key.PrivateKey)
+
+	raw, err := security.NewSecretBufferFrom(crypto.FromECDSA(key.PrivateKey))
+	if err != nil {
+		return nil, err
+	}
+	defer raw.Destroy()
+
+	out, err := security.NewSecretBuffer(2 + hex.EncodedLen(raw.Len()))
+	if err != nil {
+		return nil, err
+	}
+	copy(out.Bytes(), "0x")
+	hex.Encode(out.Bytes()[2:], raw.Bytes())
+	return out, nil
+}
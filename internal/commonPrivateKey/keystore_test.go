@@ -0,0 +1,36 @@
+package commonPrivateKey
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestDecryptKeystore(t *testing.T) {
+	key := "0x63e21d10fd50155dbba0e7d3f7431a400b84b4c2ac1ee38872f82448fe3ecfb9"
+	privateKey, err := PrivateKey(secretKey(t, key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyJSON, err := keystore.EncryptKey(&keystore.Key{
+		Address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+		PrivateKey: privateKey,
+	}, "hunter2", keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secret, err := DecryptKeystore(keyJSON, secretKey(t, "hunter2\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer secret.Destroy()
+	if string(secret.Bytes()) != key {
+		t.Errorf("DecryptKeystore() = %s, want %s", secret.Bytes(), key)
+	}
+
+	if _, err := DecryptKeystore(keyJSON, secretKey(t, "hunter3")); err == nil {
+		t.Error("DecryptKeystore() with the wrong password succeeded")
+	}
+}
@@ -0,0 +1,275 @@
+package commonPrivateKey
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/spark8899/gowallet/internal/security"
+)
+
+var (
+	// ErrInvalidSignature indicates a signature that is malformed or does
+	// not verify
+	ErrInvalidSignature = errors.New("invalid signature")
+	// ErrHighS indicates a signature whose s is above half the curve order.
+	// It is valid ECDSA, but Bitcoin (BIP62/BIP146) and Ethereum
+	// (EIP-2) reject it as malleable.
+	ErrHighS = errors.New("signature s is not canonical (high S)")
+	// ErrHashLength indicates a hash that is not 32 bytes
+	ErrHashLength = errors.New("hash must be 32 bytes")
+	// ErrNoRecoveryID indicates a signature without v where one is needed
+	ErrNoRecoveryID = errors.New("signature has no recovery id (v)")
+)
+
+// Signature is a secp256k1 ECDSA signature with an optional recovery id
+type Signature struct {
+	R, S btcec.ModNScalar
+	// V is the recovery id, 0 or 1 (2 and 3 only for r above n, which is
+	// practically never), or -1 when the encoding carried none
+	V int
+}
+
+// ParseSignature reads a signature in any common encoding: 64-byte compact
+// r || s, 65-byte r || s || v with v as 0/1, 27/28 or EIP-155, or strict
+// DER (Bitcoin, HSMs, X.509). High-S signatures are accepted here; check
+// IsLowS where the protocol requires it.
+func ParseSignature(b []byte) (*Signature, error) {
+	sig := &Signature{V: -1}
+	switch {
+	case len(b) == 64 || len(b) == 65:
+		if overflow := sig.R.SetByteSlice(b[:32]); overflow || sig.R.IsZero() {
+			return nil, fmt.Errorf("%w: r out of range", ErrInvalidSignature)
+		}
+		if overflow := sig.S.SetByteSlice(b[32:64]); overflow || sig.S.IsZero() {
+			return nil, fmt.Errorf("%w: s out of range", ErrInvalidSignature)
+		}
+		if len(b) == 65 {
+			v, err := RecoveryID(uint64(b[64]))
+			if err != nil || v > 3 {
+				return nil, fmt.Errorf("%w: recovery id %d", ErrInvalidSignature, b[64])
+			}
+			sig.V = v
+		}
+	case len(b) > 0 && b[0] == 0x30:
+		der, err := ecdsa.ParseDERSignature(b)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+		}
+		sig.R, sig.S = der.R(), der.S()
+	default:
+		return nil, fmt.Errorf("%w: %d bytes, want 64, 65 or DER", ErrInvalidSignature, len(b))
+	}
+	return sig, nil
+}
+
+// ParseEIP2098 reads an EIP-2098 compact signature: r || yParityAndS, the
+// 64-byte form that stores v in the top bit of the (always low) s
+func ParseEIP2098(b []byte) (*Signature, error) {
+	if len(b) != 64 {
+		return nil, fmt.Errorf("%w: %d bytes, want 64", ErrInvalidSignature, len(b))
+	}
+	rs := bytes.Clone(b)
+	v := int(rs[32] >> 7)
+	rs[32] &= 0x7f
+	sig, err := ParseSignature(rs)
+	if err != nil {
+		return nil, err
+	}
+	sig.V = v
+	return sig, nil
+}
+
+// RecoveryID returns the recovery id encoded in v as written by the common
+// conventions: 0/1, 27/28 (eth_sign) or 35 + 2 * chainID + {0,1} (EIP-155)
+func RecoveryID(v uint64) (int, error) {
+	switch {
+	case v <= 3:
+		return int(v), nil
+	case v == 27 || v == 28:
+		return int(v - 27), nil
+	case v >= 35:
+		return int((v - 35) % 2), nil
+	}
+	return 0, fmt.Errorf("%w: recovery id %d", ErrInvalidSignature, v)
+}
+
+// Compact returns the 64-byte r || s encoding
+func (sig *Signature) Compact() []byte {
+	out := make([]byte, 64)
+	sig.R.PutBytesUnchecked(out[:32])
+	sig.S.PutBytesUnchecked(out[32:])
+	return out
+}
+
+// RSV returns the 65-byte r || s || v encoding of crypto.Sign, v being 0 or
+// 1; add 27 for eth_sign style signatures
+func (sig *Signature) RSV() ([]byte, error) {
+	if sig.V < 0 {
+		return nil, ErrNoRecoveryID
+	}
+	return append(sig.Compact(), byte(sig.V)), nil
+}
+
+// EIP2098 returns the 64-byte EIP-2098 encoding, v folded into the top bit
+// of s. It needs a low s and a v of 0 or 1.
+func (sig *Signature) EIP2098() ([]byte, error) {
+	if sig.V < 0 {
+		return nil, ErrNoRecoveryID
+	}
+	if !sig.IsLowS() {
+		return nil, ErrHighS
+	}
+	if sig.V > 1 {
+		return nil, fmt.Errorf("%w: recovery id %d has no EIP-2098 form", ErrInvalidSignature, sig.V)
+	}
+	out := sig.Compact()
+	out[32] |= byte(sig.V) << 7
+	return out, nil
+}
+
+// DER returns the strict DER encoding. Unlike btcec's Serialize it keeps a
+// high S as is, so conversions never change the signature.
+func (sig *Signature) DER() []byte {
+	r := derInteger(sig.R.Bytes())
+	s := derInteger(sig.S.Bytes())
+	out := []byte{0x30, byte(len(r) + len(s))}
+	out = append(out, r...)
+	return append(out, s...)
+}
+
+// derInteger encodes a positive 256-bit integer as a DER INTEGER: minimal
+// length, with a zero byte when the top bit is set
+func derInteger(b [32]byte) []byte {
+	v := bytes.TrimLeft(b[:], "\x00")
+	if len(v) == 0 || v[0]&0x80 != 0 {
+		v = append([]byte{0x00}, v...)
+	}
+	return append([]byte{0x02, byte(len(v))}, v...)
+}
+
+// IsLowS reports whether s is at most half the curve order
+func (sig *Signature) IsLowS() bool {
+	return !sig.S.IsOverHalfOrder()
+}
+
+// NormalizeS replaces a high s with n - s, the other valid signature of the
+// same hash and key, and flips the parity in v to match. It reports whether
+// the signature changed.
+func (sig *Signature) NormalizeS() bool {
+	if sig.IsLowS() {
+		return false
+	}
+	sig.S.Negate()
+	if sig.V >= 0 {
+		sig.V ^= 1
+	}
+	return true
+}
+
+// Verify checks the signature of hash by pub. If the signature carries v,
+// it must also recover pub.
+func (sig *Signature) Verify(hash []byte, pub *btcec.PublicKey) error {
+	if len(hash) != 32 {
+		return ErrHashLength
+	}
+	if !ecdsa.NewSignature(&sig.R, &sig.S).Verify(hash, pub) {
+		return ErrInvalidSignature
+	}
+	if sig.V >= 0 {
+		recovered, err := sig.recover(hash, sig.V)
+		if err != nil || !recovered.IsEqual(pub) {
+			return fmt.Errorf("%w: recovery id %d does not recover the public key", ErrInvalidSignature, sig.V)
+		}
+	}
+	return nil
+}
+
+// SetRecoveryID sets V to the recovery id under which the signature of
+// hash recovers pub, for converting DER or compact signatures to r || s || v
+func (sig *Signature) SetRecoveryID(hash []byte, pub *btcec.PublicKey) error {
+	if len(hash) != 32 {
+		return ErrHashLength
+	}
+	for v := 0; v < 4; v++ {
+		if recovered, err := sig.recover(hash, v); err == nil && recovered.IsEqual(pub) {
+			sig.V = v
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: no recovery id recovers the public key", ErrInvalidSignature)
+}
+
+// RecoverPublicKey returns the public key that made the signature of hash,
+// which needs v. High-S signatures recover as well; as with ecrecover, it is
+// up to the caller to reject them.
+func (sig *Signature) RecoverPublicKey(hash []byte) (*btcec.PublicKey, error) {
+	if len(hash) != 32 {
+		return nil, ErrHashLength
+	}
+	if sig.V < 0 {
+		return nil, ErrNoRecoveryID
+	}
+	pub, err := sig.recover(hash, sig.V)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	return pub, nil
+}
+
+// recover returns the public key the signature of hash recovers to with
+// recovery id v
+func (sig *Signature) recover(hash []byte, v int) (*btcec.PublicKey, error) {
+	// btcec's compact format: 27 + v (+4 for compressed keys) || r || s
+	compact := append([]byte{27 + byte(v)}, sig.Compact()...)
+	pub, _, err := ecdsa.RecoverCompact(compact, hash)
+	return pub, err
+}
+
+// VerifySignature checks sig (compact, r || s || v or DER) of a 32-byte
+// hash by the public key pub (33 or 65 bytes). High-S signatures fail with
+// ErrHighS unless allowHighS is set.
+func VerifySignature(pub, hash, sig []byte, allowHighS bool) error {
+	key, err := btcec.ParsePubKey(pub)
+	if err != nil {
+		return fmt.Errorf("invalid public key: %w", err)
+	}
+	parsed, err := ParseSignature(sig)
+	if err != nil {
+		return err
+	}
+	if err := parsed.Verify(hash, key); err != nil {
+		return err
+	}
+	if !allowHighS && !parsed.IsLowS() {
+		return ErrHighS
+	}
+	return nil
+}
+
+// SignHashRFC6979 signs a 32-byte hash with the RFC6979 deterministic nonce
+// and returns the low-S signature with its recovery id. SignHash returns
+// the same signature as r || s || v.
+func SignHashRFC6979(secret *security.SecretBuffer, hash []byte) (*Signature, error) {
+	if len(hash) != 32 {
+		return nil, ErrHashLength
+	}
+	raw, err := PrivateKeyBytes(secret)
+	if err != nil {
+		return nil, err
+	}
+	defer raw.Destroy()
+	key, _ := btcec.PrivKeyFromBytes(raw.Bytes())
+	defer key.Zero()
+
+	// SignCompact is RFC6979 with low S: 27 + v + 4 (compressed) || r || s
+	compact := ecdsa.SignCompact(key, hash, true)
+	sig, err := ParseSignature(compact[1:])
+	if err != nil {
+		return nil, err
+	}
+	sig.V = int(compact[0] - 27 - 4)
+	return sig, nil
+}
@@ -0,0 +1,251 @@
+package commonPrivateKey
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func mustPub(t *testing.T, key string) *btcec.PublicKey {
+	t.Helper()
+	b, err := CompressedPublicKeyBytes(secretKey(t, key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := btcec.ParsePubKey(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pub
+}
+
+// TestSignHashRFC6979 checks the secp256k1 RFC6979 (HMAC-SHA256) vectors
+// shared by bitcoinjs, python-ecdsa and Trezor, signing SHA-256 of the message
+func TestSignHashRFC6979(t *testing.T) {
+	tests := []struct {
+		key, message, r, s string
+	}{
+		{"0000000000000000000000000000000000000000000000000000000000000001", "Satoshi Nakamoto",
+			"934b1ea10a4b3c1757e2b0c017d0b6143ce3c9a7e6a4a49860d7a6ab210ee3d8", "2442ce9d2b916064108014783e923ec36b49743e2ffa1c4496f01a512aafd9e5"},
+		{"0000000000000000000000000000000000000000000000000000000000000001", "All those moments will be lost in time, like tears in rain. Time to die...",
+			"8600dbd41e348fe5c9465ab92d23e3db8b98b873beecd930736488696438cb6b", "547fe64427496db33bf66019dacbf0039c04199abb0122918601db38a72cfc21"},
+		{"f8b8af8ce3c7cca5e300d33939540c10d45ce001b8f252bfbc57ba0342904181", "Alan Turing",
+			"7063ae83e7f62bbb171798131b4a0564b956930092b33b07b395615d9ec7e15c", "58dfcc1e00a35e1572f366ffe34ba0fc47db1e7189759b9fb233c5b05ab388ea"},
+		{"e91671c46231f833a6406ccbea0e3e392c76c167bac1cb013f6f1013980455c2", "There is a computer disease that anybody who works with computers knows about. It's a very serious disease and it interferes completely with the work. The trouble with computers is that you 'play' with them!",
+			"b552edd27580141f3b2a5463048cb7cd3e047b97c9f98076c32dbdf85a68718b", "279fa72dd19bfae05577e06c7c0c1900c371fcd5893f7e1d56a37d30174671f6"},
+	}
+	for _, tt := range tests {
+		hash := sha256.Sum256([]byte(tt.message))
+		sig, err := SignHashRFC6979(secretKey(t, tt.key), hash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		compact := sig.Compact()
+		if got := hex.EncodeToString(compact); got != tt.r+tt.s {
+			t.Errorf("SignHashRFC6979(%s, %q) = %s, want %s%s", tt.key, tt.message, got, tt.r, tt.s)
+		}
+		if err := sig.Verify(hash[:], mustPub(t, tt.key)); err != nil {
+			t.Errorf("signature of %q does not verify: %v", tt.message, err)
+		}
+
+		// SignHash is the same deterministic, low-S signature as r || s || v
+		rsv, err := SignHash(secretKey(t, tt.key), hash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, _ := sig.RSV(); !bytes.Equal(rsv, want) {
+			t.Errorf("SignHash = %x, want %x", rsv, want)
+		}
+	}
+
+	if _, err := SignHashRFC6979(secretKey(t, tests[0].key), []byte("short")); !errors.Is(err, ErrHashLength) {
+		t.Errorf("SignHashRFC6979(short hash) error = %v, want ErrHashLength", err)
+	}
+}
+
+func TestSignatureEncodings(t *testing.T) {
+	key := "0x0000000000000000000000000000000000000000000000000000000000000001"
+	hash := sha256.Sum256([]byte("Satoshi Nakamoto"))
+	pub := mustPub(t, key)
+	sig, err := SignHashRFC6979(secretKey(t, key), hash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	der := sig.DER()
+	if want := "3045022100934b1ea10a4b3c1757e2b0c017d0b6143ce3c9a7e6a4a49860d7a6ab210ee3d802202442ce9d2b916064108014783e923ec36b49743e2ffa1c4496f01a512aafd9e5"; hex.EncodeToString(der) != want {
+		t.Errorf("DER = %x, want %s", der, want)
+	}
+	fromDER, err := ParseSignature(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fromDER.V != -1 || !bytes.Equal(fromDER.Compact(), sig.Compact()) {
+		t.Errorf("ParseSignature(DER) = %x v=%d", fromDER.Compact(), fromDER.V)
+	}
+	if _, err := fromDER.RSV(); !errors.Is(err, ErrNoRecoveryID) {
+		t.Errorf("RSV without v error = %v, want ErrNoRecoveryID", err)
+	}
+	if err := fromDER.SetRecoveryID(hash[:], pub); err != nil || fromDER.V != sig.V {
+		t.Errorf("SetRecoveryID = %d, %v, want %d", fromDER.V, err, sig.V)
+	}
+
+	// Ethereum's 27/28 v
+	rsv, _ := sig.RSV()
+	eth := append(sig.Compact(), rsv[64]+27)
+	if parsed, err := ParseSignature(eth); err != nil || parsed.V != sig.V {
+		t.Errorf("ParseSignature(v+27) = %v, %v", parsed, err)
+	}
+
+	// go-ethereum recovers the same key from r || s || v
+	recovered, err := crypto.Ecrecover(hash[:], rsv)
+	if err != nil || !bytes.Equal(recovered, pub.SerializeUncompressed()) {
+		t.Errorf("Ecrecover = %x, %v", recovered, err)
+	}
+
+	for _, bad := range [][]byte{nil, make([]byte, 64), der[:len(der)-1], append(sig.Compact(), 5)} {
+		if _, err := ParseSignature(bad); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("ParseSignature(%x) error = %v, want ErrInvalidSignature", bad, err)
+		}
+	}
+}
+
+func TestLowS(t *testing.T) {
+	key := "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+	hash := crypto.Keccak256([]byte("hello"))
+	pub := mustPub(t, key)
+	sig, err := SignHashRFC6979(secretKey(t, key), hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sig.IsLowS() {
+		t.Fatal("SignHashRFC6979 returned a high S")
+	}
+	rsv, _ := sig.RSV()
+
+	// n - s is the malleated twin: valid ECDSA with the other v
+	high := *sig
+	high.S.Negate()
+	high.V ^= 1
+	if high.IsLowS() {
+		t.Fatal("negated s is still low")
+	}
+	if err := high.Verify(hash, pub); err != nil {
+		t.Errorf("high-S twin does not verify: %v", err)
+	}
+	highRSV, _ := high.RSV()
+	if err := VerifySignature(pub.SerializeCompressed(), hash, highRSV, false); !errors.Is(err, ErrHighS) {
+		t.Errorf("VerifySignature(high S) error = %v, want ErrHighS", err)
+	}
+	if err := VerifySignature(pub.SerializeCompressed(), hash, highRSV, true); err != nil {
+		t.Errorf("VerifySignature(high S, allowed) error = %v", err)
+	}
+	// DER keeps the high S rather than normalizing it
+	if parsed, err := ParseSignature(high.DER()); err != nil || parsed.IsLowS() {
+		t.Errorf("DER round trip of a high S = %v, %v", parsed, err)
+	}
+
+	if !high.NormalizeS() {
+		t.Error("NormalizeS did not change a high S")
+	}
+	if normalized, _ := high.RSV(); !bytes.Equal(normalized, rsv) {
+		t.Errorf("NormalizeS = %x, want %x", normalized, rsv)
+	}
+	if high.NormalizeS() {
+		t.Error("NormalizeS changed a low S")
+	}
+
+	// wrong v, key or hash
+	wrongV := append(sig.Compact(), byte(sig.V^1))
+	if err := VerifySignature(pub.SerializeUncompressed(), hash, wrongV, false); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("VerifySignature(wrong v) error = %v, want ErrInvalidSignature", err)
+	}
+	if err := VerifySignature(mustPub(t, "0x0000000000000000000000000000000000000000000000000000000000000001").SerializeCompressed(), hash, rsv, false); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("VerifySignature(other key) error = %v, want ErrInvalidSignature", err)
+	}
+	if err := VerifySignature(pub.SerializeCompressed(), hash[:31], rsv, false); !errors.Is(err, ErrHashLength) {
+		t.Errorf("VerifySignature(short hash) error = %v, want ErrHashLength", err)
+	}
+}
+
+// TestEIP2098 checks the test cases of EIP-2098, both EIP-191 signatures of
+// the same key
+func TestEIP2098(t *testing.T) {
+	key := "0x1234567890123456789012345678901234567890123456789012345678901234"
+	tests := []struct {
+		message, rsv, compact string
+	}{
+		{"Hello World",
+			"68a020a209d3d56c46f38cc50a33f704f4a9a10a59377f8dd762ac66910e9b907e865ad05c4035ab5792787d4a0297a43617ae897930a6fe4d822b8faea520641b",
+			"68a020a209d3d56c46f38cc50a33f704f4a9a10a59377f8dd762ac66910e9b907e865ad05c4035ab5792787d4a0297a43617ae897930a6fe4d822b8faea52064"},
+		{"It's a small(er) world",
+			"9328da16089fcba9bececa81663203989f2df5fe1faa6291a45381c81bd17f76139c6d6b623b42da56557e5e734a43dc83345ddfadec52cbe24d0cc64f5507931c",
+			"9328da16089fcba9bececa81663203989f2df5fe1faa6291a45381c81bd17f76939c6d6b623b42da56557e5e734a43dc83345ddfadec52cbe24d0cc64f550793"},
+	}
+	pub := mustPub(t, key)
+	for _, tt := range tests {
+		rsv, _ := hex.DecodeString(tt.rsv)
+		compact, _ := hex.DecodeString(tt.compact)
+		sig, err := ParseSignature(rsv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := sig.EIP2098()
+		if err != nil || !bytes.Equal(got, compact) {
+			t.Errorf("EIP2098(%q) = %x, %v, want %s", tt.message, got, err, tt.compact)
+		}
+
+		parsed, err := ParseEIP2098(compact)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hash := accounts.TextHash([]byte(tt.message))
+		recovered, err := parsed.RecoverPublicKey(hash)
+		if err != nil || !recovered.IsEqual(pub) {
+			t.Errorf("RecoverPublicKey(%q) = %v, %v", tt.message, recovered, err)
+		}
+	}
+
+	high := Signature{V: 0}
+	high.R.SetInt(1)
+	high.S.SetInt(1)
+	high.S.Negate()
+	if _, err := high.EIP2098(); !errors.Is(err, ErrHighS) {
+		t.Errorf("EIP2098(high S) error = %v, want ErrHighS", err)
+	}
+	if _, err := ParseEIP2098(make([]byte, 65)); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("ParseEIP2098(65 bytes) error = %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestRecoveryID(t *testing.T) {
+	tests := []struct {
+		v    uint64
+		want int
+	}{
+		{0, 0}, {1, 1}, {27, 0}, {28, 1},
+		{37, 0}, {38, 1}, // EIP-155 chain 1
+		{2*137 + 36, 1}, // EIP-155 chain 137
+	}
+	for _, tt := range tests {
+		if got, err := RecoveryID(tt.v); err != nil || got != tt.want {
+			t.Errorf("RecoveryID(%d) = %d, %v, want %d", tt.v, got, err, tt.want)
+		}
+	}
+	for _, v := range []uint64{4, 26, 29, 34} {
+		if _, err := RecoveryID(v); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("RecoveryID(%d) error = %v, want ErrInvalidSignature", v, err)
+		}
+	}
+
+	sig := Signature{V: -1}
+	if _, err := sig.RecoverPublicKey(make([]byte, 32)); !errors.Is(err, ErrNoRecoveryID) {
+		t.Errorf("RecoverPublicKey without v error = %v, want ErrNoRecoveryID", err)
+	}
+}
@@ -0,0 +1,298 @@
+package commonPrivateKey
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"math/bits"
+	"strings"
+	"sync"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spark8899/gowallet/internal/security"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// WeaknessKind classifies why a private key should not be funded
+type WeaknessKind string
+
+const (
+	// WeakBrainwallet is the SHA256 or Keccak256 of a dictionary passphrase
+	WeakBrainwallet WeaknessKind = "brainwallet"
+	// WeakTestKey is a default account of a development chain
+	WeakTestKey WeaknessKind = "test key"
+	// WeakSmallKey is close to 0 or the curve order, or a power of two
+	WeakSmallKey WeaknessKind = "small key"
+	// WeakLowHammingWeight has very few or very many one bits
+	WeakLowHammingWeight WeaknessKind = "hamming weight"
+	// WeakRepeatedNibbles has long nibble runs, few distinct nibbles or a
+	// repeating byte pattern
+	WeakRepeatedNibbles WeaknessKind = "repeated nibbles"
+)
+
+// Weakness describes one reason a private key is weak or publicly known
+type Weakness struct {
+	Kind   WeaknessKind
+	Detail string
+}
+
+func (w Weakness) String() string {
+	return fmt.Sprintf("%s: %s", w.Kind, w.Detail)
+}
+
+const (
+	// A uniformly random key has 128 +- 8 one bits; these bounds are 8
+	// standard deviations out, about 1e-15 for a random key
+	minKeyHammingWeight = 64
+	maxKeyHammingWeight = 192
+	// Chance of a run of 10 identical nibbles in a random key is ~6e-11
+	maxNibbleRun = 10
+	// Chance of at most 5 distinct nibbles in a random key is ~1e-29
+	minDistinctNibbles = 6
+	// Keys that repeat every 16 bytes or less
+	maxPatternPeriod = 16
+	// Number of default accounts derived from each development mnemonic
+	devAccounts = 20
+)
+
+// devMnemonics are the well-known mnemonics of local development chains.
+// Their accounts are funded on every fresh node and swept on mainnet within
+// seconds.
+var devMnemonics = []struct {
+	name     string
+	mnemonic string
+}{
+	{"Hardhat/Anvil", "test test test test test test test test test test test junk"},
+	{"Ganache (--deterministic)", "myth like bonus scare over problem client lizard pioneer submit female collect"},
+}
+
+// smallKeyBound flags keys below 2^64 or within 2^64 of the curve order
+var smallKeyBound = new(big.Int).Lsh(big.NewInt(1), 64)
+
+//go:embed brainwallet.txt
+var brainwalletDictionary []byte
+
+// weakKeyDB holds the hashed dictionary and development keys
+type weakKeyDB struct {
+	mu          sync.RWMutex
+	brainwallet map[[32]byte]string
+	testKeys    map[[32]byte]string
+}
+
+var (
+	defaultWeakKeyDB     *weakKeyDB
+	defaultWeakKeyDBOnce sync.Once
+	defaultWeakKeyDBErr  error
+)
+
+// weakKeys builds the default database on first use, since deriving the
+// development accounts takes a few milliseconds
+func weakKeys() (*weakKeyDB, error) {
+	defaultWeakKeyDBOnce.Do(func() {
+		db := &weakKeyDB{
+			brainwallet: make(map[[32]byte]string),
+			testKeys:    make(map[[32]byte]string),
+		}
+		if err := db.addDictionary(bytes.NewReader(brainwalletDictionary)); err != nil {
+			defaultWeakKeyDBErr = err
+			return
+		}
+		for _, word := range bip39.GetWordList() {
+			db.addPassphrase(word)
+		}
+		for _, dev := range devMnemonics {
+			if err := db.addDevAccounts(dev.name, dev.mnemonic); err != nil {
+				defaultWeakKeyDBErr = err
+				return
+			}
+		}
+		defaultWeakKeyDB = db
+	})
+	return defaultWeakKeyDB, defaultWeakKeyDBErr
+}
+
+func (db *weakKeyDB) addPassphrase(passphrase string) {
+	db.brainwallet[sha256.Sum256([]byte(passphrase))] = fmt.Sprintf("sha256(%q)", passphrase)
+	var keccak [32]byte
+	copy(keccak[:], crypto.Keccak256([]byte(passphrase)))
+	db.brainwallet[keccak] = fmt.Sprintf("keccak256(%q)", passphrase)
+}
+
+func (db *weakKeyDB) addDictionary(r io.Reader) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		db.addPassphrase(line)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read brainwallet dictionary: %w", err)
+	}
+	return nil
+}
+
+// addDevAccounts derives m/44'/60'/0'/0/i for the first devAccounts indexes
+func (db *weakKeyDB) addDevAccounts(name, mnemonic string) error {
+	seed := bip39.NewSeed(mnemonic, "")
+	master, err := hdkeychain.NewMaster(seed, &chaincfg.MainNetParams)
+	if err != nil {
+		return fmt.Errorf("failed to derive %s accounts: %w", name, err)
+	}
+	account := master
+	for _, i := range []uint32{44 + hdkeychain.HardenedKeyStart, 60 + hdkeychain.HardenedKeyStart, hdkeychain.HardenedKeyStart, 0} {
+		if account, err = account.Derive(i); err != nil {
+			return fmt.Errorf("failed to derive %s accounts: %w", name, err)
+		}
+	}
+	for i := uint32(0); i < devAccounts; i++ {
+		child, err := account.Derive(i)
+		if err != nil {
+			return fmt.Errorf("failed to derive %s account %d: %w", name, i, err)
+		}
+		key, err := child.ECPrivKey()
+		if err != nil {
+			return fmt.Errorf("failed to derive %s account %d: %w", name, i, err)
+		}
+		var k [32]byte
+		key.Key.PutBytes(&k)
+		db.testKeys[k] = fmt.Sprintf("%s default account #%d", name, i)
+	}
+	return nil
+}
+
+// LoadBrainwalletDictionary adds one passphrase per line from r to the
+// brainwallet dictionary used by CheckWeakKey
+func LoadBrainwalletDictionary(r io.Reader) error {
+	db, err := weakKeys()
+	if err != nil {
+		return err
+	}
+	return db.addDictionary(r)
+}
+
+// CheckWeakKey reports every reason privateKey is weak or publicly known:
+// brainwallet passphrases from the dictionary, development chain default
+// accounts, keys near 0 or the curve order, and bit patterns that a random
+// key has a negligible chance of showing. An empty result means no
+// weakness was found, not that the key is safe.
+func CheckWeakKey(privateKey *ecdsa.PrivateKey) ([]Weakness, error) {
+	if err := ValidatePrivateKey(privateKey); err != nil && !errors.Is(err, ErrPrivateKeyTooSmall) {
+		return nil, err
+	}
+	db, err := weakKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	key := crypto.FromECDSA(privateKey)
+	defer security.ZeroBytes(key)
+	var k [32]byte
+	copy(k[:], key)
+	defer security.ZeroBytes(k[:])
+
+	var weaknesses []Weakness
+	db.mu.RLock()
+	if source, ok := db.brainwallet[k]; ok {
+		weaknesses = append(weaknesses, Weakness{WeakBrainwallet, source})
+	}
+	if source, ok := db.testKeys[k]; ok {
+		weaknesses = append(weaknesses, Weakness{WeakTestKey, source})
+	}
+	db.mu.RUnlock()
+
+	weaknesses = append(weaknesses, smallKeyWeaknesses(privateKey.D)...)
+	weaknesses = append(weaknesses, patternWeaknesses(key)...)
+	return weaknesses, nil
+}
+
+// CheckWeakKeyHex parses the hex private key in secret and runs CheckWeakKey
+func CheckWeakKeyHex(secret *security.SecretBuffer) ([]Weakness, error) {
+	privateKey, err := PrivateKey(secret)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroPrivateKey(privateKey)
+	return CheckWeakKey(privateKey)
+}
+
+// smallKeyWeaknesses flags the 0x...01 family: tiny keys, keys just below
+// the curve order (whose public keys are negations of tiny keys') and
+// powers of two
+func smallKeyWeaknesses(d *big.Int) []Weakness {
+	// n-d and d&(d-1) are as secret as d, so they are wiped too
+	distance := new(big.Int).Sub(curveOrder, d)
+	defer security.ZeroBigInt(distance)
+	lowBits := new(big.Int).Sub(d, big.NewInt(1))
+	defer security.ZeroBigInt(lowBits)
+	lowBits.And(d, lowBits)
+
+	var weaknesses []Weakness
+	if d.Cmp(smallKeyBound) < 0 {
+		weaknesses = append(weaknesses, Weakness{WeakSmallKey, fmt.Sprintf("key is %s, below 2^64", d)})
+	} else if distance.Cmp(smallKeyBound) < 0 {
+		weaknesses = append(weaknesses, Weakness{WeakSmallKey, fmt.Sprintf("key is n-%s, within 2^64 of the curve order", distance)})
+	} else if d.BitLen() > 0 && lowBits.Sign() == 0 {
+		weaknesses = append(weaknesses, Weakness{WeakSmallKey, fmt.Sprintf("key is 2^%d", d.BitLen()-1)})
+	}
+	return weaknesses
+}
+
+func patternWeaknesses(key []byte) []Weakness {
+	var weaknesses []Weakness
+
+	ones := 0
+	for _, b := range key {
+		ones += bits.OnesCount8(b)
+	}
+	if ones < minKeyHammingWeight || ones > maxKeyHammingWeight {
+		weaknesses = append(weaknesses, Weakness{WeakLowHammingWeight,
+			fmt.Sprintf("%d of 256 bits set (random keys have about 128)", ones)})
+	}
+
+	var seen [16]bool
+	distinct, longest, run := 0, 0, 0
+	var prev byte = 0xff
+	for _, b := range key {
+		for _, nibble := range []byte{b >> 4, b & 0x0f} {
+			if !seen[nibble] {
+				seen[nibble] = true
+				distinct++
+			}
+			if nibble == prev {
+				run++
+			} else {
+				run = 1
+			}
+			prev = nibble
+			if run > longest {
+				longest = run
+			}
+		}
+	}
+	if longest >= maxNibbleRun {
+		weaknesses = append(weaknesses, Weakness{WeakRepeatedNibbles, fmt.Sprintf("%d identical nibbles in a row", longest)})
+	}
+	if distinct < minDistinctNibbles {
+		weaknesses = append(weaknesses, Weakness{WeakRepeatedNibbles, fmt.Sprintf("only %d distinct hex digits", distinct)})
+	}
+	for p := 1; p <= maxPatternPeriod; p++ {
+		if bytes.Equal(key[p:], key[:len(key)-p]) {
+			weaknesses = append(weaknesses, Weakness{WeakRepeatedNibbles, fmt.Sprintf("repeats every %d bytes", p)})
+			break
+		}
+	}
+	return weaknesses
+}
@@ -0,0 +1,115 @@
+package commonPrivateKey
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func hasWeakness(weaknesses []Weakness, kind WeaknessKind) bool {
+	for _, w := range weaknesses {
+		if w.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCheckWeakKey(t *testing.T) {
+	sha := func(s string) string {
+		sum := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(sum[:])
+	}
+
+	tests := []struct {
+		name   string
+		key    string
+		kind   WeaknessKind
+		detail string
+	}{
+		{"Hardhat account 0", "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80", WeakTestKey, "Hardhat/Anvil default account #0"},
+		{"Hardhat account 1", "59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d", WeakTestKey, "#1"},
+		{"Ganache account 0", "4f3edf983ac636a65a842ce7c78d9aa706d3b113bce9c46f30d7d21715b23b1d", WeakTestKey, "Ganache"},
+		{"brainwallet sha256", sha("correct horse battery staple"), WeakBrainwallet, "sha256"},
+		{"brainwallet keccak256", hex.EncodeToString(crypto.Keccak256([]byte("satoshi"))), WeakBrainwallet, "keccak256"},
+		{"brainwallet BIP39 word", sha("abandon"), WeakBrainwallet, "abandon"},
+		{"key one", strings.Repeat("0", 63) + "1", WeakSmallKey, "below 2^64"},
+		{"n-1", "fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140", WeakSmallKey, "n-1"},
+		{"power of two", "8" + strings.Repeat("0", 63), WeakSmallKey, "2^255"},
+		{"low hamming weight", "0100000000000000100000000000000010000000000000001000000000000001", WeakLowHammingWeight, "bits set"},
+		{"repeated nibbles", "9e885d952ad362ca" + strings.Repeat("7", 16) + "eb4efe34a8e91bd29e885d952ad362ca", WeakRepeatedNibbles, "in a row"},
+		{"few distinct nibbles", "deadbeefbeefdeadbeefdeaddeadbeefdeadbeefbeefdeadbeefdeaddeadbeef", WeakRepeatedNibbles, "distinct"},
+		{"repeating pattern", strings.Repeat("9e885d952ad362ca", 4), WeakRepeatedNibbles, "repeats every 8 bytes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			weaknesses, err := CheckWeakKeyHex(secretKey(t, tt.key))
+			if err != nil {
+				t.Fatalf("CheckWeakKeyHex() error: %v", err)
+			}
+			found := false
+			for _, w := range weaknesses {
+				if w.Kind == tt.kind && strings.Contains(w.Detail, tt.detail) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("CheckWeakKeyHex() = %v, want %s containing %q", weaknesses, tt.kind, tt.detail)
+			}
+		})
+	}
+}
+
+func TestCheckWeakKey_RandomKeys(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		weaknesses, err := CheckWeakKey(key)
+		if err != nil {
+			t.Fatalf("CheckWeakKey() error: %v", err)
+		}
+		if len(weaknesses) != 0 {
+			t.Errorf("random key %x flagged: %v", crypto.FromECDSA(key), weaknesses)
+		}
+	}
+}
+
+func TestCheckWeakKey_Invalid(t *testing.T) {
+	if _, err := CheckWeakKey(nil); err != ErrPrivateKeyNil {
+		t.Errorf("CheckWeakKey(nil) error = %v, want %v", err, ErrPrivateKeyNil)
+	}
+	if _, err := CheckWeakKeyHex(secretKey(t, "zz")); err == nil {
+		t.Error("CheckWeakKeyHex() expected error for invalid hex")
+	}
+}
+
+func TestLoadBrainwalletDictionary(t *testing.T) {
+	passphrase := "gowallet custom dictionary entry"
+	sum := sha256.Sum256([]byte(passphrase))
+	key := hex.EncodeToString(sum[:])
+
+	weaknesses, err := CheckWeakKeyHex(secretKey(t, key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hasWeakness(weaknesses, WeakBrainwallet) {
+		t.Fatal("key flagged before loading dictionary")
+	}
+
+	if err := LoadBrainwalletDictionary(strings.NewReader("# comment\n\n" + passphrase + "\r\n")); err != nil {
+		t.Fatalf("LoadBrainwalletDictionary() error: %v", err)
+	}
+	weaknesses, err = CheckWeakKeyHex(secretKey(t, key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasWeakness(weaknesses, WeakBrainwallet) {
+		t.Errorf("CheckWeakKeyHex() = %v, want brainwallet after loading dictionary", weaknesses)
+	}
+}
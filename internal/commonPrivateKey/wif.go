@@ -0,0 +1,153 @@
+package commonPrivateKey
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil/base58"
+	"github.com/spark8899/gowallet/internal/security"
+)
+
+// ErrInvalidWIF indicates a Wallet Import Format key with a bad checksum,
+// length or compression flag
+var ErrInvalidWIF = errors.New("invalid WIF private key")
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// isWIF reports whether text has the length and alphabet of a WIF key:
+// 51 characters uncompressed, 52 compressed. Hex keys are 64 characters, so
+// the two never overlap.
+func isWIF(text []byte) bool {
+	if len(text) != 51 && len(text) != 52 {
+		return false
+	}
+	for _, c := range text {
+		if !bytes.ContainsRune([]byte(base58Alphabet), rune(c)) {
+			return false
+		}
+	}
+	return true
+}
+
+// decodeBase58 decodes text into a new SecretBuffer in place of
+// base58.Decode, which only takes a string
+func decodeBase58(text []byte) (*security.SecretBuffer, error) {
+	zeros := 0
+	for zeros < len(text) && text[zeros] == '1' {
+		zeros++
+	}
+	// log(58)/log(256) < 0.733
+	work, err := security.NewSecretBuffer(len(text)*733/1000 + 1)
+	if err != nil {
+		return nil, err
+	}
+	defer work.Destroy()
+	buf := work.Bytes()
+	for _, c := range text {
+		carry := bytes.IndexByte([]byte(base58Alphabet), c)
+		if carry < 0 {
+			return nil, fmt.Errorf("%w: bad base58 character %q", ErrInvalidWIF, c)
+		}
+		for j := len(buf) - 1; j >= 0; j-- {
+			carry += 58 * int(buf[j])
+			buf[j] = byte(carry)
+			carry >>= 8
+		}
+	}
+	start := 0
+	for start < len(buf) && buf[start] == 0 {
+		start++
+	}
+
+	out, err := security.NewSecretBuffer(zeros + len(buf) - start)
+	if err != nil {
+		return nil, err
+	}
+	copy(out.Bytes()[zeros:], buf[start:])
+	return out, nil
+}
+
+// decodeWIF decodes a WIF key into its 32 key bytes, version byte and
+// compression flag
+func decodeWIF(text []byte) (*security.SecretBuffer, byte, bool, error) {
+	payload, err := decodeBase58(text)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	defer payload.Destroy()
+
+	// version || key || [0x01] || 4-byte checksum
+	p := payload.Bytes()
+	if len(p) != 1+32+4 && len(p) != 1+32+1+4 {
+		return nil, 0, false, fmt.Errorf("%w: %d-byte payload", ErrInvalidWIF, len(p))
+	}
+	body, checksum := p[:len(p)-4], p[len(p)-4:]
+	first := sha256.Sum256(body)
+	second := sha256.Sum256(first[:])
+	security.ZeroBytes(first[:])
+	if !bytes.Equal(second[:4], checksum) {
+		return nil, 0, false, fmt.Errorf("%w: checksum mismatch", ErrInvalidWIF)
+	}
+	compressed := len(body) == 1+32+1
+	if compressed && body[33] != 0x01 {
+		return nil, 0, false, fmt.Errorf("%w: compression flag 0x%02x", ErrInvalidWIF, body[33])
+	}
+
+	raw, err := security.NewSecretBufferFrom(body[1:33])
+	if err != nil {
+		return nil, 0, false, err
+	}
+	return raw, body[0], compressed, nil
+}
+
+// DecodeWIF decodes the WIF key in secret and returns the 32 raw key bytes
+// in a new SecretBuffer, the network version byte (0x80 Bitcoin mainnet,
+// 0xef testnet, 0xb0 Litecoin, ...) and whether the key's addresses use the
+// compressed public key
+func DecodeWIF(secret *security.SecretBuffer) (*security.SecretBuffer, byte, bool, error) {
+	if secret.Destroyed() {
+		return nil, 0, false, security.ErrSecretDestroyed
+	}
+	text := bytes.TrimSpace(secret.Bytes())
+	if !isWIF(text) {
+		return nil, 0, false, fmt.Errorf("%w: expected 51 or 52 base58 characters", ErrInvalidWIF)
+	}
+	return decodeWIF(text)
+}
+
+// IsWIF reports whether secret holds a well-formed WIF key rather than hex
+func IsWIF(secret *security.SecretBuffer) bool {
+	raw, _, _, err := DecodeWIF(secret)
+	if err != nil {
+		return false
+	}
+	raw.Destroy()
+	return true
+}
+
+// EncodeWIF encodes the private key in secret (hex or WIF) in Wallet Import
+// Format with the given version byte. The base58 encoder only returns a
+// string, so the result passes through one before it is copied into the
+// SecretBuffer.
+func EncodeWIF(secret *security.SecretBuffer, version byte, compressed bool) (*security.SecretBuffer, error) {
+	raw, err := PrivateKeyBytes(secret)
+	if err != nil {
+		return nil, err
+	}
+	defer raw.Destroy()
+
+	payload, err := security.NewSecretBuffer(raw.Len() + 1)
+	if err != nil {
+		return nil, err
+	}
+	defer payload.Destroy()
+	copy(payload.Bytes(), raw.Bytes())
+	n := raw.Len()
+	if compressed {
+		payload.Bytes()[n] = 0x01
+		n++
+	}
+	return security.NewSecretBufferFromString(base58.CheckEncode(payload.Bytes()[:n], version))
+}
@@ -0,0 +1,70 @@
+package commonPrivateKey
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWIF(t *testing.T) {
+	// Well-known encodings of private key 1
+	key := "0x0000000000000000000000000000000000000000000000000000000000000001"
+	tests := []struct {
+		wif        string
+		version    byte
+		compressed bool
+	}{
+		{"KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn", 0x80, true},
+		{"5HpHagT65TZzG1PH3CSu63k8DbpvD8s5ip4nEB3kEsreAnchuDf", 0x80, false},
+		{"cMahea7zqjxrtgAbB7LSGbcQUr1uX1ojuat9jZodMN87JcbXMTcA", 0xef, true},
+	}
+	for _, tt := range tests {
+		wif, err := EncodeWIF(secretKey(t, key), tt.version, tt.compressed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(wif.Bytes()) != tt.wif {
+			t.Errorf("EncodeWIF(0x%02x, %v) = %s, want %s", tt.version, tt.compressed, wif.Bytes(), tt.wif)
+		}
+		wif.Destroy()
+
+		raw, version, compressed, err := DecodeWIF(secretKey(t, tt.wif))
+		if err != nil {
+			t.Fatalf("DecodeWIF(%s): %v", tt.wif, err)
+		}
+		if raw.Bytes()[31] != 1 || version != tt.version || compressed != tt.compressed {
+			t.Errorf("DecodeWIF(%s) = %x, 0x%02x, %v", tt.wif, raw.Bytes(), version, compressed)
+		}
+		raw.Destroy()
+
+		// every key operation accepts WIF as well as hex
+		hexKey, err := PrivateKeyHex(secretKey(t, tt.wif+"\n"))
+		if err != nil || string(hexKey.Bytes()) != key {
+			t.Errorf("PrivateKeyHex(%s) = %s, %v", tt.wif, hexKey.Bytes(), err)
+		}
+		hexKey.Destroy()
+	}
+}
+
+func TestWIF_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		wif  string
+	}{
+		{"checksum", "KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWo"},
+		{"base58", "KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoW0"},
+		{"length", "KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHno"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, _, err := DecodeWIF(secretKey(t, tt.wif)); !errors.Is(err, ErrInvalidWIF) {
+				t.Errorf("DecodeWIF() error = %v, want ErrInvalidWIF", err)
+			}
+			if IsWIF(secretKey(t, tt.wif)) {
+				t.Error("IsWIF() = true")
+			}
+		})
+	}
+	if _, err := PrivateKey(secretKey(t, tests[0].wif)); !errors.Is(err, ErrInvalidWIF) {
+		t.Errorf("PrivateKey() error = %v, want ErrInvalidWIF", err)
+	}
+}
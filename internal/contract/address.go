@@ -0,0 +1,105 @@
+package contract
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spark8899/gowallet/internal/vanity"
+)
+
+var (
+	// ErrInvalidAddress indicates the deployer is not a 20-byte hex address
+	ErrInvalidAddress = errors.New("invalid deployer address")
+	// ErrInvalidSalt indicates the salt is not 32 bytes of hex
+	ErrInvalidSalt = errors.New("salt must be 32 bytes of hex")
+	// ErrInvalidInitCodeHash indicates the init code hash is not 32 bytes of hex
+	ErrInvalidInitCodeHash = errors.New("init code hash must be 32 bytes of hex")
+)
+
+// ParseAddress parses a 0x-prefixed or bare hex deployer address
+func ParseAddress(s string) (common.Address, error) {
+	if !common.IsHexAddress(s) {
+		return common.Address{}, fmt.Errorf("%w: %q", ErrInvalidAddress, s)
+	}
+	return common.HexToAddress(s), nil
+}
+
+// ParseSalt parses a 32-byte hex salt. Shorter values are left-padded with zeros.
+func ParseSalt(s string) ([32]byte, error) {
+	var salt [32]byte
+	b, err := decodeHex(s)
+	if err != nil || len(b) > 32 {
+		return salt, ErrInvalidSalt
+	}
+	copy(salt[32-len(b):], b)
+	return salt, nil
+}
+
+// ParseInitCodeHash parses a 32-byte hex keccak256 hash of the init code
+func ParseInitCodeHash(s string) ([]byte, error) {
+	b, err := decodeHex(s)
+	if err != nil || len(b) != 32 {
+		return nil, ErrInvalidInitCodeHash
+	}
+	return b, nil
+}
+
+// InitCodeHash returns keccak256(initCode) from hex encoded init code
+func InitCodeHash(initCodeHex string) ([]byte, error) {
+	b, err := decodeHex(initCodeHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid init code: %w", err)
+	}
+	return crypto.Keccak256(b), nil
+}
+
+// CreateAddress returns the address of a contract deployed with CREATE,
+// keccak256(rlp([deployer, nonce]))[12:]
+func CreateAddress(deployer common.Address, nonce uint64) common.Address {
+	return crypto.CreateAddress(deployer, nonce)
+}
+
+// Create2Address returns the address of a contract deployed with CREATE2,
+// keccak256(0xff ++ deployer ++ salt ++ initCodeHash)[12:]
+func Create2Address(deployer common.Address, salt [32]byte, initCodeHash []byte) common.Address {
+	return crypto.CreateAddress2(deployer, salt, initCodeHash)
+}
+
+// SaltResult is a salt producing a matching CREATE2 address
+type SaltResult struct {
+	Salt    [32]byte
+	Address common.Address
+}
+
+// MineSalt searches random salts in parallel until the resulting CREATE2
+// address is accepted by m
+func MineSalt(ctx context.Context, deployer common.Address, initCodeHash []byte, m *vanity.Matcher, cfg vanity.Config) (*SaltResult, vanity.Stats, error) {
+	if len(initCodeHash) != 32 {
+		return nil, vanity.Stats{}, ErrInvalidInitCodeHash
+	}
+	return vanity.Run(ctx, cfg, func() (*SaltResult, bool, error) {
+		var salt [32]byte
+		if _, err := rand.Read(salt[:]); err != nil {
+			return nil, false, err
+		}
+		address := Create2Address(deployer, salt, initCodeHash)
+		if !m.Match(address) {
+			return nil, false, nil
+		}
+		return &SaltResult{Salt: salt, Address: address}, true, nil
+	}, nil)
+}
+
+func decodeHex(s string) ([]byte, error) {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	if len(s)%2 == 1 {
+		s = "0" + s
+	}
+	return hex.DecodeString(s)
+}
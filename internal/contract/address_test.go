@@ -0,0 +1,105 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spark8899/gowallet/internal/vanity"
+)
+
+func TestCreateAddress(t *testing.T) {
+	tests := []struct {
+		deployer string
+		nonce    uint64
+		want     string
+	}{
+		{"0x6ac7ea33f8831ea9dcc53393aaa88b25a785dbf0", 0, "0xcd234a471b72ba2f1ccf0a70fcaba648a5eecd8d"},
+		{"0x6ac7ea33f8831ea9dcc53393aaa88b25a785dbf0", 1, "0x343c43a37d37dff08ae8c4a11544c718abb4fcf8"},
+	}
+
+	for _, tt := range tests {
+		deployer, err := ParseAddress(tt.deployer)
+		if err != nil {
+			t.Fatalf("ParseAddress() error: %v", err)
+		}
+		got := CreateAddress(deployer, tt.nonce)
+		if got != common.HexToAddress(tt.want) {
+			t.Errorf("CreateAddress(%s, %d) = %s, want %s", tt.deployer, tt.nonce, got.Hex(), tt.want)
+		}
+	}
+}
+
+func TestCreate2Address(t *testing.T) {
+	// Examples from EIP-1014
+	tests := []struct {
+		deployer string
+		salt     string
+		initCode string
+		want     string
+	}{
+		{"0x0000000000000000000000000000000000000000", "0x00", "0x00", "0x4D1A2e2bB4F88F0250f26Ffff098B0b30B26BF38"},
+		{"0xdeadbeef00000000000000000000000000000000", "0x00", "0x00", "0xB928f69Bb1D91Cd65274e3c79d8986362984fDA3"},
+		{"0xdeadbeef00000000000000000000000000000000", "0x000000000000000000000000feed000000000000000000000000000000000000", "0x00", "0xD04116cDd17beBE565EB2422F2497E06cC1C9833"},
+		{"0x0000000000000000000000000000000000000000", "0x00", "0xdeadbeef", "0x70f2b2914A2a4b783FaEFb75f459A580616Fcb5e"},
+	}
+
+	for _, tt := range tests {
+		deployer, err := ParseAddress(tt.deployer)
+		if err != nil {
+			t.Fatalf("ParseAddress() error: %v", err)
+		}
+		salt, err := ParseSalt(tt.salt)
+		if err != nil {
+			t.Fatalf("ParseSalt() error: %v", err)
+		}
+		hash, err := InitCodeHash(tt.initCode)
+		if err != nil {
+			t.Fatalf("InitCodeHash() error: %v", err)
+		}
+		got := Create2Address(deployer, salt, hash)
+		if got.Hex() != tt.want {
+			t.Errorf("Create2Address() = %s, want %s", got.Hex(), tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	if _, err := ParseAddress("0x1234"); !errors.Is(err, ErrInvalidAddress) {
+		t.Errorf("ParseAddress() error = %v, want %v", err, ErrInvalidAddress)
+	}
+	if _, err := ParseSalt("0xzz"); !errors.Is(err, ErrInvalidSalt) {
+		t.Errorf("ParseSalt() error = %v, want %v", err, ErrInvalidSalt)
+	}
+	if _, err := ParseSalt("0x" + "00112233445566778899aabbccddeeff00112233445566778899aabbccddeeff00"); !errors.Is(err, ErrInvalidSalt) {
+		t.Errorf("ParseSalt() with 33 bytes error = %v, want %v", err, ErrInvalidSalt)
+	}
+	if _, err := ParseInitCodeHash("0x1234"); !errors.Is(err, ErrInvalidInitCodeHash) {
+		t.Errorf("ParseInitCodeHash() error = %v, want %v", err, ErrInvalidInitCodeHash)
+	}
+}
+
+func TestMineSalt(t *testing.T) {
+	deployer := common.HexToAddress("0x4e59b44847b379578588920ca78fbf26c0b4956c")
+	hash, _ := InitCodeHash("0x6080604052")
+	m, err := vanity.NewMatcher("ab", "", "", false)
+	if err != nil {
+		t.Fatalf("NewMatcher() error: %v", err)
+	}
+
+	result, _, err := MineSalt(context.Background(), deployer, hash, m, vanity.Config{Workers: 2})
+	if err != nil {
+		t.Fatalf("MineSalt() error: %v", err)
+	}
+	if !m.Match(result.Address) {
+		t.Errorf("MineSalt() address %s does not match", result.Address.Hex())
+	}
+	if Create2Address(deployer, result.Salt, hash) != result.Address {
+		t.Error("MineSalt() salt does not reproduce the address")
+	}
+
+	if _, _, err := MineSalt(context.Background(), deployer, []byte{1}, m, vanity.Config{}); !errors.Is(err, ErrInvalidInitCodeHash) {
+		t.Errorf("MineSalt() error = %v, want %v", err, ErrInvalidInitCodeHash)
+	}
+}
@@ -0,0 +1,98 @@
+package cosmos
+
+import (
+	"crypto/sha256"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spark8899/gowallet/internal/commonPrivateKey"
+	"github.com/spark8899/gowallet/internal/hdwallet"
+	"github.com/spark8899/gowallet/internal/security"
+)
+
+// AminoPubKey is a public key in amino JSON
+type AminoPubKey struct {
+	Type  string `json:"type"`
+	Value []byte `json:"value"`
+}
+
+// StdSignature is the amino JSON signature added to a legacy StdTx
+type StdSignature struct {
+	PubKey    AminoPubKey `json:"pub_key"`
+	Signature []byte      `json:"signature"`
+}
+
+// AnyPubKey is a public key packed in a protobuf Any, in proto JSON
+type AnyPubKey struct {
+	TypeURL string `json:"@type"`
+	Key     []byte `json:"key"`
+}
+
+// DirectSignature is a direct mode signature with the signer's public key
+// for the transaction's SignerInfo
+type DirectSignature struct {
+	PubKey    AnyPubKey `json:"pub_key"`
+	Signature []byte    `json:"signature"`
+}
+
+// SignAmino signs a legacy amino JSON sign doc with the private key in secret
+func SignAmino(secret *security.SecretBuffer, params *hdwallet.CosmosParams, doc []byte) (*StdSignature, error) {
+	signBytes, err := AminoSignBytes(doc)
+	if err != nil {
+		return nil, err
+	}
+	pub, sig, err := Sign(secret, params, signBytes)
+	if err != nil {
+		return nil, err
+	}
+	return &StdSignature{
+		PubKey:    AminoPubKey{Type: params.AminoPubKeyType, Value: pub},
+		Signature: sig,
+	}, nil
+}
+
+// SignDirect signs a protobuf sign doc with the private key in secret
+func SignDirect(secret *security.SecretBuffer, params *hdwallet.CosmosParams, doc *SignDoc) (*DirectSignature, error) {
+	pub, sig, err := Sign(secret, params, doc.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return &DirectSignature{
+		PubKey:    AnyPubKey{TypeURL: params.PubKeyTypeURL, Key: pub},
+		Signature: sig,
+	}, nil
+}
+
+// Sign signs signBytes the way the chain's key type does and returns the
+// compressed public key and the signature. secp256k1 keys sign SHA-256 and
+// return 64-byte low-S r||s; eth_secp256k1 keys sign Keccak-256 and return
+// 65-byte r||s||v.
+func Sign(secret *security.SecretBuffer, params *hdwallet.CosmosParams, signBytes []byte) (pub, sig []byte, err error) {
+	publicKey, err := commonPrivateKey.PublicKey(secret)
+	if err != nil {
+		return nil, nil, err
+	}
+	pub = crypto.CompressPubkey(publicKey)
+
+	if params.EthSecp256k1 {
+		sig, err = commonPrivateKey.SignHash(secret, crypto.Keccak256(signBytes))
+		if err != nil {
+			return nil, nil, err
+		}
+		return pub, sig, nil
+	}
+
+	raw, err := commonPrivateKey.PrivateKeyBytes(secret)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer raw.Destroy()
+	privateKey, _ := btcec.PrivKeyFromBytes(raw.Bytes())
+	defer privateKey.Zero()
+
+	hash := sha256.Sum256(signBytes)
+	// RFC 6979 and low-S like the SDK; drop the recovery byte
+	compact := ecdsa.SignCompact(privateKey, hash[:], true)
+	return pub, compact[1:], nil
+}
@@ -0,0 +1,89 @@
+package cosmos
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spark8899/gowallet/internal/hdwallet"
+	"github.com/spark8899/gowallet/internal/security"
+)
+
+// Hardhat/Anvil default account #0
+const testKey = "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+
+const testPubKey = "038318535b54105d4a7aae60c08fc45f9687181b4fdfc625bd1a753fa7397fed75"
+
+const testDoc = `{"account_number":"0","chain_id":"test","fee":{"amount":[],"gas":"0"},"memo":"","msgs":[],"sequence":"0"}`
+
+func testSecret(t *testing.T) *security.SecretBuffer {
+	t.Helper()
+	s, err := security.NewSecretBufferFromString(testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(s.Destroy)
+	return s
+}
+
+func TestSignAmino(t *testing.T) {
+	sig, err := SignAmino(testSecret(t), hdwallet.CosmosHub.Cosmos, []byte(testDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sig.PubKey.Type != "tendermint/PubKeySecp256k1" || hex.EncodeToString(sig.PubKey.Value) != testPubKey {
+		t.Errorf("pub_key = %s %x", sig.PubKey.Type, sig.PubKey.Value)
+	}
+	if len(sig.Signature) != 64 {
+		t.Fatalf("signature is %d bytes, want 64", len(sig.Signature))
+	}
+
+	var r, s btcec.ModNScalar
+	r.SetByteSlice(sig.Signature[:32])
+	s.SetByteSlice(sig.Signature[32:])
+	if s.IsOverHalfOrder() {
+		t.Error("signature is not low-S")
+	}
+	pub, err := btcec.ParsePubKey(sig.PubKey.Value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := sha256.Sum256([]byte(testDoc))
+	if !ecdsa.NewSignature(&r, &s).Verify(hash[:], pub) {
+		t.Error("signature does not verify over SHA-256 of the sign bytes")
+	}
+
+	// RFC 6979: the same doc always gives the same signature
+	again, err := SignAmino(testSecret(t), hdwallet.CosmosHub.Cosmos, []byte(testDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(again.Signature, sig.Signature) {
+		t.Error("signature is not deterministic")
+	}
+}
+
+func TestSignDirectEthSecp256k1(t *testing.T) {
+	doc := &SignDoc{BodyBytes: []byte{0x0a, 0x00}, AuthInfoBytes: []byte{0x12, 0x00}, ChainID: "evmos_9001-2", AccountNumber: 7}
+	sig, err := SignDirect(testSecret(t), hdwallet.Evmos.Cosmos, doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sig.PubKey.TypeURL != "/ethermint.crypto.v1.ethsecp256k1.PubKey" {
+		t.Errorf("@type = %s", sig.PubKey.TypeURL)
+	}
+	if len(sig.Signature) != 65 {
+		t.Fatalf("signature is %d bytes, want 65", len(sig.Signature))
+	}
+	recovered, err := crypto.SigToPub(crypto.Keccak256(doc.Bytes()), sig.Signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hex.EncodeToString(crypto.CompressPubkey(recovered)); got != testPubKey {
+		t.Errorf("recovered public key = %s, want %s", got, testPubKey)
+	}
+}
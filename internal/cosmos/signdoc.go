@@ -0,0 +1,80 @@
+// Package cosmos builds and signs Cosmos SDK sign docs for offline
+// transactions, in both the legacy amino JSON and the protobuf direct modes.
+package cosmos
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrInvalidSignDoc indicates an amino sign doc that is not a JSON object
+// with the StdSignDoc fields
+var ErrInvalidSignDoc = errors.New("invalid amino sign doc")
+
+// aminoFields are the StdSignDoc fields every amino sign doc must carry
+var aminoFields = []string{"account_number", "chain_id", "fee", "msgs", "sequence"}
+
+// AminoSignBytes returns the canonical bytes of a legacy amino JSON sign doc:
+// keys sorted at every level, no insignificant whitespace and HTML characters
+// escaped, as the SDK's MustSortJSON produces. Numbers keep their exact text.
+func AminoSignBytes(doc []byte) ([]byte, error) {
+	d := json.NewDecoder(bytes.NewReader(doc))
+	d.UseNumber()
+	var v any
+	if err := d.Decode(&v); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignDoc, err)
+	}
+	if _, err := d.Token(); err != io.EOF {
+		return nil, fmt.Errorf("%w: trailing data", ErrInvalidSignDoc)
+	}
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: not a JSON object", ErrInvalidSignDoc)
+	}
+	for _, f := range aminoFields {
+		if _, ok := obj[f]; !ok {
+			return nil, fmt.Errorf("%w: missing %q", ErrInvalidSignDoc, f)
+		}
+	}
+	// encoding/json sorts map keys and escapes <, > and &
+	return json.Marshal(obj)
+}
+
+// SignDoc is the protobuf cosmos.tx.v1beta1.SignDoc signed in direct mode
+type SignDoc struct {
+	BodyBytes     []byte // serialized TxBody
+	AuthInfoBytes []byte // serialized AuthInfo
+	ChainID       string
+	AccountNumber uint64
+}
+
+// Bytes returns the protobuf encoding of the sign doc. Like the SDK, fields
+// holding their zero value are omitted.
+func (d *SignDoc) Bytes() []byte {
+	var b []byte
+	if len(d.BodyBytes) > 0 {
+		b = appendBytesField(b, 1, d.BodyBytes)
+	}
+	if len(d.AuthInfoBytes) > 0 {
+		b = appendBytesField(b, 2, d.AuthInfoBytes)
+	}
+	if d.ChainID != "" {
+		b = appendBytesField(b, 3, []byte(d.ChainID))
+	}
+	if d.AccountNumber != 0 {
+		b = binary.AppendUvarint(b, 4<<3) // varint wire type
+		b = binary.AppendUvarint(b, d.AccountNumber)
+	}
+	return b
+}
+
+// appendBytesField appends a length-delimited protobuf field
+func appendBytesField(b []byte, field uint64, data []byte) []byte {
+	b = binary.AppendUvarint(b, field<<3|2)
+	b = binary.AppendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
@@ -0,0 +1,59 @@
+package cosmos
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestAminoSignBytes(t *testing.T) {
+	doc := `{
+  "chain_id": "cosmoshub-4",
+  "account_number": "12",
+  "sequence": "3",
+  "fee": {"gas": "200000", "amount": [{"denom": "uatom", "amount": "5000"}]},
+  "msgs": [{"type": "cosmos-sdk/MsgSend", "value": {"to_address": "cosmos1b", "from_address": "cosmos1a", "amount": [{"denom": "uatom", "amount": "1"}]}}],
+  "memo": "<a&b>",
+  "timeout_height": 0
+}`
+	want := `{"account_number":"12","chain_id":"cosmoshub-4","fee":{"amount":[{"amount":"5000","denom":"uatom"}],"gas":"200000"},` +
+		`"memo":"\u003ca\u0026b\u003e","msgs":[{"type":"cosmos-sdk/MsgSend","value":{"amount":[{"amount":"1","denom":"uatom"}],"from_address":"cosmos1a","to_address":"cosmos1b"}}],` +
+		`"sequence":"3","timeout_height":0}`
+	got, err := AminoSignBytes([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("AminoSignBytes =\n%s\nwant\n%s", got, want)
+	}
+
+	for _, bad := range []string{
+		`[1, 2]`,
+		`{"chain_id": "x"}`,
+		`{"account_number":"1","chain_id":"x","fee":{},"msgs":[],"sequence":"0"} {}`,
+		`{"account_number":`,
+	} {
+		if _, err := AminoSignBytes([]byte(bad)); !errors.Is(err, ErrInvalidSignDoc) {
+			t.Errorf("AminoSignBytes(%s) error = %v, want ErrInvalidSignDoc", bad, err)
+		}
+	}
+}
+
+func TestSignDocBytes(t *testing.T) {
+	doc := &SignDoc{
+		BodyBytes:     []byte{0x0a, 0x01},
+		AuthInfoBytes: []byte{0x12},
+		ChainID:       "test-1",
+		AccountNumber: 300,
+	}
+	want, _ := hex.DecodeString("0a020a01" + "120112" + "1a06746573742d31" + "20ac02")
+	if got := doc.Bytes(); !bytes.Equal(got, want) {
+		t.Errorf("SignDoc.Bytes = %x, want %x", got, want)
+	}
+
+	// zero values are omitted
+	if got := (&SignDoc{ChainID: "c"}).Bytes(); !bytes.Equal(got, []byte{0x1a, 0x01, 'c'}) {
+		t.Errorf("SignDoc.Bytes = %x", got)
+	}
+}
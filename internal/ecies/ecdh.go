@@ -0,0 +1,81 @@
+package ecies
+
+import (
+	"crypto/hkdf"
+	"crypto/sha256"
+	"errors"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/spark8899/gowallet/internal/commonPrivateKey"
+	"github.com/spark8899/gowallet/internal/security"
+)
+
+// ErrKeyLength indicates an HKDF output length outside 1-8160 bytes
+// (255 SHA-256 blocks)
+var ErrKeyLength = errors.New("HKDF key length must be between 1 and 8160 bytes")
+
+// sharedPoint multiplies peer by the private key in secret and returns the
+// 33-byte compressed shared point in a new SecretBuffer
+func sharedPoint(secret *security.SecretBuffer, peer *btcec.PublicKey) (*security.SecretBuffer, error) {
+	raw, err := commonPrivateKey.PrivateKeyBytes(secret)
+	if err != nil {
+		return nil, err
+	}
+	defer raw.Destroy()
+	priv, _ := btcec.PrivKeyFromBytes(raw.Bytes())
+	defer priv.Zero()
+
+	var point, result btcec.JacobianPoint
+	peer.AsJacobian(&point)
+	btcec.ScalarMultNonConst(&priv.Key, &point, &result)
+	result.ToAffine()
+	defer func() {
+		result.X.Zero()
+		result.Y.Zero()
+	}()
+
+	out, err := security.NewSecretBuffer(33)
+	if err != nil {
+		return nil, err
+	}
+	p := out.Bytes()
+	p[0] = 0x02
+	if result.Y.IsOdd() {
+		p[0] = 0x03
+	}
+	result.X.PutBytesUnchecked(p[1:])
+	return out, nil
+}
+
+// SharedSecret derives the ECDH secret of the private key in secret (hex
+// or WIF) and a peer public key as SHA-256 of the compressed shared point,
+// the default hash of libsecp256k1's secp256k1_ecdh. Both sides get the
+// same 32 bytes.
+func SharedSecret(secret *security.SecretBuffer, peer *btcec.PublicKey) (*security.SecretBuffer, error) {
+	point, err := sharedPoint(secret, peer)
+	if err != nil {
+		return nil, err
+	}
+	defer point.Destroy()
+	sum := sha256.Sum256(point.Bytes())
+	return security.NewSecretBufferFrom(sum[:])
+}
+
+// SharedKeyHKDF derives length bytes from the ECDH x coordinate with
+// HKDF-SHA256 (RFC 5869). salt and info may be nil; giving each channel its
+// own info keeps their keys independent.
+func SharedKeyHKDF(secret *security.SecretBuffer, peer *btcec.PublicKey, salt []byte, info string, length int) (*security.SecretBuffer, error) {
+	if length < 1 || length > 255*sha256.Size {
+		return nil, ErrKeyLength
+	}
+	point, err := sharedPoint(secret, peer)
+	if err != nil {
+		return nil, err
+	}
+	defer point.Destroy()
+	key, err := hkdf.Key(sha256.New, point.Bytes()[1:], salt, info, length)
+	if err != nil {
+		return nil, err
+	}
+	return security.NewSecretBufferFrom(key)
+}
@@ -0,0 +1,70 @@
+package ecies
+
+import (
+	"bytes"
+	"crypto/hkdf"
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestSharedSecret(t *testing.T) {
+	alice := "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+	bob := "0x59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d"
+	ab, err := SharedSecret(secretString(t, alice), testPublicKey(t, bob))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ab.Destroy()
+	ba, err := SharedSecret(secretString(t, bob), testPublicKey(t, alice))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ba.Destroy()
+	if !bytes.Equal(ab.Bytes(), ba.Bytes()) || ab.Len() != 32 {
+		t.Fatalf("SharedSecret differs between peers: %x, %x", ab.Bytes(), ba.Bytes())
+	}
+
+	// libsecp256k1's hash: SHA-256 of the compressed point, whose x is the
+	// plain ECDH secret
+	aliceKey, err := crypto.HexToECDSA(alice[2:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	bobKey, err := crypto.HexToECDSA(bob[2:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, y := crypto.S256().ScalarMult(bobKey.PublicKey.X, bobKey.PublicKey.Y, aliceKey.D.Bytes())
+	point := append([]byte{0x02 + byte(y.Bit(0))}, x.FillBytes(make([]byte, 32))...)
+	if want := sha256.Sum256(point); !bytes.Equal(ab.Bytes(), want[:]) {
+		t.Errorf("SharedSecret = %x, want %x", ab.Bytes(), want)
+	}
+
+	// HKDF-SHA256 of x, independent per info
+	k1, err := SharedKeyHKDF(secretString(t, alice), testPublicKey(t, bob), []byte("salt"), "channel 1", 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer k1.Destroy()
+	want, err := hkdf.Key(sha256.New, x.FillBytes(make([]byte, 32)), []byte("salt"), "channel 1", 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(k1.Bytes(), want) {
+		t.Errorf("SharedKeyHKDF = %x, want %x", k1.Bytes(), want)
+	}
+	k2, err := SharedKeyHKDF(secretString(t, bob), testPublicKey(t, alice), []byte("salt"), "channel 2", 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer k2.Destroy()
+	if bytes.Equal(k1.Bytes(), k2.Bytes()) {
+		t.Error("SharedKeyHKDF ignores info")
+	}
+	if _, err := SharedKeyHKDF(secretString(t, alice), testPublicKey(t, bob), nil, "", 0); !errors.Is(err, ErrKeyLength) {
+		t.Errorf("SharedKeyHKDF(length 0) error = %v, want ErrKeyLength", err)
+	}
+}
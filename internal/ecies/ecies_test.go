@@ -0,0 +1,211 @@
+package ecies
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/ethereum/go-ethereum/crypto"
+	gethecies "github.com/ethereum/go-ethereum/crypto/ecies"
+	"github.com/spark8899/gowallet/internal/commonPrivateKey"
+	"github.com/spark8899/gowallet/internal/security"
+)
+
+const testKey = "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+
+func secretString(t *testing.T, s string) *security.SecretBuffer {
+	t.Helper()
+	secret, err := security.NewSecretBufferFromString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(secret.Destroy)
+	return secret
+}
+
+func testPublicKey(t *testing.T, key string) *btcec.PublicKey {
+	t.Helper()
+	pub, err := commonPrivateKey.CompressedPublicKeyBytes(secretString(t, key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := btcec.ParsePubKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func TestRoundTrip(t *testing.T) {
+	pub := testPublicKey(t, testKey)
+	for _, format := range Formats {
+		for _, msg := range []string{"foobar", "exactly sixteen!", "a longer secret spanning several AES blocks"} {
+			ct, err := Encrypt(nil, pub, []byte(msg), format)
+			if err != nil {
+				t.Fatalf("%s: Encrypt(%q): %v", format, msg, err)
+			}
+			pt, err := Decrypt(secretString(t, testKey), ct, format)
+			if err != nil {
+				t.Fatalf("%s: Decrypt(%q): %v", format, msg, err)
+			}
+			if string(pt.Bytes()) != msg {
+				t.Errorf("%s: Decrypt = %q, want %q", format, pt.Bytes(), msg)
+			}
+			pt.Destroy()
+		}
+	}
+}
+
+func TestEncrypt_Empty(t *testing.T) {
+	pub := testPublicKey(t, testKey)
+	if _, err := Encrypt(nil, pub, nil, Geth); !errors.Is(err, ErrEmptyMessage) {
+		t.Errorf("Encrypt(geth, empty) error = %v, want ErrEmptyMessage", err)
+	}
+	ct, err := Encrypt(nil, pub, nil, EthCrypto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pt, err := Decrypt(secretString(t, testKey), ct, EthCrypto)
+	if err != nil || pt.Len() != 0 {
+		t.Errorf("Decrypt(eth-crypto, empty) = %q, %v", pt.Bytes(), err)
+	}
+	pt.Destroy()
+}
+
+func TestGethCompatibility(t *testing.T) {
+	privateKey, err := crypto.HexToECDSA(testKey[2:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("hello from go-ethereum")
+
+	// go-ethereum decrypts ours
+	ct, err := Encrypt(nil, testPublicKey(t, testKey), msg, Geth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := gethecies.ImportECDSA(privateKey).Decrypt(ct, nil, nil); err != nil || !bytes.Equal(got, msg) {
+		t.Errorf("ecies.Decrypt = %q, %v", got, err)
+	}
+
+	// and we decrypt go-ethereum's, also as hex text
+	ct, err = gethecies.Encrypt(rand.Reader, gethecies.ImportECDSAPublic(&privateKey.PublicKey), msg, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, input := range [][]byte{ct, []byte("0x" + hex.EncodeToString(ct) + "\n")} {
+		pt, err := Decrypt(secretString(t, testKey), input, Geth)
+		if err != nil || !bytes.Equal(pt.Bytes(), msg) {
+			t.Errorf("Decrypt = %q, %v", pt.Bytes(), err)
+		}
+		pt.Destroy()
+	}
+}
+
+func TestEthCryptoSerialization(t *testing.T) {
+	ct, err := Encrypt(nil, testPublicKey(t, testKey), []byte("foobar"), EthCrypto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// iv (16) || compressed ephemeral key (33) || mac (32) || one block
+	if len(ct) != 2*(16+33+32+16) || (ct[32] != '0' || (ct[33] != '2' && ct[33] != '3')) {
+		t.Fatalf("stringified ciphertext %s has the wrong layout", ct)
+	}
+
+	// The unstringified object carries the uncompressed ephemeral key
+	s := string(ct)
+	pub, err := hex.DecodeString(s[32:98])
+	if err != nil {
+		t.Fatal(err)
+	}
+	ephem, err := btcec.ParsePubKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj, err := json.Marshal(ethCryptoObject{
+		IV:             s[:32],
+		EphemPublicKey: hex.EncodeToString(ephem.SerializeUncompressed()),
+		Ciphertext:     s[162:],
+		MAC:            s[98:162],
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pt, err := Decrypt(secretString(t, testKey), obj, EthCrypto)
+	if err != nil || string(pt.Bytes()) != "foobar" {
+		t.Errorf("Decrypt(object) = %q, %v", pt.Bytes(), err)
+	}
+	pt.Destroy()
+}
+
+// TestEthCryptoKnownAnswer checks a ciphertext made outside this package:
+// eccrypto's encrypt and eth-crypto's cipher.stringify, reimplemented on
+// Node's OpenSSL bindings (crypto.createECDH, createCipheriv, createHmac),
+// to testKey with the ephemeral key SHA-256("gowallet eth-crypto vector
+// ephemeral key") and the iv SHA-256("gowallet eth-crypto vector iv")[:16]
+func TestEthCryptoKnownAnswer(t *testing.T) {
+	const (
+		plaintext = "Hello from eth-crypto! Ünïcödé ✓"
+		vector    = "1590b3bed610b5c6bc3c2d6ce5d49a1f" + // iv
+			"0226c893f42c0a6d5391911483af2b8b79c8169b6400c36719e3fd9367724e21d5" + // compressed ephemeral key
+			"3dabf86f1f4f3cc79ab84e4f215eb9b1a30d2c5201034da5c7b3d0257fc98b12" + // mac
+			"03b05e954e6f5462463a5872be75cdc90b73d72989fa2c46e438cfdbe3cf2f7d7551c1d029e1c40b1edb936101c839b4"
+		ephemPublicKey = "0426c893f42c0a6d5391911483af2b8b79c8169b6400c36719e3fd9367724e21d5" +
+			"c33a101a94dd167afe26d01ddbb03cb36bfc41ecf564945d2080d46fe16c6994"
+	)
+
+	obj, err := json.Marshal(ethCryptoObject{IV: vector[:32], EphemPublicKey: ephemPublicKey, Ciphertext: vector[162:], MAC: vector[98:162]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, input := range [][]byte{[]byte(vector), obj} {
+		pt, err := Decrypt(secretString(t, testKey), input, EthCrypto)
+		if err != nil || string(pt.Bytes()) != plaintext {
+			t.Errorf("Decrypt(%.20s...) = %q, %v, want %q", input, pt.Bytes(), err, plaintext)
+		}
+		pt.Destroy()
+	}
+
+	// With the same ephemeral key and iv, Encrypt gives the same bytes
+	ephem := sha256.Sum256([]byte("gowallet eth-crypto vector ephemeral key"))
+	iv := sha256.Sum256([]byte("gowallet eth-crypto vector iv"))
+	random := bytes.NewReader(append(ephem[:], iv[:16]...))
+	ct, err := Encrypt(random, testPublicKey(t, testKey), []byte(plaintext), EthCrypto)
+	if err != nil || string(ct) != vector {
+		t.Errorf("Encrypt() = %s, %v, want %s", ct, err, vector)
+	}
+}
+
+func TestDecrypt_Invalid(t *testing.T) {
+	pub := testPublicKey(t, testKey)
+	otherKey := "0x59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d"
+	for _, format := range Formats {
+		ct, err := Encrypt(nil, pub, []byte("secret"), format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Decrypt(secretString(t, otherKey), ct, format); !errors.Is(err, ErrDecrypt) {
+			t.Errorf("%s: Decrypt with another key error = %v, want ErrDecrypt", format, err)
+		}
+		tampered := bytes.Clone(ct)
+		tampered[len(tampered)-1] ^= 1
+		if format == EthCrypto {
+			// keep it hex
+			tampered[len(tampered)-1] = "01"[tampered[len(tampered)-1]&1]
+			if bytes.Equal(tampered, ct) {
+				tampered[len(tampered)-1] = '2'
+			}
+		}
+		if _, err := Decrypt(secretString(t, testKey), tampered, format); !errors.Is(err, ErrDecrypt) {
+			t.Errorf("%s: Decrypt of a modified message error = %v, want ErrDecrypt", format, err)
+		}
+	}
+	if _, err := FormatByName("pgp"); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("FormatByName(pgp) error = %v, want ErrUnknownFormat", err)
+	}
+}
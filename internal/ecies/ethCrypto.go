@@ -0,0 +1,174 @@
+package ecies
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/spark8899/gowallet/internal/commonPrivateKey"
+	"github.com/spark8899/gowallet/internal/security"
+)
+
+// ethCryptoObject is the object eth-crypto's encryptWithPublicKey returns
+// before cipher.stringify, all fields hex
+type ethCryptoObject struct {
+	IV             string `json:"iv"`
+	EphemPublicKey string `json:"ephemPublicKey"`
+	Ciphertext     string `json:"ciphertext"`
+	MAC            string `json:"mac"`
+}
+
+// ethCryptoKeys derives the AES-256 and HMAC keys from the ECDH x
+// coordinate, padded to 32 bytes. Old eccrypto releases dropped a leading
+// zero byte of x, so about one in 256 of their messages will not decrypt.
+func ethCryptoKeys(priv *btcec.PrivateKey, pub *btcec.PublicKey) (encKey, macKey []byte) {
+	shared := btcec.GenerateSharedSecret(priv, pub)
+	defer security.ZeroBytes(shared)
+	hash := sha512.Sum512(shared)
+	return hash[:32], hash[32:]
+}
+
+func ethCryptoMAC(macKey, iv []byte, ephemPub *btcec.PublicKey, ciphertext []byte) []byte {
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write(iv)
+	mac.Write(ephemPub.SerializeUncompressed())
+	mac.Write(ciphertext)
+	return mac.Sum(nil)
+}
+
+// newEphemeralKey draws a private key from random, retrying the
+// negligible fraction of values outside [1, n-1]
+func newEphemeralKey(random io.Reader) (*btcec.PrivateKey, error) {
+	b := make([]byte, 32)
+	defer security.ZeroBytes(b)
+	for {
+		if _, err := io.ReadFull(random, b); err != nil {
+			return nil, err
+		}
+		var s btcec.ModNScalar
+		if overflow := s.SetByteSlice(b); !overflow && !s.IsZero() {
+			s.Zero()
+			priv, _ := btcec.PrivKeyFromBytes(b)
+			return priv, nil
+		}
+	}
+}
+
+func encryptEthCrypto(random io.Reader, pub *btcec.PublicKey, plaintext []byte) ([]byte, error) {
+	ephem, err := newEphemeralKey(random)
+	if err != nil {
+		return nil, err
+	}
+	defer ephem.Zero()
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(random, iv); err != nil {
+		return nil, err
+	}
+
+	encKey, macKey := ethCryptoKeys(ephem, pub)
+	defer security.ZeroBytes(encKey)
+	defer security.ZeroBytes(macKey)
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, err
+	}
+	// PKCS#7 padding, always at least one byte
+	pad := aes.BlockSize - len(plaintext)%aes.BlockSize
+	ciphertext := make([]byte, len(plaintext)+pad)
+	copy(ciphertext, plaintext)
+	for i := len(plaintext); i < len(ciphertext); i++ {
+		ciphertext[i] = byte(pad)
+	}
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, ciphertext)
+
+	ephemPub := ephem.PubKey()
+	mac := ethCryptoMAC(macKey, iv, ephemPub, ciphertext)
+	out := hex.EncodeToString(iv) + hex.EncodeToString(ephemPub.SerializeCompressed()) +
+		hex.EncodeToString(mac) + hex.EncodeToString(ciphertext)
+	return []byte(out), nil
+}
+
+// parseEthCrypto reads the cipher.stringify hex or the object's JSON
+func parseEthCrypto(data []byte) (iv []byte, ephemPub *btcec.PublicKey, mac, ciphertext []byte, err error) {
+	text := bytes.TrimSpace(data)
+	var obj ethCryptoObject
+	if len(text) > 0 && text[0] == '{' {
+		if err := json.Unmarshal(text, &obj); err != nil {
+			return nil, nil, nil, nil, fmt.Errorf("%w: %v", ErrDecrypt, err)
+		}
+	} else {
+		s := strings.TrimPrefix(string(text), "0x")
+		// 16-byte iv, 33-byte key, 32-byte mac and at least one block
+		if len(s) < 2*(16+33+32+aes.BlockSize) {
+			return nil, nil, nil, nil, fmt.Errorf("%w: message too short", ErrDecrypt)
+		}
+		obj = ethCryptoObject{IV: s[:32], EphemPublicKey: s[32:98], MAC: s[98:162], Ciphertext: s[162:]}
+	}
+
+	fields := make([][]byte, 4)
+	for i, f := range []string{obj.IV, obj.EphemPublicKey, obj.MAC, obj.Ciphertext} {
+		if fields[i], err = hex.DecodeString(strings.TrimPrefix(f, "0x")); err != nil {
+			return nil, nil, nil, nil, fmt.Errorf("%w: %v", ErrDecrypt, err)
+		}
+	}
+	iv, mac, ciphertext = fields[0], fields[2], fields[3]
+	if len(iv) != aes.BlockSize || len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, nil, nil, nil, fmt.Errorf("%w: bad iv or ciphertext length", ErrDecrypt)
+	}
+	if ephemPub, err = btcec.ParsePubKey(fields[1]); err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("%w: ephemeral key: %v", ErrDecrypt, err)
+	}
+	return iv, ephemPub, mac, ciphertext, nil
+}
+
+func decryptEthCrypto(secret *security.SecretBuffer, data []byte) (*security.SecretBuffer, error) {
+	iv, ephemPub, mac, ciphertext, err := parseEthCrypto(data)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := commonPrivateKey.PrivateKeyBytes(secret)
+	if err != nil {
+		return nil, err
+	}
+	defer raw.Destroy()
+	priv, _ := btcec.PrivKeyFromBytes(raw.Bytes())
+	defer priv.Zero()
+
+	encKey, macKey := ethCryptoKeys(priv, ephemPub)
+	defer security.ZeroBytes(encKey)
+	defer security.ZeroBytes(macKey)
+	if !hmac.Equal(mac, ethCryptoMAC(macKey, iv, ephemPub, ciphertext)) {
+		return nil, fmt.Errorf("%w: MAC mismatch", ErrDecrypt)
+	}
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := security.NewSecretBuffer(len(ciphertext))
+	if err != nil {
+		return nil, err
+	}
+	defer plaintext.Destroy()
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext.Bytes(), ciphertext)
+
+	p := plaintext.Bytes()
+	pad := int(p[len(p)-1])
+	if pad == 0 || pad > aes.BlockSize {
+		return nil, fmt.Errorf("%w: bad padding", ErrDecrypt)
+	}
+	for _, b := range p[len(p)-pad:] {
+		if int(b) != pad {
+			return nil, fmt.Errorf("%w: bad padding", ErrDecrypt)
+		}
+	}
+	return security.NewSecretBufferFrom(p[:len(p)-pad])
+}
@@ -0,0 +1,60 @@
+package ecies
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/ethereum/go-ethereum/crypto"
+	gethecies "github.com/ethereum/go-ethereum/crypto/ecies"
+	"github.com/spark8899/gowallet/internal/commonPrivateKey"
+	"github.com/spark8899/gowallet/internal/security"
+)
+
+func encryptGeth(random io.Reader, pub *btcec.PublicKey, plaintext []byte) ([]byte, error) {
+	if len(plaintext) == 0 {
+		// go-ethereum encrypts it but rejects the result as too short
+		return nil, ErrEmptyMessage
+	}
+	// go-ethereum looks the ECIES parameters up by curve, so the key must be
+	// on its S256 rather than btcec's
+	ecdsaPub, err := crypto.UnmarshalPubkey(pub.SerializeUncompressed())
+	if err != nil {
+		return nil, err
+	}
+	return gethecies.Encrypt(random, gethecies.ImportECDSAPublic(ecdsaPub), plaintext, nil, nil)
+}
+
+func decryptGeth(secret *security.SecretBuffer, ciphertext []byte) (*security.SecretBuffer, error) {
+	if raw, ok := decodeHexText(ciphertext); ok {
+		ciphertext = raw
+	}
+	privateKey, err := commonPrivateKey.PrivateKey(secret)
+	if err != nil {
+		return nil, err
+	}
+	// ImportECDSA shares D with privateKey, so this wipes both
+	defer security.ZeroPrivateKey(privateKey)
+
+	plaintext, err := gethecies.ImportECDSA(privateKey).Decrypt(ciphertext, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecrypt, err)
+	}
+	return security.NewSecretBufferFrom(plaintext)
+}
+
+// decodeHexText decodes data if it is entirely (optionally 0x-prefixed)
+// hex, as binary ciphertexts never are
+func decodeHexText(data []byte) ([]byte, bool) {
+	text := bytes.TrimSpace(data)
+	if len(text) >= 2 && text[0] == '0' && (text[1] == 'x' || text[1] == 'X') {
+		text = text[2:]
+	}
+	raw := make([]byte, hex.DecodedLen(len(text)))
+	if _, err := hex.Decode(raw, text); err != nil || len(text) == 0 {
+		return nil, false
+	}
+	return raw, true
+}
@@ -0,0 +1,265 @@
+// Package entropy implements health tests for short entropy samples such as
+// the 128-256 bits behind a BIP39 mnemonic.
+//
+// The tests follow NIST SP 800-90B (repetition count, adaptive proportion)
+// and SP 800-22 (frequency, runs), plus a chi-square test on the nibble
+// distribution and a check for repeating byte patterns. The cutoffs in
+// SP 800-90B assume windows of 512+ samples, so instead every test computes
+// an exact p-value under the hypothesis of independent uniform bits and fails
+// when it falls below alpha. Each test therefore rejects truly random input
+// with probability at most alpha, and a sample fails at least one of the
+// tests with probability at most FalsePositiveRate(alpha).
+package entropy
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"strings"
+)
+
+var (
+	// ErrSampleSize indicates a sample outside MinSampleBytes-MaxSampleBytes
+	ErrSampleSize = errors.New("entropy sample must be 16-32 bytes")
+	// ErrInvalidAlpha indicates a significance level outside (0, 1)
+	ErrInvalidAlpha = errors.New("alpha must be between 0 and 1")
+	// ErrHealthTestFailed indicates the sample failed at least one test
+	ErrHealthTestFailed = errors.New("entropy failed health tests")
+)
+
+const (
+	// MinSampleBytes is the shortest sample that can be tested (BIP39 minimum)
+	MinSampleBytes = 16
+	// MaxSampleBytes is the longest sample that can be tested (BIP39 maximum)
+	MaxSampleBytes = 32
+	// DefaultAlpha is the per-test false positive rate used by Validate.
+	// With six tests, random entropy is rejected at most 6 times in a million.
+	DefaultAlpha = 1e-6
+)
+
+// Result is the outcome of a single health test
+type Result struct {
+	Name string
+	// Statistic is the observed test statistic, e.g. the longest run
+	Statistic float64
+	// PValue is the probability of a statistic at least this extreme for
+	// uniformly random input
+	PValue float64
+	Passed bool
+}
+
+// Report collects the results of all health tests on one sample
+type Report struct {
+	Bits    int
+	Alpha   float64
+	Results []Result
+}
+
+// Passed reports whether every test passed
+func (r *Report) Passed() bool {
+	return len(r.Failed()) == 0
+}
+
+// Failed returns the results of the tests that failed
+func (r *Report) Failed() []Result {
+	var failed []Result
+	for _, res := range r.Results {
+		if !res.Passed {
+			failed = append(failed, res)
+		}
+	}
+	return failed
+}
+
+// Err returns nil if every test passed, otherwise an error wrapping
+// ErrHealthTestFailed that names the failed tests
+func (r *Report) Err() error {
+	failed := r.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+	names := make([]string, len(failed))
+	for i, res := range failed {
+		names[i] = fmt.Sprintf("%s (p=%.2g)", res.Name, res.PValue)
+	}
+	return fmt.Errorf("%w: %s", ErrHealthTestFailed, strings.Join(names, ", "))
+}
+
+// String formats the report as a table
+func (r *Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-30s %10s %10s  %s\n", "Test", "Statistic", "p-value", "Result")
+	for _, res := range r.Results {
+		status := "PASS"
+		if !res.Passed {
+			status = "FAIL"
+		}
+		fmt.Fprintf(&b, "%-30s %10.4g %10.3g  %s\n", res.Name, res.Statistic, res.PValue, status)
+	}
+	fmt.Fprintf(&b, "%d bits, alpha %.3g per test, false positive rate <= %.3g",
+		r.Bits, r.Alpha, FalsePositiveRate(r.Alpha))
+	return b.String()
+}
+
+// test computes a statistic and its p-value for a sample
+type test struct {
+	name string
+	run  func(sample []byte) (statistic, pValue float64)
+}
+
+var tests = []test{
+	{"repetition count (bits)", repetitionCount},
+	{"adaptive proportion (nibbles)", adaptiveProportion},
+	{"frequency (monobit)", monobit},
+	{"runs", runs},
+	{"chi-square (nibbles)", chiSquareNibbles},
+	{"repeating pattern", repeatingPattern},
+}
+
+// FalsePositiveRate returns an upper bound on the probability that uniformly
+// random entropy fails at least one test at the given per-test alpha
+func FalsePositiveRate(alpha float64) float64 {
+	return math.Min(float64(len(tests))*alpha, 1)
+}
+
+// Check runs every health test on sample with per-test significance alpha
+func Check(sample []byte, alpha float64) (*Report, error) {
+	if len(sample) < MinSampleBytes || len(sample) > MaxSampleBytes {
+		return nil, fmt.Errorf("%w: got %d bytes", ErrSampleSize, len(sample))
+	}
+	if !(alpha > 0 && alpha < 1) {
+		return nil, fmt.Errorf("%w: got %v", ErrInvalidAlpha, alpha)
+	}
+
+	report := &Report{Bits: len(sample) * 8, Alpha: alpha}
+	for _, t := range tests {
+		statistic, pValue := t.run(sample)
+		report.Results = append(report.Results, Result{
+			Name:      t.name,
+			Statistic: statistic,
+			PValue:    pValue,
+			Passed:    pValue >= alpha,
+		})
+	}
+	return report, nil
+}
+
+// Validate runs Check with DefaultAlpha and returns the report's error
+func Validate(sample []byte) error {
+	report, err := Check(sample, DefaultAlpha)
+	if err != nil {
+		return err
+	}
+	return report.Err()
+}
+
+func bitAt(sample []byte, i int) byte {
+	return (sample[i/8] >> (7 - uint(i%8))) & 1
+}
+
+func nibbles(sample []byte) []byte {
+	out := make([]byte, 0, len(sample)*2)
+	for _, b := range sample {
+		out = append(out, b>>4, b&0x0f)
+	}
+	return out
+}
+
+// repetitionCount is the SP 800-90B repetition count test: the longest run
+// of identical bits, with the exact probability of a run at least that long
+func repetitionCount(sample []byte) (float64, float64) {
+	n := len(sample) * 8
+	longest, run := 1, 1
+	for i := 1; i < n; i++ {
+		if bitAt(sample, i) == bitAt(sample, i-1) {
+			run++
+		} else {
+			run = 1
+		}
+		if run > longest {
+			longest = run
+		}
+	}
+	return float64(longest), longestRunUpper(n, 2, longest)
+}
+
+// adaptiveProportion is the SP 800-90B adaptive proportion test on 4-bit
+// symbols: how often the first symbol recurs in the rest of the sample
+func adaptiveProportion(sample []byte) (float64, float64) {
+	symbols := nibbles(sample)
+	count := 0
+	for _, s := range symbols[1:] {
+		if s == symbols[0] {
+			count++
+		}
+	}
+	return float64(count + 1), binomialUpper(len(symbols)-1, count, 1.0/16)
+}
+
+// monobit is the SP 800-22 frequency test: the number of one bits, two-sided
+func monobit(sample []byte) (float64, float64) {
+	ones := 0
+	for _, b := range sample {
+		ones += bits.OnesCount8(b)
+	}
+	return float64(ones), binomialTwoSided(len(sample)*8, ones)
+}
+
+// runs counts bit transitions, two-sided. For independent fair bits each
+// adjacent pair differs with probability 1/2, so alternating patterns such
+// as 0xaa fail on too many transitions and long runs on too few.
+func runs(sample []byte) (float64, float64) {
+	n := len(sample) * 8
+	transitions := 0
+	for i := 1; i < n; i++ {
+		if bitAt(sample, i) != bitAt(sample, i-1) {
+			transitions++
+		}
+	}
+	return float64(transitions + 1), binomialTwoSided(n-1, transitions)
+}
+
+// chiSquareNibbles is a chi-square goodness of fit test of the 16 nibble
+// values. With 32-64 nibbles the expected count per cell is 2-4, far below
+// where the chi-square approximation holds, so the p-value is exact.
+func chiSquareNibbles(sample []byte) (float64, float64) {
+	var counts [16]int
+	symbols := nibbles(sample)
+	for _, s := range symbols {
+		counts[s]++
+	}
+	sumSquares := 0
+	for _, c := range counts {
+		sumSquares += c * c
+	}
+	n := len(symbols)
+	chi := float64(sumSquares)*16/float64(n) - float64(n)
+	return chi, sumSquaresUpper(n, 16, sumSquares)
+}
+
+// repeatingPattern finds the shortest period p such that the sample repeats
+// every p bytes. The p-value is the union bound sum(256^-(n-q)) for q <= p,
+// so only patterns that repeat over a substantial part of the sample fail.
+func repeatingPattern(sample []byte) (float64, float64) {
+	n := len(sample)
+	for p := 1; p < n; p++ {
+		if hasPeriod(sample, p) {
+			pValue := 0.0
+			for q := 1; q <= p; q++ {
+				pValue += math.Pow(256, -float64(n-q))
+			}
+			return float64(p), math.Min(pValue, 1)
+		}
+	}
+	return float64(n), 1
+}
+
+func hasPeriod(sample []byte, p int) bool {
+	for i := p; i < len(sample); i++ {
+		if sample[i] != sample[i-p] {
+			return false
+		}
+	}
+	return true
+}
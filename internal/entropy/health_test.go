@@ -0,0 +1,155 @@
+package entropy
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestCheck_Patterns(t *testing.T) {
+	tests := []struct {
+		name   string
+		sample string
+		// failing lists tests that must fail; others may fail too
+		failing []string
+	}{
+		{"all ones", strings.Repeat("ff", 16), []string{"repetition count (bits)", "frequency (monobit)", "runs"}},
+		{"alternating bits", strings.Repeat("aa", 16), []string{"runs", "repeating pattern"}},
+		{"alternating bytes", strings.Repeat("55aa", 8), []string{"runs"}},
+		{"two byte pattern", strings.Repeat("abcd", 8), []string{"chi-square (nibbles)", "repeating pattern"}},
+		{"counter", "000102030405060708090a0b0c0d0e0f", []string{"frequency (monobit)"}},
+		{"mostly ones", strings.Repeat("7f", 16), []string{"frequency (monobit)"}},
+		{"repeated half", strings.Repeat("9e885d952ad362ca", 2), []string{"repeating pattern"}},
+		{"256-bit stuck nibble", strings.Repeat("a0", 32), []string{"adaptive proportion (nibbles)", "chi-square (nibbles)"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := Check(mustHex(t, tt.sample), DefaultAlpha)
+			if err != nil {
+				t.Fatalf("Check() error: %v", err)
+			}
+			if report.Passed() {
+				t.Fatalf("Check() passed patterned input:\n%s", report)
+			}
+			failed := make(map[string]bool)
+			for _, res := range report.Failed() {
+				failed[res.Name] = true
+			}
+			for _, name := range tt.failing {
+				if !failed[name] {
+					t.Errorf("expected %q to fail:\n%s", name, report)
+				}
+			}
+			if err := report.Err(); !errors.Is(err, ErrHealthTestFailed) {
+				t.Errorf("Report.Err() = %v, want %v", err, ErrHealthTestFailed)
+			}
+		})
+	}
+}
+
+func TestCheck_Random(t *testing.T) {
+	// BIP39 test vector entropies and SHA256 outputs
+	samples := []string{
+		"9e885d952ad362caeb4efe34a8e91bd2",
+		"6610b25967cdcca9d59875f5cb50b0ea75433311869e930b",
+		"68a79eaca2324873eacc50cb9c6eca8cc68ea5d936f98787c60c7ebc74e6ce7c",
+		"edceb2d86ed94b3b67b707e8721ca00f",
+	}
+	for _, s := range samples {
+		if err := Validate(mustHex(t, s)); err != nil {
+			t.Errorf("Validate(%s) error: %v", s, err)
+		}
+	}
+}
+
+func TestCheck_InvalidInput(t *testing.T) {
+	if _, err := Check(make([]byte, 15), DefaultAlpha); !errors.Is(err, ErrSampleSize) {
+		t.Errorf("Check() error = %v, want %v", err, ErrSampleSize)
+	}
+	if _, err := Check(make([]byte, 33), DefaultAlpha); !errors.Is(err, ErrSampleSize) {
+		t.Errorf("Check() error = %v, want %v", err, ErrSampleSize)
+	}
+	for _, alpha := range []float64{0, 1, -0.5, math.NaN()} {
+		if _, err := Check(make([]byte, 16), alpha); !errors.Is(err, ErrInvalidAlpha) {
+			t.Errorf("Check(alpha=%v) error = %v, want %v", alpha, err, ErrInvalidAlpha)
+		}
+	}
+}
+
+// TestFalsePositiveRate checks the stated false positive rate empirically.
+// At alpha = 1% each test must reject about 1% of random samples or fewer,
+// since the p-values are exact (and conservative for discrete statistics).
+func TestFalsePositiveRate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Monte Carlo calibration")
+	}
+	const alpha = 0.01
+	const trials = 20000
+	// Three standard deviations of a binomial proportion at alpha
+	limit := alpha + 3*math.Sqrt(alpha*(1-alpha)/trials)
+
+	rng := rand.New(rand.NewSource(1))
+	for _, size := range []int{16, 32} {
+		rejected := make(map[string]int)
+		anyRejected := 0
+		sample := make([]byte, size)
+		for i := 0; i < trials; i++ {
+			rng.Read(sample)
+			report, err := Check(sample, alpha)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, res := range report.Failed() {
+				rejected[res.Name]++
+			}
+			if !report.Passed() {
+				anyRejected++
+			}
+		}
+
+		for _, tt := range tests {
+			rate := float64(rejected[tt.name]) / trials
+			if rate > limit {
+				t.Errorf("%d bytes: %s rejected %.4f of random samples, want <= %.4f", size, tt.name, rate, alpha)
+			}
+		}
+		if rate := float64(anyRejected) / trials; rate > FalsePositiveRate(alpha) {
+			t.Errorf("%d bytes: rejected %.4f of random samples, stated rate %.4f", size, rate, FalsePositiveRate(alpha))
+		}
+	}
+}
+
+func TestReportString(t *testing.T) {
+	report, err := Check(bytes.Repeat([]byte{0xaa}, 16), DefaultAlpha)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := report.String()
+	for _, want := range []string{"runs", "FAIL", "PASS", "false positive rate"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Report.String() missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func BenchmarkValidate(b *testing.B) {
+	sample := make([]byte, 32)
+	rand.New(rand.NewSource(1)).Read(sample)
+	for i := 0; i < b.N; i++ {
+		_ = Validate(sample)
+	}
+}
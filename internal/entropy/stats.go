@@ -0,0 +1,184 @@
+package entropy
+
+import (
+	"math"
+	"sync"
+)
+
+// binomialPMF returns P(X = k) for X ~ Bin(n, p)
+func binomialPMF(n, k int, p float64) float64 {
+	if k < 0 || k > n {
+		return 0
+	}
+	if p == 0 {
+		if k == 0 {
+			return 1
+		}
+		return 0
+	}
+	lnChoose := lgamma(n+1) - lgamma(k+1) - lgamma(n-k+1)
+	return math.Exp(lnChoose + float64(k)*math.Log(p) + float64(n-k)*math.Log1p(-p))
+}
+
+// binomialUpper returns P(X >= k) for X ~ Bin(n, p). Terms are summed from
+// the far tail inwards so tiny probabilities keep their precision.
+func binomialUpper(n, k int, p float64) float64 {
+	if k <= 0 {
+		return 1
+	}
+	sum := 0.0
+	for i := n; i >= k; i-- {
+		sum += binomialPMF(n, i, p)
+	}
+	return math.Min(sum, 1)
+}
+
+// binomialLower returns P(X <= k) for X ~ Bin(n, p)
+func binomialLower(n, k int, p float64) float64 {
+	if k >= n {
+		return 1
+	}
+	sum := 0.0
+	for i := 0; i <= k; i++ {
+		sum += binomialPMF(n, i, p)
+	}
+	return math.Min(sum, 1)
+}
+
+// binomialTwoSided returns the two-sided p-value of observing k successes
+// for X ~ Bin(n, 1/2), doubling the smaller tail
+func binomialTwoSided(n, k int) float64 {
+	tail := math.Min(binomialLower(n, k, 0.5), binomialUpper(n, k, 0.5))
+	return math.Min(2*tail, 1)
+}
+
+// longestRunUpper returns the probability that n independent uniform symbols
+// from an alphabet of size k contain a run of at least length identical
+// symbols.
+func longestRunUpper(n, k, length int) float64 {
+	if length <= 1 {
+		return 1
+	}
+	if length > n {
+		return 0
+	}
+
+	// state[r] is the probability that the prefix has no run >= length and
+	// ends in a run of exactly r+1 symbols
+	stay := 1 / float64(k)
+	state := make([]float64, length-1)
+	next := make([]float64, length-1)
+	state[0] = 1
+	for i := 1; i < n; i++ {
+		total := 0.0
+		for _, v := range state {
+			total += v
+		}
+		for r := range next {
+			next[r] = 0
+		}
+		next[0] = total * (1 - stay)
+		for r := 0; r < length-2; r++ {
+			next[r+1] = state[r] * stay
+		}
+		state, next = next, state
+	}
+
+	noRun := 0.0
+	for _, v := range state {
+		noRun += v
+	}
+	return math.Max(1-noRun, 0)
+}
+
+// sumSquaresDist holds the exact distribution of S = sum(o_i^2) for the cell
+// counts o_i of n samples spread uniformly over k cells
+type sumSquaresDist struct {
+	// upper[s] is P(S >= s)
+	upper []float64
+}
+
+var (
+	sumSquaresMu    sync.Mutex
+	sumSquaresCache = map[[2]int]*sumSquaresDist{}
+)
+
+// sumSquaresUpper returns P(S >= s) for the multinomial sum of squared
+// counts, computed exactly by dynamic programming over the cells and cached
+// per (n, k). The chi-square statistic is S*k/n - n, so this is an exact
+// chi-square tail that stays valid for expected counts far below 5.
+func sumSquaresUpper(n, k, s int) float64 {
+	key := [2]int{n, k}
+	sumSquaresMu.Lock()
+	dist, ok := sumSquaresCache[key]
+	if !ok {
+		dist = newSumSquaresDist(n, k)
+		sumSquaresCache[key] = dist
+	}
+	sumSquaresMu.Unlock()
+
+	if s <= 0 {
+		return 1
+	}
+	if s >= len(dist.upper) {
+		return 0
+	}
+	return dist.upper[s]
+}
+
+func newSumSquaresDist(n, k int) *sumSquaresDist {
+	maxS := n * n
+	invFact := make([]float64, n+1)
+	for i := range invFact {
+		invFact[i] = math.Exp(-lgamma(i + 1))
+	}
+
+	// weight[m][s] sums prod(1/o_i!) over the cells processed so far, with
+	// m samples used and sum of squares s
+	weight := make([][]float64, n+1)
+	for m := range weight {
+		weight[m] = make([]float64, m*m+1)
+	}
+	weight[0][0] = 1
+
+	for cell := 0; cell < k; cell++ {
+		next := make([][]float64, n+1)
+		for m := range next {
+			next[m] = make([]float64, m*m+1)
+		}
+		last := cell == k-1
+		for m, row := range weight {
+			for s, w := range row {
+				if w == 0 {
+					continue
+				}
+				if last {
+					// The last cell takes all remaining samples
+					o := n - m
+					next[n][s+o*o] += w * invFact[o]
+					continue
+				}
+				for o := 0; m+o <= n; o++ {
+					next[m+o][s+o*o] += w * invFact[o]
+				}
+			}
+		}
+		weight = next
+	}
+
+	// P(counts) = n!/k^n * prod(1/o_i!)
+	scale := math.Exp(lgamma(n+1) - float64(n)*math.Log(float64(k)))
+	upper := make([]float64, maxS+2)
+	for s := maxS; s >= 0; s-- {
+		upper[s] = upper[s+1] + weight[n][s]*scale
+	}
+	for s := range upper {
+		upper[s] = math.Min(upper[s], 1)
+	}
+	return &sumSquaresDist{upper: upper}
+}
+
+func lgamma(x int) float64 {
+	v, _ := math.Lgamma(float64(x))
+	return v
+}
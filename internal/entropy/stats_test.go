@@ -0,0 +1,97 @@
+package entropy
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBinomialTails(t *testing.T) {
+	for _, n := range []int{1, 10, 127, 256} {
+		total := 0.0
+		for k := 0; k <= n; k++ {
+			total += binomialPMF(n, k, 0.5)
+		}
+		if math.Abs(total-1) > 1e-9 {
+			t.Errorf("Bin(%d, 1/2) sums to %v", n, total)
+		}
+	}
+
+	// P(X >= 3) for Bin(4, 1/2) is 5/16
+	if got := binomialUpper(4, 3, 0.5); math.Abs(got-5.0/16) > 1e-12 {
+		t.Errorf("binomialUpper(4, 3) = %v, want %v", got, 5.0/16)
+	}
+	if got := binomialLower(4, 1, 0.5); math.Abs(got-5.0/16) > 1e-12 {
+		t.Errorf("binomialLower(4, 1) = %v, want %v", got, 5.0/16)
+	}
+	if got := binomialTwoSided(128, 64); got != 1 {
+		t.Errorf("binomialTwoSided(128, 64) = %v, want 1", got)
+	}
+	if got := binomialTwoSided(128, 128); math.Abs(got-math.Pow(2, -127)) > 1e-50 {
+		t.Errorf("binomialTwoSided(128, 128) = %v, want 2^-127", got)
+	}
+}
+
+func TestLongestRunUpper(t *testing.T) {
+	// Compare against brute force enumeration of all 12-bit strings
+	const n = 12
+	counts := make([]int, n+2)
+	for v := 0; v < 1<<n; v++ {
+		longest, run := 1, 1
+		for i := 1; i < n; i++ {
+			if (v>>i)&1 == (v>>(i-1))&1 {
+				run++
+			} else {
+				run = 1
+			}
+			if run > longest {
+				longest = run
+			}
+		}
+		counts[longest]++
+	}
+
+	atLeast := 0
+	for length := n; length >= 1; length-- {
+		atLeast += counts[length]
+		want := float64(atLeast) / (1 << n)
+		if got := longestRunUpper(n, 2, length); math.Abs(got-want) > 1e-12 {
+			t.Errorf("longestRunUpper(%d, 2, %d) = %v, want %v", n, length, got, want)
+		}
+	}
+}
+
+func TestSumSquaresUpper(t *testing.T) {
+	// Compare against brute force enumeration of 6 samples over 4 cells
+	const n, k = 6, 4
+	total := 1
+	for i := 0; i < n; i++ {
+		total *= k
+	}
+	counts := make(map[int]int)
+	for v := 0; v < total; v++ {
+		var cells [k]int
+		x := v
+		for i := 0; i < n; i++ {
+			cells[x%k]++
+			x /= k
+		}
+		s := 0
+		for _, c := range cells {
+			s += c * c
+		}
+		counts[s]++
+	}
+
+	for s := 0; s <= n*n+1; s++ {
+		atLeast := 0
+		for v, c := range counts {
+			if v >= s {
+				atLeast += c
+			}
+		}
+		want := float64(atLeast) / float64(total)
+		if got := sumSquaresUpper(n, k, s); math.Abs(got-want) > 1e-12 {
+			t.Errorf("sumSquaresUpper(%d, %d, %d) = %v, want %v", n, k, s, got, want)
+		}
+	}
+}
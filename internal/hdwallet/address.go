@@ -9,6 +9,9 @@ import (
 	"golang.org/x/crypto/ripemd160"
 )
 
+// PubkeyToAddress encodes a P2PKH address with version byte netId. Chain
+// presets (Litecoin, Dogecoin, ...) know their version bytes; prefer
+// UTXOParams.Address.
 func PubkeyToAddress(key []byte, netId byte) string {
 	hash160Bytes := btcutil.Hash160(key)
 	return base58.CheckEncode(hash160Bytes[:ripemd160.Size], netId)
@@ -0,0 +1,137 @@
+package hdwallet
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil/bech32"
+)
+
+// ErrInvalidCashAddr indicates a malformed CashAddr string or checksum
+var ErrInvalidCashAddr = errors.New("invalid CashAddr")
+
+var errCashAddrChecksum = fmt.Errorf("%w: checksum mismatch", ErrInvalidCashAddr)
+
+// cashAddrCharset is the bech32 character set, which CashAddr shares
+const cashAddrCharset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// CashAddr version byte types
+const (
+	cashAddrP2PKH = 0
+	cashAddrP2SH  = 1
+)
+
+// cashAddrPolymod is the BCH code checksum of the CashAddr specification
+func cashAddrPolymod(values []byte) uint64 {
+	c := uint64(1)
+	for _, d := range values {
+		c0 := byte(c >> 35)
+		c = (c&0x07ffffffff)<<5 ^ uint64(d)
+		if c0&0x01 != 0 {
+			c ^= 0x98f2bc8e61
+		}
+		if c0&0x02 != 0 {
+			c ^= 0x79b76d99e2
+		}
+		if c0&0x04 != 0 {
+			c ^= 0xf33e5fb3c4
+		}
+		if c0&0x08 != 0 {
+			c ^= 0xae2eabe2a8
+		}
+		if c0&0x10 != 0 {
+			c ^= 0x1e4f43e470
+		}
+	}
+	return c ^ 1
+}
+
+// cashAddrChecksumInput is the lower 5 bits of each prefix character, a zero
+// separator and the payload
+func cashAddrChecksumInput(prefix string, payload []byte) []byte {
+	values := make([]byte, 0, len(prefix)+1+len(payload)+8)
+	for i := 0; i < len(prefix); i++ {
+		values = append(values, prefix[i]&0x1f)
+	}
+	values = append(values, 0)
+	return append(values, payload...)
+}
+
+// EncodeCashAddr encodes a 20-byte hash as a Bitcoin Cash CashAddr with the
+// given prefix (e.g. "bitcoincash") and type (P2PKH or P2SH)
+func EncodeCashAddr(prefix string, t AddressType, hash []byte) (string, error) {
+	var kind byte
+	switch t {
+	case P2PKH:
+		kind = cashAddrP2PKH
+	case P2SH:
+		kind = cashAddrP2SH
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedAddressType, t)
+	}
+	if len(hash) != 20 {
+		return "", fmt.Errorf("%w: %d-byte hash", ErrInvalidCashAddr, len(hash))
+	}
+	// size code 0 is a 160-bit hash
+	payload, err := bech32.ConvertBits(append([]byte{kind << 3}, hash...), 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	values := append(cashAddrChecksumInput(prefix, payload), make([]byte, 8)...)
+	mod := cashAddrPolymod(values)
+	for i := 0; i < 8; i++ {
+		payload = append(payload, byte(mod>>(5*(7-i)))&0x1f)
+	}
+
+	var b strings.Builder
+	b.WriteString(prefix)
+	b.WriteByte(':')
+	for _, v := range payload {
+		b.WriteByte(cashAddrCharset[v])
+	}
+	return b.String(), nil
+}
+
+// DecodeCashAddr decodes a CashAddr, with or without its prefix. A missing
+// prefix is taken to be defaultPrefix; the returned prefix is the one the
+// checksum was verified against.
+func DecodeCashAddr(address, defaultPrefix string) (prefix string, t AddressType, hash []byte, err error) {
+	if strings.ToLower(address) != address && strings.ToUpper(address) != address {
+		return "", "", nil, fmt.Errorf("%w: mixed case", ErrInvalidCashAddr)
+	}
+	address = strings.ToLower(address)
+	prefix, data, offset := defaultPrefix, address, 0
+	if i := strings.LastIndexByte(address, ':'); i >= 0 {
+		prefix, data, offset = address[:i], address[i+1:], i+1
+	}
+	if prefix == "" || len(data) < 8 {
+		return "", "", nil, ErrInvalidCashAddr
+	}
+
+	values := make([]byte, len(data))
+	for i := 0; i < len(data); i++ {
+		v := strings.IndexByte(cashAddrCharset, data[i])
+		if v < 0 {
+			return "", "", nil, fmt.Errorf("%w: invalid character %q at position %d", ErrInvalidCashAddr, data[i], offset+i+1)
+		}
+		values[i] = byte(v)
+	}
+	if cashAddrPolymod(cashAddrChecksumInput(prefix, values)) != 0 {
+		return "", "", nil, errCashAddrChecksum
+	}
+
+	payload, err := bech32.ConvertBits(values[:len(values)-8], 5, 8, false)
+	if err != nil || len(payload) != 21 {
+		return "", "", nil, fmt.Errorf("%w: bad payload length", ErrInvalidCashAddr)
+	}
+	switch payload[0] {
+	case cashAddrP2PKH << 3:
+		t = P2PKH
+	case cashAddrP2SH << 3:
+		t = P2SH
+	default:
+		return "", "", nil, fmt.Errorf("%w: unsupported version byte 0x%02x", ErrInvalidCashAddr, payload[0])
+	}
+	return prefix, t, payload[1:], nil
+}
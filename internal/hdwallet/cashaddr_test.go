@@ -0,0 +1,48 @@
+package hdwallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestCashAddr(t *testing.T) {
+	// CashAddr specification test vectors
+	tests := []struct {
+		address string
+		prefix  string
+		t       AddressType
+		hash    string
+	}{
+		{"bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a", "bitcoincash", P2PKH, "76a04053bda0a88bda5177b86a15c3b29f559873"},
+		{"bitcoincash:qr6m7j9njldwwzlg9v7v53unlr4jkmx6eylep8ekg2", "bitcoincash", P2PKH, "f5bf48b397dae70be82b3cca4793f8eb2b6cdac9"},
+		{"bchtest:pr6m7j9njldwwzlg9v7v53unlr4jkmx6eyvwc0uz5t", "bchtest", P2SH, "f5bf48b397dae70be82b3cca4793f8eb2b6cdac9"},
+		{"pref:pr6m7j9njldwwzlg9v7v53unlr4jkmx6ey65nvtks5", "pref", P2SH, "f5bf48b397dae70be82b3cca4793f8eb2b6cdac9"},
+	}
+	for _, test := range tests {
+		hash, _ := hex.DecodeString(test.hash)
+		got, err := EncodeCashAddr(test.prefix, test.t, hash)
+		if err != nil || got != test.address {
+			t.Errorf("EncodeCashAddr(%s, %s) = %s, %v, want %s", test.prefix, test.t, got, err, test.address)
+		}
+		prefix, typ, decoded, err := DecodeCashAddr(test.address, "")
+		if err != nil || prefix != test.prefix || typ != test.t || hex.EncodeToString(decoded) != test.hash {
+			t.Errorf("DecodeCashAddr(%s) = %s, %s, %x, %v", test.address, prefix, typ, decoded, err)
+		}
+	}
+
+	// The prefix may be omitted, and upper case is allowed
+	if _, _, _, err := DecodeCashAddr("QPM2QSZNHKS23Z7629MMS6S4CWEF74VCWVY22GDX6A", "bitcoincash"); err != nil {
+		t.Errorf("DecodeCashAddr without prefix: %v", err)
+	}
+	for _, bad := range []string{
+		"bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6b", // checksum
+		"bchtest:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a",     // prefix is checksummed
+		"bitcoincash:Qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a", // mixed case
+		"bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdxbo", // 'o' is not in the charset
+	} {
+		if _, _, _, err := DecodeCashAddr(bad, ""); !errors.Is(err, ErrInvalidCashAddr) {
+			t.Errorf("DecodeCashAddr(%s) error = %v, want ErrInvalidCashAddr", bad, err)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package hdwallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spark8899/gowallet/internal/security"
+)
+
+// Chain describes a secp256k1 chain whose accounts come from the same BIP32
+// keys as Ethereum, with its own address and private key encodings
+type Chain struct {
+	Name        string
+	CoinType    uint32 // SLIP-44
+	DefaultPath string
+	// Address encodes a public key as an account address
+	Address func(*btcec.PublicKey) string
+	// Cosmos is set for Cosmos SDK chains
+	Cosmos *CosmosParams
+	// UTXO is set for Bitcoin-derived chains
+	UTXO *UTXOParams
+	// privateKey encodes the 32-byte private key in the format the chain's
+	// wallets import
+	privateKey func(raw []byte) (*security.SecretBuffer, error)
+}
+
+var (
+	// Ethereum is the EIP-55 checksummed Keccak-256 address
+	Ethereum = &Chain{
+		Name:        "ethereum",
+		CoinType:    60,
+		DefaultPath: "m/44'/60'/0'/0/0",
+		Address: func(pub *btcec.PublicKey) string {
+			return crypto.PubkeyToAddress(*pub.ToECDSA()).Hex()
+		},
+		privateKey: hexPrivateKey,
+	}
+	// Tron uses the Ethereum address bytes in base58check with prefix 0x41;
+	// TronLink imports the private key as plain hex
+	Tron = &Chain{
+		Name:        "tron",
+		CoinType:    195,
+		DefaultPath: "m/44'/195'/0'/0/0",
+		Address: func(pub *btcec.PublicKey) string {
+			return TronAddress(crypto.PubkeyToAddress(*pub.ToECDSA()))
+		},
+		privateKey: plainHexPrivateKey,
+	}
+)
+
+// Chains lists every supported secp256k1 chain in display order
+var Chains = []*Chain{Ethereum, Tron, CosmosHub, Osmosis, Evmos, Injective, Litecoin, Dogecoin, Dash, BitcoinCash}
+
+// ChainByName looks up a secp256k1 chain by name (case-insensitive)
+func ChainByName(name string) (*Chain, error) {
+	for _, c := range Chains {
+		if strings.EqualFold(c.Name, name) {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownChain, name)
+}
+
+func (c *Chain) String() string {
+	return c.Name
+}
+
+// hexPrivateKey encodes raw as 0x-prefixed hex
+func hexPrivateKey(raw []byte) (*security.SecretBuffer, error) {
+	s, err := security.NewSecretBuffer(2 + hex.EncodedLen(len(raw)))
+	if err != nil {
+		return nil, err
+	}
+	copy(s.Bytes(), "0x")
+	hex.Encode(s.Bytes()[2:], raw)
+	return s, nil
+}
+
+// plainHexPrivateKey encodes raw as hex without a prefix
+func plainHexPrivateKey(raw []byte) (*security.SecretBuffer, error) {
+	s, err := security.NewSecretBuffer(hex.EncodedLen(len(raw)))
+	if err != nil {
+		return nil, err
+	}
+	hex.Encode(s.Bytes(), raw)
+	return s, nil
+}
+
+// ChainKeyInfo holds a derived account in a chain's encodings. The private
+// key lives in a SecretBuffer; call Destroy when done.
+type ChainKeyInfo struct {
+	Path       string
+	Chain      *Chain
+	Address    string
+	PublicKey  string                 // compressed, hex
+	PrivateKey *security.SecretBuffer // in the chain's import format
+	// XOnlyPublicKey is the BIP340 x-only form of PublicKey, hex
+	XOnlyPublicKey string
+}
+
+// Destroy wipes the private key
+func (k *ChainKeyInfo) Destroy() {
+	k.PrivateKey.Destroy()
+}
+
+// String returns the "address:privateKey" form
+func (k *ChainKeyInfo) String() string {
+	var b strings.Builder
+	k.WriteTo(&b)
+	return b.String()
+}
+
+// WriteTo writes the "address:privateKey" form to w
+func (k *ChainKeyInfo) WriteTo(w io.Writer) (int64, error) {
+	return writeAll(w, []byte(k.Address), []byte(":"), k.PrivateKey.Bytes())
+}
+
+// WriteDetails writes a multi-line, human readable description to w
+func (k *ChainKeyInfo) WriteDetails(w io.Writer) (int64, error) {
+	return writeAll(w,
+		fmt.Appendf(nil, "Chain: %s\n", k.Chain.Name),
+		fmt.Appendf(nil, "Path: %s\n", k.Path),
+		fmt.Appendf(nil, "Address: %s\n", k.Address),
+		[]byte("Private Key: "), k.PrivateKey.Bytes(), []byte("\n"),
+		fmt.Appendf(nil, "Public Key: %s\n", k.PublicKey),
+		fmt.Appendf(nil, "X-Only Public Key: %s", k.XOnlyPublicKey),
+	)
+}
+
+// ChainPathFromMnemonic derives the chain's account at pathStr (or its
+// default path if empty) from a BIP39 mnemonic
+func ChainPathFromMnemonic(mnemonic *security.SecretBuffer, pathStr string, chain *Chain, net *Network) (*ChainKeyInfo, error) {
+	if pathStr == "" {
+		pathStr = chain.DefaultPath
+	}
+	info, err := PathFromMnemonic(mnemonic, pathStr, net)
+	if err != nil {
+		return nil, err
+	}
+	defer info.Destroy()
+	return chainKeyInfo(info, chain)
+}
+
+// ChainPathFromSeed derives the chain's account at pathStr (or its default
+// path if empty) from a hex encoded BIP39 seed
+func ChainPathFromSeed(seedHex *security.SecretBuffer, pathStr string, chain *Chain, net *Network) (*ChainKeyInfo, error) {
+	if pathStr == "" {
+		pathStr = chain.DefaultPath
+	}
+	info, err := PathFromSeed(seedHex, pathStr, net)
+	if err != nil {
+		return nil, err
+	}
+	defer info.Destroy()
+	return chainKeyInfo(info, chain)
+}
+
+func chainKeyInfo(info *KeyInfo, chain *Chain) (*ChainKeyInfo, error) {
+	compressed, err := hex.DecodeString(info.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := btcec.ParsePubKey(compressed)
+	if err != nil {
+		return nil, err
+	}
+
+	// KeyInfo.PrivateKey is 0x-prefixed hex
+	raw, err := security.NewSecretBuffer(hex.DecodedLen(info.PrivateKey.Len() - 2))
+	if err != nil {
+		return nil, err
+	}
+	defer raw.Destroy()
+	if _, err := hex.Decode(raw.Bytes(), info.PrivateKey.Bytes()[2:]); err != nil {
+		return nil, errors.New("malformed derived private key")
+	}
+
+	out := &ChainKeyInfo{
+		Path:           info.Path,
+		Chain:          chain,
+		Address:        chain.Address(pub),
+		PublicKey:      info.PublicKey,
+		XOnlyPublicKey: info.XOnlyPublicKey,
+	}
+	if out.PrivateKey, err = chain.privateKey(raw.Bytes()); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
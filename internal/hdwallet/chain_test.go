@@ -0,0 +1,63 @@
+package hdwallet
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func commonAddress(t *testing.T, s string) common.Address {
+	t.Helper()
+	if !common.IsHexAddress(s) {
+		t.Fatalf("%q is not an address", s)
+	}
+	return common.HexToAddress(s)
+}
+
+func TestChainPathFromMnemonic(t *testing.T) {
+	mnemonic := "test test test test test test test test test test test junk"
+
+	eth, err := ChainPathFromMnemonic(secretString(t, mnemonic), "", Ethereum, MainNet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer eth.Destroy()
+	// Hardhat/Anvil default account #0
+	if want := "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266:0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"; eth.String() != want {
+		t.Errorf("Ethereum = %s, want %s", eth, want)
+	}
+
+	// The same key in Tron encodings
+	tron, err := ChainPathFromMnemonic(secretString(t, mnemonic), Ethereum.DefaultPath, Tron, MainNet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tron.Destroy()
+	if want := TronAddress(commonAddress(t, eth.Address)); tron.Address != want {
+		t.Errorf("Tron address = %s, want %s", tron.Address, want)
+	}
+	if got := string(tron.PrivateKey.Bytes()); got != strings.TrimPrefix(string(eth.PrivateKey.Bytes()), "0x") {
+		t.Errorf("Tron private key = %s", got)
+	}
+
+	// Tron's own coin type gives a different account
+	tronDefault, err := ChainPathFromMnemonic(secretString(t, mnemonic), "", Tron, MainNet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tronDefault.Destroy()
+	if tronDefault.Path != "m/44'/195'/0'/0/0" || tronDefault.Address == tron.Address || tronDefault.Address[0] != 'T' {
+		t.Errorf("Tron default account = %s at %s", tronDefault.Address, tronDefault.Path)
+	}
+}
+
+func TestChainByName(t *testing.T) {
+	if c, err := ChainByName("TRON"); err != nil || c != Tron {
+		t.Errorf("ChainByName(TRON) = %v, %v", c, err)
+	}
+	if _, err := ChainByName("solana"); !errors.Is(err, ErrUnknownChain) {
+		t.Errorf("ChainByName(solana) error = %v, want ErrUnknownChain", err)
+	}
+}
@@ -0,0 +1,136 @@
+package hdwallet
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/bech32"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrNotCosmosChain indicates a bech32 prefix override for a chain that does
+// not use Cosmos SDK addresses
+var ErrNotCosmosChain = errors.New("not a Cosmos SDK chain")
+
+// CosmosParams holds the Cosmos SDK account settings of a chain
+type CosmosParams struct {
+	HRP string // bech32 account prefix
+	// EthSecp256k1 selects Ethermint keys: Keccak-256 (Ethereum) addresses and
+	// Keccak-256 sign-doc hashes with 65-byte r||s||v signatures
+	EthSecp256k1    bool
+	AminoPubKeyType string // amino JSON "type" of the public key
+	PubKeyTypeURL   string // protobuf Any type URL of the public key
+}
+
+const (
+	aminoSecp256k1   = "tendermint/PubKeySecp256k1"
+	typeURLSecp256k1 = "/cosmos.crypto.secp256k1.PubKey"
+)
+
+var (
+	// CosmosHub is the Cosmos Hub (ATOM)
+	CosmosHub = NewCosmosChain("cosmos", 118, "m/44'/118'/0'/0/0", CosmosParams{
+		HRP:             "cosmos",
+		AminoPubKeyType: aminoSecp256k1,
+		PubKeyTypeURL:   typeURLSecp256k1,
+	})
+	// Osmosis shares the Cosmos Hub coin type and key type
+	Osmosis = NewCosmosChain("osmosis", 118, "m/44'/118'/0'/0/0", CosmosParams{
+		HRP:             "osmo",
+		AminoPubKeyType: aminoSecp256k1,
+		PubKeyTypeURL:   typeURLSecp256k1,
+	})
+	// Evmos uses Ethermint eth_secp256k1 keys at the Ethereum path
+	Evmos = NewCosmosChain("evmos", 60, "m/44'/60'/0'/0/0", CosmosParams{
+		HRP:             "evmos",
+		EthSecp256k1:    true,
+		AminoPubKeyType: "ethermint/PubKeyEthSecp256k1",
+		PubKeyTypeURL:   "/ethermint.crypto.v1.ethsecp256k1.PubKey",
+	})
+	// Injective uses eth_secp256k1 keys under its own type names
+	Injective = NewCosmosChain("injective", 60, "m/44'/60'/0'/0/0", CosmosParams{
+		HRP:             "inj",
+		EthSecp256k1:    true,
+		AminoPubKeyType: "injective/PubKeyEthSecp256k1",
+		PubKeyTypeURL:   "/injective.crypto.v1beta1.ethsecp256k1.PubKey",
+	})
+)
+
+// NewCosmosChain returns a Cosmos SDK chain whose addresses are bech32 with
+// params.HRP. Cosmos wallets import the private key as plain hex.
+func NewCosmosChain(name string, coinType uint32, defaultPath string, params CosmosParams) *Chain {
+	return &Chain{
+		Name:        name,
+		CoinType:    coinType,
+		DefaultPath: defaultPath,
+		Address: func(pub *btcec.PublicKey) string {
+			address, err := CosmosAddress(pub, params.HRP, params.EthSecp256k1)
+			if err != nil {
+				// only reachable with an invalid HRP, which WithHRP rejects
+				panic(err)
+			}
+			return address
+		},
+		Cosmos:     &params,
+		privateKey: plainHexPrivateKey,
+	}
+}
+
+// WithHRP returns a copy of a Cosmos SDK chain that encodes addresses with
+// another bech32 prefix, for chains without a preset
+func (c *Chain) WithHRP(hrp string) (*Chain, error) {
+	if c.Cosmos == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotCosmosChain, c.Name)
+	}
+	if err := validateHRP(hrp); err != nil {
+		return nil, err
+	}
+	params := *c.Cosmos
+	params.HRP = hrp
+	return NewCosmosChain(c.Name, c.CoinType, c.DefaultPath, params), nil
+}
+
+// CosmosAddress returns the bech32 account address of pub with prefix hrp:
+// RIPEMD160(SHA256(compressed pubkey)), or the 20-byte Ethereum address for
+// eth_secp256k1 keys
+func CosmosAddress(pub *btcec.PublicKey, hrp string, ethSecp256k1 bool) (string, error) {
+	var data []byte
+	if ethSecp256k1 {
+		data = crypto.PubkeyToAddress(*pub.ToECDSA()).Bytes()
+	} else {
+		data = btcutil.Hash160(pub.SerializeCompressed())
+	}
+	words, err := bech32.ConvertBits(data, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	return bech32.Encode(hrp, words)
+}
+
+// DecodeCosmosAddress returns the prefix and 20 address bytes of a bech32
+// account address
+func DecodeCosmosAddress(address string) (string, []byte, error) {
+	hrp, words, err := bech32.Decode(address)
+	if err != nil {
+		return "", nil, err
+	}
+	data, err := bech32.ConvertBits(words, 5, 8, false)
+	if err != nil {
+		return "", nil, err
+	}
+	return hrp, data, nil
+}
+
+func validateHRP(hrp string) error {
+	if hrp == "" || len(hrp) > 83 {
+		return fmt.Errorf("invalid bech32 prefix %q", hrp)
+	}
+	for _, c := range hrp {
+		if c < 33 || c > 126 || (c >= 'A' && c <= 'Z') {
+			return fmt.Errorf("invalid bech32 prefix %q", hrp)
+		}
+	}
+	return nil
+}
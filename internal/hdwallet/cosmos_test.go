@@ -0,0 +1,91 @@
+package hdwallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+func TestCosmosAddress(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+	// cosmjs Secp256k1HdWallet reference account
+	atom, err := ChainPathFromMnemonic(secretString(t, mnemonic), "", CosmosHub, MainNet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer atom.Destroy()
+	if want := "cosmos19rl4cm2hmr8afy4kldpxz3fka4jguq0auqdal4"; atom.Address != want {
+		t.Errorf("Cosmos Hub address = %s, want %s", atom.Address, want)
+	}
+
+	// Same key, Osmosis prefix
+	osmo, err := ChainPathFromMnemonic(secretString(t, mnemonic), "", Osmosis, MainNet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer osmo.Destroy()
+	_, atomData, err := DecodeCosmosAddress(atom.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hrp, osmoData, err := DecodeCosmosAddress(osmo.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hrp != "osmo" || !bytes.Equal(osmoData, atomData) {
+		t.Errorf("Osmosis address %s does not share the Cosmos Hub key hash", osmo.Address)
+	}
+
+	// Custom prefix
+	juno, err := CosmosHub.WithHRP("juno")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hrp, data, _ := DecodeCosmosAddress(juno.Address(mustPublicKey(t, atom.PublicKey))); hrp != "juno" || !bytes.Equal(data, atomData) {
+		t.Errorf("WithHRP(juno) gave prefix %q", hrp)
+	}
+	if _, err := CosmosHub.WithHRP("Juno"); err == nil {
+		t.Error("Expected an upper case prefix to be rejected")
+	}
+	if _, err := Tron.WithHRP("juno"); !errors.Is(err, ErrNotCosmosChain) {
+		t.Errorf("Tron.WithHRP error = %v, want ErrNotCosmosChain", err)
+	}
+}
+
+func TestEthermintAddress(t *testing.T) {
+	mnemonic := "test test test test test test test test test test test junk"
+	evmos, err := ChainPathFromMnemonic(secretString(t, mnemonic), "", Evmos, MainNet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer evmos.Destroy()
+
+	// eth_secp256k1 addresses are the Ethereum address bytes
+	hrp, data, err := DecodeCosmosAddress(evmos.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := commonAddress(t, "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"); hrp != "evmos" || !bytes.Equal(data, want.Bytes()) {
+		t.Errorf("Evmos address %s decodes to %s %x, want %s", evmos.Address, hrp, data, want)
+	}
+	if string(evmos.PrivateKey.Bytes()) != "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80" {
+		t.Errorf("Evmos private key = %s", evmos.PrivateKey.Bytes())
+	}
+}
+
+func mustPublicKey(t *testing.T, compressedHex string) *btcec.PublicKey {
+	t.Helper()
+	raw, err := hex.DecodeString(compressedHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := btcec.ParsePubKey(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pub
+}
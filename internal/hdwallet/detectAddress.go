@@ -0,0 +1,486 @@
+package hdwallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil/base58"
+	"github.com/btcsuite/btcd/btcutil/bech32"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// Account is an account-model address (Ethereum, Tron, Cosmos)
+	Account AddressType = "account"
+	// P2TR is a SegWit version 1 (Taproot) output
+	P2TR AddressType = "p2tr"
+)
+
+// AddressInfo describes an address recognised by DetectAddress
+type AddressInfo struct {
+	Address string
+	// Chains the address is valid on, most likely first. Legacy "1" and "3"
+	// addresses, for example, are valid on both Bitcoin and Bitcoin Cash.
+	Chains   []string
+	Network  string // mainnet, testnet or regtest
+	Type     AddressType
+	Encoding string // EIP-55, hex, base58check, bech32, bech32m or CashAddr
+	Payload  []byte // key hash, script hash or witness program
+	// Forms are equivalent encodings of the same address
+	Forms    []AddressForm
+	Warnings []string
+}
+
+// AddressForm is one encoding of an address
+type AddressForm struct {
+	Name    string
+	Address string
+}
+
+// ValidOn reports whether the address is valid on the named chain
+func (a *AddressInfo) ValidOn(chain string) bool {
+	for _, c := range a.Chains {
+		if strings.EqualFold(c, chain) {
+			return true
+		}
+	}
+	return false
+}
+
+// Form returns the address in the named form
+func (a *AddressInfo) Form(name string) (string, bool) {
+	for _, f := range a.Forms {
+		if strings.EqualFold(f.Name, name) {
+			return f.Address, true
+		}
+	}
+	return "", false
+}
+
+// ChecksumError reports a checksum mismatch, with the 1-based positions of
+// the characters that may be wrong when they can be located
+type ChecksumError struct {
+	Encoding  string
+	Positions []int
+}
+
+func (e *ChecksumError) Error() string {
+	positions := make([]string, len(e.Positions))
+	for i, p := range e.Positions {
+		positions[i] = strconv.Itoa(p)
+	}
+	switch {
+	case e.Encoding == "EIP-55" && len(e.Positions) == 0:
+		return "invalid EIP-55 checksum (typo or wrong case)"
+	case e.Encoding == "EIP-55" && len(e.Positions) == 1:
+		return fmt.Sprintf("invalid EIP-55 checksum: wrong letter case at position %s", positions[0])
+	case e.Encoding == "EIP-55":
+		return fmt.Sprintf("invalid EIP-55 checksum: wrong letter case at positions %s", strings.Join(positions, ", "))
+	case len(e.Positions) == 1:
+		return fmt.Sprintf("invalid %s checksum: probably a typo at position %s", e.Encoding, positions[0])
+	case len(e.Positions) > 1:
+		return fmt.Sprintf("invalid %s checksum: one typo at position %s would explain it", e.Encoding, strings.Join(positions, " or "))
+	}
+	return fmt.Sprintf("invalid %s checksum: more than one character is wrong", e.Encoding)
+}
+
+func (e *ChecksumError) Unwrap() error {
+	return ErrInvalidAddress
+}
+
+// maxCaseErrors is the most letter-case mismatches an EIP-55 checksum error
+// is blamed on. The checksum is recomputed from the address as typed, so
+// after a mistyped digit each letter mismatches with probability 1/2 and
+// the positions would point at the wrong characters.
+const maxCaseErrors = 2
+
+// cashAddrNetworks maps the CashAddr prefixes of Bitcoin Cash to networks
+var cashAddrNetworks = map[string]string{
+	"bitcoincash": "mainnet",
+	"bchtest":     "testnet",
+	"bchreg":      "regtest",
+}
+
+// segwitHRPs maps the bech32 prefixes of SegWit chains to chain and network
+var segwitHRPs = map[string][2]string{
+	"bc":   {"bitcoin", "mainnet"},
+	"tb":   {"bitcoin", "testnet"},
+	"bcrt": {"bitcoin", "regtest"},
+	"ltc":  {"litecoin", "mainnet"},
+	"tltc": {"litecoin", "testnet"},
+	"rltc": {"litecoin", "regtest"},
+}
+
+// base58Version is a chain and address type using a base58check version byte
+type base58Version struct {
+	chain   string
+	network string
+	t       AddressType
+}
+
+// base58Versions lists the chains of every known version byte, Bitcoin first
+func base58Versions() map[byte][]base58Version {
+	versions := map[byte][]base58Version{
+		0x00:              {{"bitcoin", "mainnet", P2PKH}},
+		0x05:              {{"bitcoin", "mainnet", P2SH}},
+		0x6f:              {{"bitcoin", "testnet", P2PKH}},
+		0xc4:              {{"bitcoin", "testnet", P2SH}},
+		TronAddressPrefix: {{Tron.Name, "mainnet", Account}},
+	}
+	for _, c := range Chains {
+		if c.UTXO == nil {
+			continue
+		}
+		versions[c.UTXO.PubKeyHashAddrID] = append(versions[c.UTXO.PubKeyHashAddrID], base58Version{c.Name, "mainnet", P2PKH})
+		for _, v := range append([]byte{c.UTXO.ScriptHashAddrID}, c.UTXO.LegacyScriptHashAddrIDs...) {
+			versions[v] = append(versions[v], base58Version{c.Name, "mainnet", P2SH})
+		}
+	}
+	return versions
+}
+
+// DetectAddress recognises an address of any supported chain offline: EIP-55
+// Ethereum, base58check and bech32/bech32m Bitcoin-family, CashAddr, Tron and
+// Cosmos SDK bech32. Checksum failures are reported as *ChecksumError with
+// the likely positions of a single typo.
+func DetectAddress(address string) (*AddressInfo, error) {
+	address = strings.TrimSpace(address)
+	switch {
+	case address == "":
+		return nil, fmt.Errorf("%w: empty", ErrInvalidAddress)
+	case strings.HasPrefix(address, "0x") || strings.HasPrefix(address, "0X"):
+		return detectEthereum(address)
+	case strings.Contains(address, ":"):
+		return detectCashAddr(address, "")
+	case len(address) == 42 && strings.HasPrefix(address, "41") && isHex(address):
+		return detectTronHex(address)
+	case len(address) == 40 && isHex(address):
+		info, err := detectEthereum("0x" + address)
+		if err == nil {
+			info.Address = address
+			info.Warnings = append(info.Warnings, "missing 0x prefix")
+		}
+		return info, err
+	}
+
+	if one := strings.LastIndexByte(address, '1'); one > 0 {
+		if _, known := segwitHRPs[strings.ToLower(address[:one])]; known {
+			return detectBech32(address, strings.ToLower(address[:one]))
+		}
+	}
+	if hrp, ok := bech32Shaped(address); ok {
+		if _, _, err := base58.CheckDecode(address); err != nil {
+			return detectBech32(address, hrp)
+		}
+	}
+	if len(address) == 42 && strings.ContainsRune("qpQP", rune(address[0])) && inCharset(strings.ToLower(address), cashAddrCharset) {
+		return detectCashAddr(address, BitcoinCash.UTXO.CashAddrPrefix)
+	}
+	return detectBase58(address)
+}
+
+func detectEthereum(address string) (*AddressInfo, error) {
+	body := address[2:]
+	if len(body) != 40 {
+		return nil, fmt.Errorf("%w: Ethereum addresses have 40 hex digits, got %d", ErrInvalidAddress, len(body))
+	}
+	if i := strings.IndexFunc(body, func(r rune) bool { return !isHexDigit(r) }); i >= 0 {
+		return nil, fmt.Errorf("%w: invalid hex character %q at position %d", ErrInvalidAddress, body[i], i+3)
+	}
+
+	checksummed := common.HexToAddress(address)
+	info := &AddressInfo{
+		Address:  address,
+		Chains:   []string{Ethereum.Name},
+		Network:  "mainnet",
+		Type:     Account,
+		Encoding: "EIP-55",
+		Payload:  checksummed.Bytes(),
+		Forms: []AddressForm{
+			{"checksummed", checksummed.Hex()},
+			{"lowercase", strings.ToLower(checksummed.Hex())},
+		},
+	}
+	if strings.ToLower(body) == body || strings.ToUpper(body) == body {
+		info.Encoding = "hex"
+		info.Warnings = append(info.Warnings, "no EIP-55 checksum: a mistyped digit cannot be detected")
+		return info, nil
+	}
+
+	var wrong []int
+	want := checksummed.Hex()[2:]
+	for i := range body {
+		if body[i] != want[i] {
+			wrong = append(wrong, i+3)
+		}
+	}
+	if len(wrong) > maxCaseErrors {
+		return nil, &ChecksumError{Encoding: "EIP-55"}
+	}
+	if len(wrong) > 0 {
+		return nil, &ChecksumError{Encoding: "EIP-55", Positions: wrong}
+	}
+	return info, nil
+}
+
+func detectTronHex(address string) (*AddressInfo, error) {
+	raw, _ := hex.DecodeString(address[2:])
+	addr := common.BytesToAddress(raw)
+	return &AddressInfo{
+		Address:  address,
+		Chains:   []string{Tron.Name},
+		Network:  "mainnet",
+		Type:     Account,
+		Encoding: "hex",
+		Payload:  raw,
+		Forms: []AddressForm{
+			{"base58", TronAddress(addr)},
+			{"hex", "41" + hex.EncodeToString(raw)},
+		},
+	}, nil
+}
+
+func detectBase58(address string) (*AddressInfo, error) {
+	if i := strings.IndexFunc(address, func(r rune) bool { return !strings.ContainsRune(base58Alphabet, r) }); i >= 0 {
+		return nil, fmt.Errorf("%w: %q at position %d is not a base58 character (0, O, I and l are excluded)", ErrInvalidAddress, address[i], i+1)
+	}
+	payload, version, err := base58.CheckDecode(address)
+	if err == base58.ErrChecksum {
+		return nil, &ChecksumError{Encoding: "base58check", Positions: locateTypo(address, base58Alphabet, 0, func(s string) bool {
+			_, _, err := base58.CheckDecode(s)
+			return err == nil
+		})}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAddress, err)
+	}
+	if len(payload) != 20 {
+		return nil, fmt.Errorf("%w: %d-byte payload, want 20", ErrInvalidAddress, len(payload))
+	}
+	candidates, ok := base58Versions()[version]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown version byte 0x%02x", ErrInvalidAddress, version)
+	}
+
+	info := &AddressInfo{
+		Address:  address,
+		Network:  candidates[0].network,
+		Type:     candidates[0].t,
+		Encoding: "base58check",
+		Payload:  payload,
+	}
+	for _, c := range candidates {
+		info.Chains = append(info.Chains, c.chain)
+	}
+	switch {
+	case version == TronAddressPrefix:
+		info.Forms = []AddressForm{
+			{"base58", address},
+			{"hex", "41" + hex.EncodeToString(payload)},
+		}
+	case info.ValidOn(BitcoinCash.Name):
+		info.Forms = []AddressForm{{"legacy", address}}
+		if cashAddr, err := EncodeCashAddr(BitcoinCash.UTXO.CashAddrPrefix, info.Type, payload); err == nil {
+			info.Forms = append(info.Forms, AddressForm{"cashaddr", cashAddr})
+		}
+		if info.ValidOn(Litecoin.Name) {
+			info.Forms = append(info.Forms, AddressForm{"litecoin", base58.CheckEncode(payload, Litecoin.UTXO.ScriptHashAddrID)})
+		}
+	}
+	return info, nil
+}
+
+func detectCashAddr(address, defaultPrefix string) (*AddressInfo, error) {
+	if given, _, found := strings.Cut(address, ":"); found {
+		if _, known := cashAddrNetworks[strings.ToLower(given)]; !known {
+			return nil, fmt.Errorf("%w: unsupported CashAddr network %q", ErrInvalidAddress, given)
+		}
+	}
+	prefix, t, hash, err := DecodeCashAddr(address, defaultPrefix)
+	if err != nil {
+		if !errors.Is(err, errCashAddrChecksum) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidAddress, err)
+		}
+		// positions are counted in the address as given
+		start := strings.LastIndexByte(address, ':') + 1
+		lower := strings.ToLower(address)
+		return nil, &ChecksumError{Encoding: "CashAddr", Positions: locateTypo(lower, cashAddrCharset, start, func(s string) bool {
+			_, _, _, err := DecodeCashAddr(s, defaultPrefix)
+			return err == nil
+		})}
+	}
+
+	info := &AddressInfo{
+		Address:  address,
+		Chains:   []string{BitcoinCash.Name},
+		Network:  cashAddrNetworks[prefix],
+		Type:     t,
+		Encoding: "CashAddr",
+		Payload:  hash,
+	}
+	version := BitcoinCash.UTXO.PubKeyHashAddrID
+	if t == P2SH {
+		version = BitcoinCash.UTXO.ScriptHashAddrID
+	}
+	if info.Network != "mainnet" {
+		version = map[AddressType]byte{P2PKH: 0x6f, P2SH: 0xc4}[t]
+	}
+	cashAddr, _ := EncodeCashAddr(prefix, t, hash)
+	info.Forms = []AddressForm{
+		{"cashaddr", cashAddr},
+		{"cashaddr-short", cashAddr[len(prefix)+1:]},
+		{"legacy", base58.CheckEncode(hash, version)},
+	}
+	return info, nil
+}
+
+func detectBech32(address, hrp string) (*AddressInfo, error) {
+	start := len(hrp) + 1
+	if i := strings.IndexFunc(strings.ToLower(address[start:]), func(r rune) bool { return !strings.ContainsRune(cashAddrCharset, r) }); i >= 0 {
+		return nil, fmt.Errorf("%w: %q at position %d is not a bech32 character (1, b, i and o are excluded)", ErrInvalidAddress, address[start+i], start+i+1)
+	}
+	_, data, version, err := bech32.DecodeNoLimitWithVersion(address)
+	if _, ok := err.(bech32.ErrInvalidChecksum); ok {
+		// the library error includes a valid checksum for the mistyped
+		// payload; never show it
+		return nil, &ChecksumError{Encoding: "bech32", Positions: locateTypo(strings.ToLower(address), cashAddrCharset, start, func(s string) bool {
+			_, _, err := bech32.DecodeNoLimit(s)
+			return err == nil
+		})}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAddress, err)
+	}
+	encoding := map[bech32.Version]string{bech32.Version0: "bech32", bech32.VersionM: "bech32m"}[version]
+
+	if chain, ok := segwitHRPs[hrp]; ok {
+		return detectSegWit(address, chain, data, encoding)
+	}
+
+	payload, err := bech32.ConvertBits(data, 5, 8, false)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAddress, err)
+	}
+	if encoding != "bech32" || (len(payload) != 20 && len(payload) != 32) {
+		return nil, fmt.Errorf("%w: unknown bech32 prefix %q", ErrInvalidAddress, hrp)
+	}
+	info := &AddressInfo{
+		Address:  address,
+		Chains:   []string{"cosmos-sdk"},
+		Network:  "mainnet",
+		Type:     Account,
+		Encoding: encoding,
+		Payload:  payload,
+		Forms:    []AddressForm{{"bech32", strings.ToLower(address)}},
+	}
+	for _, c := range Chains {
+		if c.Cosmos != nil && c.Cosmos.HRP == hrp {
+			info.Chains = []string{c.Name}
+			if c.Cosmos.EthSecp256k1 && len(payload) == common.AddressLength {
+				info.Forms = append(info.Forms, AddressForm{"ethereum", common.BytesToAddress(payload).Hex()})
+			}
+		}
+	}
+	info.Forms = append(info.Forms, AddressForm{"hex", strings.ToUpper(hex.EncodeToString(payload))})
+	return info, nil
+}
+
+func detectSegWit(address string, chain [2]string, data []byte, encoding string) (*AddressInfo, error) {
+	if len(data) == 0 || data[0] > 16 {
+		return nil, fmt.Errorf("%w: invalid witness version", ErrInvalidAddress)
+	}
+	witnessVersion := data[0]
+	// BIP350: version 0 uses bech32, later versions bech32m
+	if (witnessVersion == 0) != (encoding == "bech32") {
+		return nil, fmt.Errorf("%w: witness version %d must not use %s", ErrInvalidAddress, witnessVersion, encoding)
+	}
+	program, err := bech32.ConvertBits(data[1:], 5, 8, false)
+	if err != nil || len(program) < 2 || len(program) > 40 {
+		return nil, fmt.Errorf("%w: invalid witness program", ErrInvalidAddress)
+	}
+
+	var t AddressType
+	switch {
+	case witnessVersion == 0 && len(program) == 20:
+		t = P2WPKH
+	case witnessVersion == 0 && len(program) == 32:
+		t = P2WSH
+	case witnessVersion == 0:
+		return nil, fmt.Errorf("%w: %d-byte version 0 witness program", ErrInvalidAddress, len(program))
+	case witnessVersion == 1 && len(program) == 32:
+		t = P2TR
+	default:
+		t = AddressType(fmt.Sprintf("witness-v%d", witnessVersion))
+	}
+
+	info := &AddressInfo{
+		Address:  address,
+		Chains:   []string{chain[0]},
+		Network:  chain[1],
+		Type:     t,
+		Encoding: encoding,
+		Payload:  program,
+		Forms: []AddressForm{
+			{"lowercase", strings.ToLower(address)},
+			// QR codes encode upper case more compactly
+			{"uppercase", strings.ToUpper(address)},
+		},
+	}
+	return info, nil
+}
+
+// locateTypo returns the 1-based positions at or after start where replacing
+// a single character from alphabet makes valid accept the string
+func locateTypo(s, alphabet string, start int, valid func(string) bool) []int {
+	var positions []int
+	b := []byte(s)
+	for i := start; i < len(b); i++ {
+		orig := b[i]
+		for j := 0; j < len(alphabet); j++ {
+			if alphabet[j] == orig {
+				continue
+			}
+			b[i] = alphabet[j]
+			if valid(string(b)) {
+				positions = append(positions, i+1)
+				break
+			}
+		}
+		b[i] = orig
+	}
+	return positions
+}
+
+// bech32Shaped reports whether s has the shape of a bech32 string and
+// returns its lower case prefix
+func bech32Shaped(s string) (string, bool) {
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", false
+	}
+	s = strings.ToLower(s)
+	one := strings.LastIndexByte(s, '1')
+	if one < 1 || one+7 > len(s) || !inCharset(s[one+1:], cashAddrCharset) {
+		return "", false
+	}
+	return s[:one], true
+}
+
+func inCharset(s, charset string) bool {
+	for _, r := range s {
+		if !strings.ContainsRune(charset, r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isHex(s string) bool {
+	return strings.IndexFunc(s, func(r rune) bool { return !isHexDigit(r) }) < 0
+}
+
+func isHexDigit(r rune) bool {
+	return (r >= '0' && r <= '9') || (r >= 'a' && r <= 'f') || (r >= 'A' && r <= 'F')
+}
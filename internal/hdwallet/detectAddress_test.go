@@ -0,0 +1,148 @@
+package hdwallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDetectAddress(t *testing.T) {
+	tests := []struct {
+		address  string
+		chains   []string
+		network  string
+		t        AddressType
+		encoding string
+		forms    map[string]string
+	}{
+		{"0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266", []string{"ethereum"}, "mainnet", Account, "EIP-55",
+			map[string]string{"lowercase": "0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266"}},
+		{"0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266", []string{"ethereum"}, "mainnet", Account, "hex",
+			map[string]string{"checksummed": "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"}},
+		{"1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu", []string{"bitcoin", "bitcoincash"}, "mainnet", P2PKH, "base58check",
+			map[string]string{"cashaddr": "bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a"}},
+		{"3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", []string{"bitcoin", "litecoin", "bitcoincash"}, "mainnet", P2SH, "base58check", nil},
+		{"bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a", []string{"bitcoincash"}, "mainnet", P2PKH, "CashAddr",
+			map[string]string{"legacy": "1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu", "cashaddr-short": "qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a"}},
+		{"qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a", []string{"bitcoincash"}, "mainnet", P2PKH, "CashAddr", nil},
+		{"bchtest:pr6m7j9njldwwzlg9v7v53unlr4jkmx6eyvwc0uz5t", []string{"bitcoincash"}, "testnet", P2SH, "CashAddr", nil},
+		// BIP173 and BIP350 vectors
+		{"BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", []string{"bitcoin"}, "mainnet", P2WPKH, "bech32",
+			map[string]string{"lowercase": "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"}},
+		{"tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7", []string{"bitcoin"}, "testnet", P2WSH, "bech32", nil},
+		{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", []string{"bitcoin"}, "mainnet", P2TR, "bech32m", nil},
+		{"ltc1qjmxnz78nmc8nq77wuxh25n2es7rzm5c2rkk4wh", []string{"litecoin"}, "mainnet", P2WPKH, "bech32", nil},
+		{"M7wtsL7wSHDBJVMWWhtQfTMSYYkyooAAXM", []string{"litecoin"}, "mainnet", P2SH, "base58check", nil},
+		{"DBus3bamQjgJULBJtYXpEzDWQRwF5iwxgC", []string{"dogecoin"}, "mainnet", P2PKH, "base58check", nil},
+		{"XoJA8qE3N2Y3jMLEtZ3vcN42qseZ8LvFf5", []string{"dash"}, "mainnet", P2PKH, "base58check", nil},
+		{"TNPeeaaFB7K9cmo4uQpcU32zGK8G1NYqeL", []string{"tron"}, "mainnet", Account, "base58check",
+			map[string]string{"hex": "418840e6c55b9ada326d211d818c34a994aeced808"}},
+		{"418840e6c55b9ada326d211d818c34a994aeced808", []string{"tron"}, "mainnet", Account, "hex",
+			map[string]string{"base58": "TNPeeaaFB7K9cmo4uQpcU32zGK8G1NYqeL"}},
+		{"cosmos19rl4cm2hmr8afy4kldpxz3fka4jguq0auqdal4", []string{"cosmos"}, "mainnet", Account, "bech32", nil},
+		{"evmos17w0adeg64ky0daxwd2ugyuneellmjgnxpu2u3g", []string{"evmos"}, "mainnet", Account, "bech32",
+			map[string]string{"ethereum": "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"}},
+		{"juno15428vq2uzwhm3taey9sr9x5vm6tk78ew0xkyf9", []string{"cosmos-sdk"}, "mainnet", Account, "bech32", nil},
+	}
+	for _, test := range tests {
+		info, err := DetectAddress(test.address)
+		if err != nil {
+			t.Errorf("DetectAddress(%s): %v", test.address, err)
+			continue
+		}
+		if !reflect.DeepEqual(info.Chains, test.chains) || info.Network != test.network || info.Type != test.t || info.Encoding != test.encoding {
+			t.Errorf("DetectAddress(%s) = %v %s %s %s, want %v %s %s %s", test.address,
+				info.Chains, info.Network, info.Type, info.Encoding, test.chains, test.network, test.t, test.encoding)
+		}
+		for name, want := range test.forms {
+			if got, _ := info.Form(name); got != want {
+				t.Errorf("DetectAddress(%s) %s form = %q, want %q", test.address, name, got, want)
+			}
+		}
+	}
+}
+
+func TestDetectAddressErrors(t *testing.T) {
+	tests := []struct {
+		address   string
+		positions []int // nil: not a checksum error
+	}{
+		// wrong case of the 'F' at position 6 and the 'd' at position 14
+		{"0xf39fd6e51aaD88F6F4ce6aB8827279cffFb92266", []int{6, 14}},
+		{"1BpEi6DfDAUFd7GtittLSdBeYJvcoaVgga", []int{34}},
+		{"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5", []int{42}},
+		{"bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx67", []int{54}},
+		{"bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdxba", nil}, // b is not in the charset
+		{"ecash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a", nil},       // not a Bitcoin Cash network
+		{"0xf39Fd6e51aad88F6F4ce6aB8827279cffFb9226", nil},              // 39 digits
+		{"0xf39Fd6e51aad88F6F4ce6aB8827279cffFb9226g", nil},             // not hex
+		{"1BpEi6DfDAUFd7GtittLSdBeYJvcoaVgOu", nil},                     // O is not base58
+		{"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3b4", nil},             // b is not bech32
+		// BIP350: a version 1 program must use bech32m
+		{"bc1pw508d6qejxtdg4y5r3zarvary0c5xw7kw508d6qejxtdg4y5r3zarvary0c5xw7k7grplx", nil},
+	}
+	for _, test := range tests {
+		_, err := DetectAddress(test.address)
+		if !errors.Is(err, ErrInvalidAddress) {
+			t.Errorf("DetectAddress(%s) error = %v, want ErrInvalidAddress", test.address, err)
+			continue
+		}
+		var checksumErr *ChecksumError
+		if errors.As(err, &checksumErr) != (test.positions != nil) {
+			t.Errorf("DetectAddress(%s) error = %v, want checksum error %v", test.address, err, test.positions != nil)
+			continue
+		}
+		if test.positions != nil && !reflect.DeepEqual(checksumErr.Positions, test.positions) {
+			t.Errorf("DetectAddress(%s) positions = %v, want %v", test.address, checksumErr.Positions, test.positions)
+		}
+	}
+}
+
+func TestDetectAddress_EIP55Typo(t *testing.T) {
+	// a mistyped last digit: the checksum of the typo disagrees with many
+	// letters, none of which is wrong
+	address := "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92267"
+	_, err := DetectAddress(address)
+	var checksumErr *ChecksumError
+	if !errors.As(err, &checksumErr) {
+		t.Fatalf("DetectAddress(%s) error = %v, want a checksum error", address, err)
+	}
+	if len(checksumErr.Positions) != 0 {
+		t.Errorf("DetectAddress(%s) positions = %v, want none", address, checksumErr.Positions)
+	}
+	if want := "invalid EIP-55 checksum (typo or wrong case)"; err.Error() != want {
+		t.Errorf("DetectAddress(%s) error = %q, want %q", address, err, want)
+	}
+}
+
+func TestDetectAddress_CashAddrNetworks(t *testing.T) {
+	// the same hash on each network; only the prefix and checksum differ
+	hash, _ := hex.DecodeString("76a04053bda0a88bda5177b86a15c3b29f559873")
+	for prefix, network := range map[string]string{"bitcoincash": "mainnet", "bchtest": "testnet", "bchreg": "regtest"} {
+		address, err := EncodeCashAddr(prefix, P2PKH, hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		info, err := DetectAddress(address)
+		if err != nil {
+			t.Fatalf("DetectAddress(%s) error = %v", address, err)
+		}
+		if info.Network != network {
+			t.Errorf("DetectAddress(%s) network = %s, want %s", address, info.Network, network)
+		}
+		legacy, _ := info.Form("legacy")
+		if wantTestnet := network != "mainnet"; (legacy[0] == 'm' || legacy[0] == 'n') != wantTestnet {
+			t.Errorf("DetectAddress(%s) legacy form = %s", address, legacy)
+		}
+	}
+
+	// a valid checksum under another prefix is not Bitcoin Cash
+	for _, prefix := range []string{"ecash", "simpleledger"} {
+		address, _ := EncodeCashAddr(prefix, P2PKH, hash)
+		if _, err := DetectAddress(address); !errors.Is(err, ErrInvalidAddress) || !strings.Contains(err.Error(), "unsupported CashAddr network") {
+			t.Errorf("DetectAddress(%s) error = %v, want unsupported network", address, err)
+		}
+	}
+}
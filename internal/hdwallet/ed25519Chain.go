@@ -0,0 +1,261 @@
+package hdwallet
+
+import (
+	"crypto/ed25519"
+	"crypto/sha3"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil/base58"
+	"github.com/btcsuite/btcd/btcutil/bech32"
+	"github.com/spark8899/gowallet/internal/security"
+	"golang.org/x/crypto/blake2b"
+)
+
+// ErrUnknownChain indicates the requested chain name is not supported
+var ErrUnknownChain = errors.New("unknown chain")
+
+// Ed25519Chain describes a chain whose accounts are ed25519 keys derived
+// with SLIP-10 from a BIP39 seed
+type Ed25519Chain struct {
+	Name        string
+	CoinType    uint32 // SLIP-44
+	DefaultPath string // the path used by the chain's reference wallets
+	// Address encodes a public key as an account address
+	Address func(ed25519.PublicKey) string
+	// privateKey encodes the 32-byte private key in the format the chain's
+	// wallets import
+	privateKey func(seed []byte, pub ed25519.PublicKey) (*security.SecretBuffer, error)
+}
+
+var (
+	// Solana accounts are the base58 public key; the private key is the
+	// base58 64-byte keypair imported by Phantom and Solflare
+	Solana = &Ed25519Chain{
+		Name:        "solana",
+		CoinType:    501,
+		DefaultPath: "m/44'/501'/0'/0'",
+		Address:     func(pub ed25519.PublicKey) string { return base58.Encode(pub) },
+		privateKey:  solanaPrivateKey,
+	}
+	// Aptos addresses are SHA3-256(pubkey || 0x00), the single-key ed25519
+	// authentication key
+	Aptos = &Ed25519Chain{
+		Name:        "aptos",
+		CoinType:    637,
+		DefaultPath: "m/44'/637'/0'/0'/0'",
+		Address: func(pub ed25519.PublicKey) string {
+			digest := sha3.Sum256(append(append([]byte(nil), pub...), 0x00))
+			return "0x" + hex.EncodeToString(digest[:])
+		},
+		privateKey: func(seed []byte, _ ed25519.PublicKey) (*security.SecretBuffer, error) {
+			return hexPrivateKey(seed)
+		},
+	}
+	// Sui addresses are BLAKE2b-256(0x00 || pubkey); private keys use the
+	// bech32 "suiprivkey" format of Sui wallets
+	Sui = &Ed25519Chain{
+		Name:        "sui",
+		CoinType:    784,
+		DefaultPath: "m/44'/784'/0'/0'/0'",
+		Address: func(pub ed25519.PublicKey) string {
+			digest := blake2b.Sum256(append([]byte{0x00}, pub...))
+			return "0x" + hex.EncodeToString(digest[:])
+		},
+		privateKey: suiPrivateKey,
+	}
+	// Stellar accounts and secret seeds are StrKeys (SEP-0005 paths)
+	Stellar = &Ed25519Chain{
+		Name:        "stellar",
+		CoinType:    148,
+		DefaultPath: "m/44'/148'/0'",
+		Address:     func(pub ed25519.PublicKey) string { return string(strKey(strKeyAccountID, pub)) },
+		privateKey: func(seed []byte, _ ed25519.PublicKey) (*security.SecretBuffer, error) {
+			encoded := strKey(strKeySeed, seed)
+			return security.NewSecretBufferFrom(encoded)
+		},
+	}
+)
+
+// Ed25519Chains lists every supported ed25519 chain in display order
+var Ed25519Chains = []*Ed25519Chain{Solana, Aptos, Sui, Stellar}
+
+// Ed25519ChainByName looks up an ed25519 chain by name (case-insensitive)
+func Ed25519ChainByName(name string) (*Ed25519Chain, error) {
+	for _, c := range Ed25519Chains {
+		if strings.EqualFold(c.Name, name) {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownChain, name)
+}
+
+func (c *Ed25519Chain) String() string {
+	return c.Name
+}
+
+// solanaPrivateKey encodes seed || pubkey. The base58 encoder only returns
+// a string, so a copy may remain on the heap until it is collected.
+func solanaPrivateKey(seed []byte, pub ed25519.PublicKey) (*security.SecretBuffer, error) {
+	keypair := append(append(make([]byte, 0, 64), seed...), pub...)
+	defer security.ZeroBytes(keypair)
+	return security.NewSecretBufferFromString(base58.Encode(keypair))
+}
+
+// suiPrivateKey encodes the ed25519 flag and seed as bech32, with the same
+// string caveat as solanaPrivateKey
+func suiPrivateKey(seed []byte, _ ed25519.PublicKey) (*security.SecretBuffer, error) {
+	data := append([]byte{0x00}, seed...)
+	defer security.ZeroBytes(data)
+	words, err := bech32.ConvertBits(data, 8, 5, true)
+	if err != nil {
+		return nil, err
+	}
+	defer security.ZeroBytes(words)
+	encoded, err := bech32.Encode("suiprivkey", words)
+	if err != nil {
+		return nil, err
+	}
+	return security.NewSecretBufferFromString(encoded)
+}
+
+// StrKey version bytes (the first base32 letter: G for accounts, S for seeds)
+const (
+	strKeyAccountID = 6 << 3
+	strKeySeed      = 18 << 3
+)
+
+var strKeyEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// strKey encodes version || payload || CRC16-XModem (little endian) in
+// base32. The intermediate buffer is wiped since seeds pass through it.
+func strKey(version byte, payload []byte) []byte {
+	raw := append([]byte{version}, payload...)
+	defer security.ZeroBytes(raw)
+	crc := crc16XModem(raw)
+	raw = append(raw, byte(crc), byte(crc>>8))
+
+	out := make([]byte, strKeyEncoding.EncodedLen(len(raw)))
+	strKeyEncoding.Encode(out, raw)
+	return out
+}
+
+func crc16XModem(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// Ed25519KeyInfo holds everything derived for one ed25519 account. The
+// private key lives in a SecretBuffer; call Destroy when done.
+type Ed25519KeyInfo struct {
+	Path       string
+	Chain      *Ed25519Chain
+	Address    string
+	PublicKey  string                 // hex
+	PrivateKey *security.SecretBuffer // in the chain's import format
+}
+
+// Destroy wipes the private key
+func (k *Ed25519KeyInfo) Destroy() {
+	k.PrivateKey.Destroy()
+}
+
+// String returns the "address:privateKey" form
+func (k *Ed25519KeyInfo) String() string {
+	var b strings.Builder
+	k.WriteTo(&b)
+	return b.String()
+}
+
+// WriteTo writes the "address:privateKey" form to w
+func (k *Ed25519KeyInfo) WriteTo(w io.Writer) (int64, error) {
+	return writeAll(w, []byte(k.Address), []byte(":"), k.PrivateKey.Bytes())
+}
+
+// WriteDetails writes a multi-line, human readable description to w
+func (k *Ed25519KeyInfo) WriteDetails(w io.Writer) (int64, error) {
+	return writeAll(w,
+		fmt.Appendf(nil, "Chain: %s\n", k.Chain.Name),
+		fmt.Appendf(nil, "Path: %s\n", k.Path),
+		fmt.Appendf(nil, "Address: %s\n", k.Address),
+		[]byte("Private Key: "), k.PrivateKey.Bytes(), []byte("\n"),
+		fmt.Appendf(nil, "Public Key: %s", k.PublicKey),
+	)
+}
+
+// Ed25519PathFromMnemonic derives the chain's account at pathStr (or its
+// default path if empty) from a BIP39 mnemonic
+func Ed25519PathFromMnemonic(mnemonic *security.SecretBuffer, pathStr string, chain *Ed25519Chain) (*Ed25519KeyInfo, error) {
+	if mnemonic.Len() == 0 {
+		return nil, errors.New("mnemonic is required")
+	}
+	entropyBytes, err := entropyFromMnemonic(mnemonic.Bytes())
+	if err != nil {
+		return nil, errors.New("mnemonic is invalid")
+	}
+	entropyBytes.Destroy()
+
+	seed, err := Bip39MnemonicToSeed(mnemonic, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate seed from mnemonic: %w", err)
+	}
+	defer seed.Destroy()
+	return deriveEd25519KeyInfo(seed.Bytes(), pathStr, chain)
+}
+
+// Ed25519PathFromSeed derives the chain's account at pathStr (or its
+// default path if empty) from a hex encoded BIP39 seed
+func Ed25519PathFromSeed(seedHex *security.SecretBuffer, pathStr string, chain *Ed25519Chain) (*Ed25519KeyInfo, error) {
+	seed, err := security.NewSecretBuffer(hex.DecodedLen(seedHex.Len()))
+	if err != nil {
+		return nil, err
+	}
+	defer seed.Destroy()
+	if _, err := hex.Decode(seed.Bytes(), seedHex.Bytes()); err != nil {
+		return nil, fmt.Errorf("seed str to bytes: %w", err)
+	}
+	return deriveEd25519KeyInfo(seed.Bytes(), pathStr, chain)
+}
+
+func deriveEd25519KeyInfo(seed []byte, pathStr string, chain *Ed25519Chain) (*Ed25519KeyInfo, error) {
+	if chain == nil {
+		chain = Solana
+	}
+	if pathStr == "" {
+		pathStr = chain.DefaultPath
+	}
+	privateKey, err := deriveEd25519(seed, pathStr)
+	if err != nil {
+		return nil, err
+	}
+	defer privateKey.Destroy()
+
+	expanded := ed25519.NewKeyFromSeed(privateKey.Bytes())
+	defer security.ZeroBytes(expanded)
+	pub := ed25519.PublicKey(expanded.Public().(ed25519.PublicKey))
+
+	info := &Ed25519KeyInfo{
+		Path:      pathStr,
+		Chain:     chain,
+		Address:   chain.Address(pub),
+		PublicKey: hex.EncodeToString(pub),
+	}
+	if info.PrivateKey, err = chain.privateKey(privateKey.Bytes(), pub); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
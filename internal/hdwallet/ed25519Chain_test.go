@@ -0,0 +1,120 @@
+package hdwallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEd25519PathFromMnemonic_Stellar(t *testing.T) {
+	// SEP-0005 test 1
+	mnemonic := "illness spike retreat truth genius clock brain pass fit cave bargain toe"
+	tests := []struct {
+		path, address, secret string
+	}{
+		{"m/44'/148'/0'", "GDRXE2BQUC3AZNPVFSCEZ76NJ3WWL25FYFK6RGZGIEKWE4SOOHSUJUJ6", "SBGWSG6BTNCKCOB3DIFBGCVMUPQFYPA2G4O34RMTB343OYPXU5DJDVMN"},
+		{"m/44'/148'/1'", "GBAW5XGWORWVFE2XTJYDTLDHXTY2Q2MO73HYCGB3XMFMQ562Q2W2GJQX", "SCEPFFWGAG5P2VX5DHIYK3XEMZYLTYWIPWYEKXFHSK25RVMIUNJ7CTIS"},
+	}
+	for _, tt := range tests {
+		info, err := Ed25519PathFromMnemonic(secretString(t, mnemonic), tt.path, Stellar)
+		if err != nil {
+			t.Fatalf("Ed25519PathFromMnemonic(%s) error: %v", tt.path, err)
+		}
+		if info.Address != tt.address {
+			t.Errorf("%s address = %s, want %s", tt.path, info.Address, tt.address)
+		}
+		if got := string(info.PrivateKey.Bytes()); got != tt.secret {
+			t.Errorf("%s secret = %s, want %s", tt.path, got, tt.secret)
+		}
+		info.Destroy()
+	}
+}
+
+func TestEd25519PathFromMnemonic_Solana(t *testing.T) {
+	// The first Phantom account of the BIP39 test mnemonic
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	info, err := Ed25519PathFromMnemonic(secretString(t, mnemonic), "m/44'/501'/0'/0'", Solana)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer info.Destroy()
+	if want := "HAgk14JpMQLgt6rVgv7cBQFJWFto5Dqxi472uT3DKpqk"; info.Address != want {
+		t.Errorf("Address = %s, want %s", info.Address, want)
+	}
+}
+
+func TestEd25519PathFromMnemonic_Chains(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	for _, chain := range Ed25519Chains {
+		t.Run(chain.Name, func(t *testing.T) {
+			info, err := Ed25519PathFromMnemonic(secretString(t, mnemonic), "", chain)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer info.Destroy()
+			if info.Path != chain.DefaultPath || info.Chain != chain {
+				t.Errorf("path %s, chain %s", info.Path, info.Chain)
+			}
+			if len(info.PublicKey) != 64 {
+				t.Errorf("public key %q", info.PublicKey)
+			}
+			if !strings.HasPrefix(info.String(), info.Address+":") {
+				t.Errorf("String() = %q", info.String())
+			}
+
+			// The seed route gives the same account
+			seed, err := Bip39MnemonicToSeed(secretString(t, mnemonic), "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer seed.Destroy()
+			fromSeed, err := Ed25519PathFromSeed(secretString(t, hex.EncodeToString(seed.Bytes())), "", chain)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer fromSeed.Destroy()
+			if fromSeed.String() != info.String() {
+				t.Errorf("seed route gave %s, want %s", fromSeed, info)
+			}
+		})
+	}
+}
+
+func TestEd25519Chain_Formats(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	checks := map[*Ed25519Chain]func(address, privateKey string) bool{
+		Solana: func(a, k string) bool { return len(a) >= 32 && len(a) <= 44 && len(k) >= 86 && len(k) <= 88 },
+		Aptos:  func(a, k string) bool { return len(a) == 66 && strings.HasPrefix(k, "0x") && len(k) == 66 },
+		Sui:    func(a, k string) bool { return len(a) == 66 && strings.HasPrefix(k, "suiprivkey1") },
+		Stellar: func(a, k string) bool {
+			return len(a) == 56 && a[0] == 'G' && len(k) == 56 && k[0] == 'S'
+		},
+	}
+	for chain, check := range checks {
+		info, err := Ed25519PathFromMnemonic(secretString(t, mnemonic), "", chain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !check(info.Address, string(info.PrivateKey.Bytes())) {
+			t.Errorf("%s: unexpected format %s", chain, info)
+		}
+		info.Destroy()
+	}
+}
+
+func TestEd25519PathFromMnemonic_Errors(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	if _, err := Ed25519PathFromMnemonic(secretString(t, mnemonic), "m/44'/501'/0'/0", Solana); !errors.Is(err, ErrNonHardenedPath) {
+		t.Errorf("non-hardened path: error = %v, want ErrNonHardenedPath", err)
+	}
+	if _, err := Ed25519PathFromMnemonic(secretString(t, "abandon abandon"), "", Solana); err == nil {
+		t.Error("invalid mnemonic: expected an error")
+	}
+	if _, err := Ed25519ChainByName("SOLANA"); err != nil {
+		t.Errorf("Ed25519ChainByName(SOLANA) error: %v", err)
+	}
+	if _, err := Ed25519ChainByName("cardano"); !errors.Is(err, ErrUnknownChain) {
+		t.Errorf("Ed25519ChainByName(cardano) error = %v, want ErrUnknownChain", err)
+	}
+}
@@ -0,0 +1,206 @@
+package hdwallet
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spark8899/gowallet/internal/security"
+)
+
+// KeyInfo holds everything derived for a single derivation path. The
+// private fields live in SecretBuffers; call Destroy when done.
+type KeyInfo struct {
+	Path       string
+	Network    *Network
+	Address    string                 // EVM address (EIP-55 checksummed)
+	PrivateKey *security.SecretBuffer // 0x-prefixed hex
+	PublicKey  string                 // compressed, hex
+	// XOnlyPublicKey is the BIP340 (Schnorr, Taproot) form of PublicKey:
+	// its 32-byte x coordinate, hex
+	XOnlyPublicKey string
+	// WIF is the compressed WIF for Network. The encoder only returns a
+	// string, so a copy may remain on the heap until it is collected.
+	WIF        *security.SecretBuffer
+	BTCAddress string // P2PKH address for Network
+	// TaprootAddress is the BIP86 key-path-only P2TR address for Network
+	TaprootAddress string
+	// ExtendedPrivateKey has the same caveat as WIF
+	ExtendedPrivateKey *security.SecretBuffer
+	ExtendedPublicKey  string
+	// MasterFingerprint identifies the seed in key origins such as
+	// [d34db33f/84'/0'/0']
+	MasterFingerprint uint32
+}
+
+// Destroy wipes the private fields
+func (k *KeyInfo) Destroy() {
+	k.PrivateKey.Destroy()
+	k.WIF.Destroy()
+	k.ExtendedPrivateKey.Destroy()
+}
+
+// String returns the classic "address:privateKey" form
+func (k *KeyInfo) String() string {
+	var b strings.Builder
+	k.WriteTo(&b)
+	return b.String()
+}
+
+// WriteTo writes the "address:privateKey" form to w without building an
+// intermediate string
+func (k *KeyInfo) WriteTo(w io.Writer) (int64, error) {
+	return writeAll(w, []byte(k.Address), []byte(":"), k.PrivateKey.Bytes())
+}
+
+// WriteWIFTo writes the "BTCAddress:WIF" form to w, the Bitcoin
+// counterpart of WriteTo
+func (k *KeyInfo) WriteWIFTo(w io.Writer) (int64, error) {
+	return writeAll(w, []byte(k.BTCAddress), []byte(":"), k.WIF.Bytes())
+}
+
+// Details returns a multi-line, human readable description of the key
+func (k *KeyInfo) Details() string {
+	var b strings.Builder
+	k.WriteDetails(&b)
+	return b.String()
+}
+
+// WriteDetails writes the Details form to w, copying the private fields
+// straight from their buffers
+func (k *KeyInfo) WriteDetails(w io.Writer) (int64, error) {
+	return writeAll(w,
+		fmt.Appendf(nil, "Network: %s (EVM chain ID %s)\n", k.Network.Name, k.Network.ChainID),
+		fmt.Appendf(nil, "Path: %s\n", k.Path),
+		fmt.Appendf(nil, "Address: %s\n", k.Address),
+		[]byte("Private Key: "), k.PrivateKey.Bytes(), []byte("\n"),
+		fmt.Appendf(nil, "Public Key: %s\n", k.PublicKey),
+		fmt.Appendf(nil, "X-Only Public Key: %s\n", k.XOnlyPublicKey),
+		[]byte("WIF: "), k.WIF.Bytes(), []byte("\n"),
+		fmt.Appendf(nil, "BTC Address: %s\n", k.BTCAddress),
+		fmt.Appendf(nil, "Taproot Address: %s\n", k.TaprootAddress),
+		[]byte("Extended Private Key: "), k.ExtendedPrivateKey.Bytes(), []byte("\n"),
+		fmt.Appendf(nil, "Extended Public Key: %s", k.ExtendedPublicKey),
+	)
+}
+
+func writeAll(w io.Writer, parts ...[]byte) (int64, error) {
+	var total int64
+	for _, p := range parts {
+		n, err := w.Write(p)
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// zeroExtendedKey wipes the key and chain code of an extended key. It is a
+// variable so tests can check that every key in a derivation is wiped.
+var zeroExtendedKey = func(k *hdkeychain.ExtendedKey) { k.Zero() }
+
+// deriveKeyInfo walks pathStr from the BIP32 master key of seed using the
+// version bytes and address encodings of net. Every intermediate extended
+// key and the final private scalar are wiped before returning, including on
+// errors; only the SecretBuffers in the result hold key material.
+func deriveKeyInfo(seed []byte, pathStr string, net *Network) (*KeyInfo, error) {
+	if net == nil {
+		net = MainNet
+	}
+
+	path, err := accounts.ParseDerivationPath(pathStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse derivation path: %w", err)
+	}
+
+	// Create master private key
+	key, err := hdkeychain.NewMaster(seed, net.Params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create master key: %w", err)
+	}
+	defer func() { zeroExtendedKey(key) }()
+
+	masterPub, err := key.ECPubKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get master public key: %w", err)
+	}
+	masterFingerprint := binary.BigEndian.Uint32(btcutil.Hash160(masterPub.SerializeCompressed())[:4])
+
+	// Derivation path
+	fixIssue172 := true
+	for _, n := range path {
+		var child *hdkeychain.ExtendedKey
+		if fixIssue172 && key.IsAffectedByIssue172() {
+			child, err = key.Derive(n)
+		} else {
+			child, err = key.DeriveNonStandard(n)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive key at path %d: %w", n, err)
+		}
+		zeroExtendedKey(key)
+		key = child
+	}
+
+	privateKey, err := key.ECPrivKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get EC private key: %w", err)
+	}
+	defer privateKey.Zero()
+	privateKeyECDSA := privateKey.ToECDSA()
+	defer security.ZeroPrivateKey(privateKeyECDSA)
+
+	wif, err := net.WIF(privateKey, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode WIF: %w", err)
+	}
+
+	publicKey, err := key.Neuter()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get extended public key: %w", err)
+	}
+
+	compressed := privateKey.PubKey().SerializeCompressed()
+	taproot, err := BitcoinChain(net).UTXO.Address(privateKey.PubKey(), P2TR)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode taproot address: %w", err)
+	}
+
+	info := &KeyInfo{
+		Path:              pathStr,
+		Network:           net,
+		Address:           crypto.PubkeyToAddress(privateKeyECDSA.PublicKey).Hex(),
+		PublicKey:         hex.EncodeToString(compressed),
+		XOnlyPublicKey:    hex.EncodeToString(compressed[1:]),
+		BTCAddress:        net.PubkeyToAddress(compressed),
+		TaprootAddress:    taproot,
+		ExtendedPublicKey: publicKey.String(),
+		MasterFingerprint: masterFingerprint,
+	}
+
+	var raw [32]byte
+	privateKey.Key.PutBytes(&raw)
+	defer security.ZeroBytes(raw[:])
+	if info.PrivateKey, err = security.NewSecretBuffer(2 + hex.EncodedLen(len(raw))); err != nil {
+		return nil, err
+	}
+	copy(info.PrivateKey.Bytes(), "0x")
+	hex.Encode(info.PrivateKey.Bytes()[2:], raw[:])
+
+	if info.WIF, err = security.NewSecretBufferFromString(wif); err != nil {
+		info.Destroy()
+		return nil, err
+	}
+	if info.ExtendedPrivateKey, err = security.NewSecretBufferFromString(key.String()); err != nil {
+		info.Destroy()
+		return nil, err
+	}
+	return info, nil
+}
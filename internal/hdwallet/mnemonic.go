@@ -2,13 +2,17 @@ package hdwallet
 
 import (
 	"bytes"
+	"crypto/rand"
 	"crypto/sha256"
+	"crypto/sha512"
 	"errors"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/spark8899/gowallet/internal/security"
 	"github.com/tyler-smith/go-bip39"
+	"golang.org/x/crypto/pbkdf2"
 )
 
 var wordList = strings.Split(alternatingWords, "\n")
@@ -21,31 +25,46 @@ func init() {
 	}
 }
 
-func Bip39GenMnemonic(size int) (string, error) {
-	entropyBytes, err := bip39.NewEntropy(size)
-	if err != nil {
-		return "", err
+// Bip39GenMnemonic generates a mnemonic from size bits of entropy read from
+// random. A nil random uses crypto/rand. The mnemonic is written straight
+// into a SecretBuffer and never exists as a Go string; the caller must
+// Destroy it.
+func Bip39GenMnemonic(size int, random io.Reader) (*security.SecretBuffer, error) {
+	if size%32 != 0 || size < 128 || size > 256 {
+		return nil, bip39.ErrEntropyLengthInvalid
+	}
+	if random == nil {
+		random = rand.Reader
+	}
+
+	entropyBytes := make([]byte, size/8)
+	// Clear entropy from memory after use
+	defer security.ZeroBytes(entropyBytes)
+	if _, err := io.ReadFull(random, entropyBytes); err != nil {
+		return nil, fmt.Errorf("failed to read entropy: %w", err)
 	}
 
 	// Validate entropy quality for security
 	if err := ValidateEntropy(entropyBytes); err != nil {
-		return "", fmt.Errorf("entropy validation failed: %w", err)
+		return nil, fmt.Errorf("entropy validation failed: %w", err)
 	}
 
 	// Generate mnemonic phrase
-	mnemonic, err := bip39.NewMnemonic(entropyBytes)
-
-	// Clear entropy from memory after use
-	defer security.ZeroBytes(entropyBytes)
-
-	return mnemonic, err
+	return mnemonicFromEntropy(entropyBytes)
 }
 
-func Bip39MnemonicToSeed(mnemonic string, password string) ([]byte, error) {
-	if !bip39.IsMnemonicValid(mnemonic) {
+// Bip39MnemonicToSeed validates mnemonic and derives the 64-byte BIP39 seed
+// (PBKDF2-HMAC-SHA512, 2048 rounds, salt "mnemonic"+password) into a new
+// SecretBuffer. The mnemonic is hashed exactly as given, like bip39.NewSeed.
+func Bip39MnemonicToSeed(mnemonic *security.SecretBuffer, password string) (*security.SecretBuffer, error) {
+	entropyBytes, err := entropyFromMnemonic(mnemonic.Bytes())
+	if err != nil {
 		return nil, errors.New("mnemonic not valid")
 	}
-	return bip39.NewSeed(mnemonic, password), nil
+	entropyBytes.Destroy()
+
+	seed := pbkdf2.Key(mnemonic.Bytes(), []byte("mnemonic"+password), 2048, 64, sha512.New)
+	return security.NewSecretBufferFrom(seed)
 }
 
 // DCR mnemonic and seed conversion is bidirectional and doesn't follow BIP39 standard
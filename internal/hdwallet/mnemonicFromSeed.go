@@ -2,31 +2,105 @@ package hdwallet
 
 import (
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"unicode"
 
-	"github.com/tyler-smith/go-bip32"
-	"github.com/tyler-smith/go-bip39"
+	"github.com/spark8899/gowallet/internal/security"
 )
 
+// ErrSeedNotReversible is returned by MnemonicFromSeed. A BIP39 seed is the
+// PBKDF2-HMAC-SHA512 output of the mnemonic and passphrase, so there is no
+// way back from a seed to the words that produced it.
+var ErrSeedNotReversible = errors.New("a BIP39 seed cannot be converted back to a mnemonic; " +
+	"the seed is a one-way PBKDF2 hash of the mnemonic and passphrase. " +
+	"Use entropyToMnemonic with the 16-32 byte entropy instead")
+
+// MnemonicFromSeed always fails with ErrSeedNotReversible.
+//
+// It used to encode the BIP32 master key derived from the seed as a mnemonic,
+// which does not round-trip with mnToSeed and silently produced an unrelated
+// wallet.
 func MnemonicFromSeed(seedStr string) (string, error) {
-	// to seed bytes
-	seed, err := hex.DecodeString(seedStr)
+	return "", ErrSeedNotReversible
+}
+
+// destroySecret destroys an intermediate SecretBuffer. It is a variable so
+// tests can check that the conversions below wipe everything they decode.
+var destroySecret = func(s *security.SecretBuffer) { s.Destroy() }
+
+// EntropyToMnemonic encodes 16, 20, 24, 28 or 32 bytes of hex entropy as a
+// BIP39 mnemonic of 12 to 24 words. The caller must Destroy the result.
+func EntropyToMnemonic(entropyHex *security.SecretBuffer) (*security.SecretBuffer, error) {
+	entropy, err := decodeHexSecret(entropyHex.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode entropy hex: %w", err)
+	}
+	defer destroySecret(entropy)
+	if err := validateEntropyBits(entropy.Len() * 8); err != nil {
+		return nil, err
+	}
+
+	mnemonic, err := mnemonicFromEntropy(entropy.Bytes())
 	if err != nil {
-		return "", fmt.Errorf("failed to decode seed string: %w", err)
+		return nil, fmt.Errorf("failed to create mnemonic: %w", err)
 	}
+	return mnemonic, nil
+}
 
-	// Convert seed to master private key
-	masterKey01, err := bip32.NewMasterKey(seed)
+// MnemonicToEntropy decodes a BIP39 mnemonic back to its entropy, verifying
+// the word list and checksum. The caller must Destroy the result.
+func MnemonicToEntropy(mnemonic *security.SecretBuffer) (*security.SecretBuffer, error) {
+	entropy, err := entropyFromMnemonic(mnemonic.Bytes())
 	if err != nil {
-		return "", fmt.Errorf("failed to create master key: %w", err)
+		return nil, fmt.Errorf("invalid mnemonic: %w", err)
 	}
+	return entropy, nil
+}
 
-	// Convert entropy to mnemonic
-	mnemonic, err := bip39.NewMnemonic(masterKey01.Key)
+// MnemonicToEntropyHex is MnemonicToEntropy with hex encoded output
+func MnemonicToEntropyHex(mnemonic *security.SecretBuffer) (*security.SecretBuffer, error) {
+	entropy, err := MnemonicToEntropy(mnemonic)
 	if err != nil {
-		return "", fmt.Errorf("failed to create mnemonic: %w", err)
+		return nil, err
 	}
+	defer destroySecret(entropy)
 
-	fmt.Println("Mnemonic:", mnemonic)
-	return mnemonic, nil
+	out, err := security.NewSecretBuffer(hex.EncodedLen(entropy.Len()))
+	if err != nil {
+		return nil, err
+	}
+	hex.Encode(out.Bytes(), entropy.Bytes())
+	return out, nil
+}
+
+// decodeHexSecret decodes hex (optionally 0x prefixed, whitespace ignored)
+// into a new SecretBuffer without making a string of it
+func decodeHexSecret(s []byte) (*security.SecretBuffer, error) {
+	digits, err := security.NewSecretBuffer(len(s))
+	if err != nil {
+		return nil, err
+	}
+	defer destroySecret(digits)
+	n := 0
+	for _, c := range s {
+		if !unicode.IsSpace(rune(c)) {
+			digits.Bytes()[n] = c
+			n++
+		}
+	}
+	hexDigits := digits.Bytes()[:n]
+	if n >= 2 && hexDigits[0] == '0' && (hexDigits[1] == 'x' || hexDigits[1] == 'X') {
+		hexDigits = hexDigits[2:]
+	}
+
+	out, err := security.NewSecretBuffer(hex.DecodedLen(len(hexDigits)))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := hex.Decode(out.Bytes(), hexDigits); err != nil {
+		out.Destroy()
+		return nil, err
+	}
+	return out, nil
 }
@@ -1,157 +1,216 @@
 package hdwallet
 
 import (
+	"bytes"
+	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"strings"
 	"testing"
+
+	"github.com/spark8899/gowallet/internal/security"
+	"github.com/tyler-smith/go-bip39"
 )
 
 func TestMnemonicFromSeed(t *testing.T) {
-	tests := []struct {
-		name        string
-		seedHex     string
-		expectError bool
-		errorMsg    string
-	}{
-		{
-			name:    "Valid 128-bit seed",
-			seedHex: "00112233445566778899aabbccddeeff",
-		},
-		{
-			name:    "Valid 256-bit seed",
-			seedHex: "00112233445566778899aabbccddeeff00112233445566778899aabbccddeeff",
-		},
-		{
-			name:        "Invalid hex characters",
-			seedHex:     "gghhiijj",
-			expectError: true,
-			errorMsg:    "failed to decode seed string",
-		},
-		{
-			name:        "Odd length hex string",
-			seedHex:     "00112233445",
-			expectError: true,
-			errorMsg:    "failed to decode seed string",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result, err := MnemonicFromSeed(tt.seedHex)
-
-			if tt.expectError {
-				if err == nil {
-					t.Errorf("Expected error containing '%s', got nil", tt.errorMsg)
-				} else if !strings.Contains(err.Error(), tt.errorMsg) {
-					t.Errorf("Expected error containing '%s', got '%s'", tt.errorMsg, err.Error())
-				}
-				return
-			}
-
-			if err != nil {
-				t.Errorf("Unexpected error: %v", err)
-				return
-			}
-
-			// Verify result is a non-empty string
-			if result == "" {
-				t.Error("Expected non-empty mnemonic result")
-			}
-
-			// Count words in the mnemonic
-			words := strings.Fields(result)
-			if len(words) < 12 {
-				t.Errorf("Expected at least 12 words, got %d", len(words))
-			}
-		})
+	// A seed produced by mnToSeed must not be turned into a different wallet
+	seed := hex.EncodeToString(bip39.NewSeed(strings.Repeat("abandon ", 11)+"about", ""))
+	for _, seedHex := range []string{seed, "00112233445566778899aabbccddeeff", "gghhiijj"} {
+		mnemonic, err := MnemonicFromSeed(seedHex)
+		if !errors.Is(err, ErrSeedNotReversible) {
+			t.Errorf("MnemonicFromSeed(%s) error = %v, want %v", seedHex, err, ErrSeedNotReversible)
+		}
+		if mnemonic != "" {
+			t.Errorf("MnemonicFromSeed(%s) = %q, want empty", seedHex, mnemonic)
+		}
 	}
 }
 
-func TestMnemonicFromSeed_Deterministic(t *testing.T) {
-	// Test that the same seed always produces the same mnemonic
-	seedHex := "00112233445566778899aabbccddeeff"
+// BIP39 reference vectors (Trezor python-mnemonic vectors.json)
+var entropyVectors = []struct {
+	entropy  string
+	mnemonic string
+}{
+	{
+		"00000000000000000000000000000000",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+	},
+	{
+		"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+		"legal winner thank year wave sausage worth useful legal winner thank yellow",
+	},
+	{
+		"80808080808080808080808080808080",
+		"letter advice cage absurd amount doctor acoustic avoid letter advice cage above",
+	},
+	{
+		"ffffffffffffffffffffffffffffffff",
+		"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong",
+	},
+	{
+		"9e885d952ad362caeb4efe34a8e91bd2",
+		"ozone drill grab fiber curtain grace pudding thank cruise elder eight picnic",
+	},
+	{
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon " +
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art",
+	},
+}
 
-	result1, err1 := MnemonicFromSeed(seedHex)
-	if err1 != nil {
-		t.Fatalf("First call failed: %v", err1)
+func TestEntropyToMnemonic(t *testing.T) {
+	for _, v := range entropyVectors {
+		got, err := EntropyToMnemonic(secretString(t, v.entropy))
+		if err != nil {
+			t.Fatalf("EntropyToMnemonic(%s) error: %v", v.entropy, err)
+		}
+		if string(got.Bytes()) != v.mnemonic {
+			t.Errorf("EntropyToMnemonic(%s) = %q, want %q", v.entropy, got.Bytes(), v.mnemonic)
+		}
+		got.Destroy()
 	}
 
-	result2, err2 := MnemonicFromSeed(seedHex)
-	if err2 != nil {
-		t.Fatalf("Second call failed: %v", err2)
+	for _, bad := range []string{"00112233", strings.Repeat("00", 33), "00112233445566778899aabbccddeeff00"} {
+		if _, err := EntropyToMnemonic(secretString(t, bad)); !errors.Is(err, ErrInvalidEntropyLength) {
+			t.Errorf("EntropyToMnemonic(%s) error = %v, want %v", bad, err, ErrInvalidEntropyLength)
+		}
 	}
-
-	if result1 != result2 {
-		t.Errorf("Results are not deterministic: '%s' != '%s'", result1, result2)
+	if _, err := EntropyToMnemonic(secretString(t, "gghhiijj")); err == nil {
+		t.Error("EntropyToMnemonic() expected error for invalid hex")
 	}
 }
 
-func TestMnemonicFromSeed_DifferentSeeds(t *testing.T) {
-	// Test that different seeds produce different mnemonics
-	seed1 := "00112233445566778899aabbccddeeff"
-	seed2 := "ffeeddccbbaa99887766554433221100"
-
-	result1, err1 := MnemonicFromSeed(seed1)
-	if err1 != nil {
-		t.Fatalf("Seed1 failed: %v", err1)
+func TestMnemonicToEntropy(t *testing.T) {
+	for _, v := range entropyVectors {
+		got, err := MnemonicToEntropyHex(secretString(t, v.mnemonic))
+		if err != nil {
+			t.Fatalf("MnemonicToEntropyHex(%q) error: %v", v.mnemonic, err)
+		}
+		if string(got.Bytes()) != v.entropy {
+			t.Errorf("MnemonicToEntropyHex(%q) = %s, want %s", v.mnemonic, got.Bytes(), v.entropy)
+		}
+		got.Destroy()
 	}
 
-	result2, err2 := MnemonicFromSeed(seed2)
-	if err2 != nil {
-		t.Fatalf("Seed2 failed: %v", err2)
+	// Extra whitespace is tolerated
+	if _, err := MnemonicToEntropy(secretString(t, "  legal winner thank year wave sausage\n worth useful legal winner thank yellow ")); err != nil {
+		t.Errorf("MnemonicToEntropy() with extra whitespace error: %v", err)
 	}
 
-	if result1 == result2 {
-		t.Error("Different seeds should produce different mnemonics")
+	tests := []struct {
+		name     string
+		mnemonic string
+		wantErr  error
+	}{
+		{"bad checksum", strings.Repeat("abandon ", 11) + "abandon", bip39.ErrChecksumIncorrect},
+		{"unknown word", strings.Repeat("abandon ", 11) + "xyzzy", nil},
+		{"wrong word count", "abandon abandon abandon", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := MnemonicToEntropy(secretString(t, tt.mnemonic))
+			if err == nil {
+				t.Fatal("MnemonicToEntropy() expected error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("MnemonicToEntropy() error = %v, want %v", err, tt.wantErr)
+			}
+		})
 	}
 }
 
-func TestMnemonicFromSeed_ValidBIP39(t *testing.T) {
-	// Generate a mnemonic from a seed and verify it's a valid BIP39 mnemonic
-	seedHex := "0123456789abcdef0123456789abcdef"
+func TestEntropyMnemonicRoundTrip(t *testing.T) {
+	for _, size := range []int{16, 20, 24, 28, 32} {
+		for i := 0; i < 200; i++ {
+			entropy := make([]byte, size)
+			if _, err := rand.Read(entropy); err != nil {
+				t.Fatal(err)
+			}
 
-	mnemonic, err := MnemonicFromSeed(seedHex)
-	if err != nil {
-		t.Fatalf("Failed to generate mnemonic: %v", err)
-	}
+			secret, err := EntropyToMnemonic(secretString(t, hex.EncodeToString(entropy)))
+			if err != nil {
+				t.Fatalf("EntropyToMnemonic(%x) error: %v", entropy, err)
+			}
+			mnemonic := string(secret.Bytes())
+			if words := len(strings.Fields(mnemonic)); words != size*3/4 {
+				t.Fatalf("EntropyToMnemonic(%x) gave %d words, want %d", entropy, words, size*3/4)
+			}
 
-	// The generated mnemonic should be convertible back to a seed
-	// (though not necessarily the original seed due to the way the function works)
-	seed, err := Bip39MnemonicToSeed(mnemonic, "")
-	if err != nil {
-		t.Errorf("Generated mnemonic is not valid BIP39: %v", err)
-	}
+			decoded, err := MnemonicToEntropy(secret)
+			if err != nil {
+				t.Fatalf("MnemonicToEntropy(%q) error: %v", mnemonic, err)
+			}
+			if !bytes.Equal(decoded.Bytes(), entropy) {
+				t.Fatalf("round trip %x -> %q -> %x", entropy, mnemonic, decoded.Bytes())
+			}
+			decoded.Destroy()
 
-	if len(seed) != 64 {
-		t.Errorf("Expected 64-byte seed, got %d bytes", len(seed))
+			// The mnemonic must lead to the same seed mnToSeed computes
+			seed, err := Bip39MnemonicToSeed(secret, "")
+			if err != nil {
+				t.Fatalf("Bip39MnemonicToSeed() error: %v", err)
+			}
+			if !bytes.Equal(seed.Bytes(), bip39.NewSeed(mnemonic, "")) {
+				t.Fatalf("seed mismatch for %q", mnemonic)
+			}
+			seed.Destroy()
+			secret.Destroy()
+		}
 	}
 }
 
-func TestMnemonicFromSeed_HexDecoding(t *testing.T) {
-	// Test with uppercase and lowercase hex
-	seedLower := "aabbccdd"
-	seedUpper := "AABBCCDD"
-
-	resultLower, errLower := MnemonicFromSeed(seedLower)
-	if errLower != nil {
-		t.Fatalf("Lowercase hex failed: %v", errLower)
-	}
+// captureDestroyed records every intermediate SecretBuffer passed to
+// destroySecret until the test ends
+func captureDestroyed(t *testing.T) *[]*security.SecretBuffer {
+	var destroyed []*security.SecretBuffer
+	orig := destroySecret
+	destroySecret = func(s *security.SecretBuffer) {
+		destroyed = append(destroyed, s)
+		orig(s)
+	}
+	t.Cleanup(func() { destroySecret = orig })
+	return &destroyed
+}
 
-	resultUpper, errUpper := MnemonicFromSeed(seedUpper)
-	if errUpper != nil {
-		t.Fatalf("Uppercase hex failed: %v", errUpper)
+func TestEntropyMnemonic_WipesIntermediates(t *testing.T) {
+	v := entropyVectors[1]
+	tests := []struct {
+		name string
+		run  func() (*security.SecretBuffer, error)
+		want int
+	}{
+		// the hex digits and the decoded entropy
+		{"EntropyToMnemonic", func() (*security.SecretBuffer, error) { return EntropyToMnemonic(secretString(t, "0x"+v.entropy)) }, 2},
+		// the decoded entropy
+		{"MnemonicToEntropyHex", func() (*security.SecretBuffer, error) { return MnemonicToEntropyHex(secretString(t, v.mnemonic)) }, 1},
+		// a failed decode leaves nothing behind
+		{"EntropyToMnemonic short", func() (*security.SecretBuffer, error) { return EntropyToMnemonic(secretString(t, "00112233")) }, 2},
 	}
-
-	if resultLower != resultUpper {
-		t.Error("Hex case should not affect result")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			destroyed := captureDestroyed(t)
+			out, err := tt.run()
+			if err == nil {
+				out.Destroy()
+			}
+			if len(*destroyed) != tt.want {
+				t.Fatalf("destroyed %d intermediate buffers, want %d", len(*destroyed), tt.want)
+			}
+			for i, s := range *destroyed {
+				if !s.Destroyed() {
+					t.Errorf("buffer %d not wiped", i)
+				}
+			}
+		})
 	}
 }
 
-// Benchmark for performance testing
-func BenchmarkMnemonicFromSeed(b *testing.B) {
-	seedHex := "00112233445566778899aabbccddeeff"
+func BenchmarkEntropyToMnemonic(b *testing.B) {
+	entropyHex := secretString(b, "00112233445566778899aabbccddeeff")
 	for i := 0; i < b.N; i++ {
-		_, _ = MnemonicFromSeed(seedHex)
+		if mnemonic, err := EntropyToMnemonic(entropyHex); err == nil {
+			mnemonic.Destroy()
+		}
 	}
 }
 
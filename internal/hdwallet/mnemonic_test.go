@@ -8,8 +8,20 @@ import (
 
 	"github.com/btcsuite/btcd/btcutil/base58"
 	dcrbase58 "github.com/decred/base58"
+	"github.com/spark8899/gowallet/internal/security"
 )
 
+// secretString wraps s in a SecretBuffer destroyed at the end of the test
+func secretString(t testing.TB, s string) *security.SecretBuffer {
+	t.Helper()
+	secret, err := security.NewSecretBufferFromString(s)
+	if err != nil {
+		t.Fatalf("NewSecretBufferFromString() error: %v", err)
+	}
+	t.Cleanup(secret.Destroy)
+	return secret
+}
+
 func TestGenMnemonicBip39(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -44,12 +56,13 @@ func TestGenMnemonicBip39(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		mnemonic, err := Bip39GenMnemonic(test.randSize)
+		mnemonic, err := Bip39GenMnemonic(test.randSize, nil)
 		if err != nil {
 			t.Errorf("%v: GenMnemonicBip39 err: %v", test.name, err)
 			return
 		}
-		mLen := len(strings.Split(mnemonic, " "))
+		mLen := len(strings.Split(string(mnemonic.Bytes()), " "))
+		mnemonic.Destroy()
 		if mLen != test.wordsNum {
 			t.Errorf("%v: GenMnemonicBip39 fail : want %v got %v", test.name, test.wordsNum, mLen)
 			return
@@ -57,6 +70,39 @@ func TestGenMnemonicBip39(t *testing.T) {
 	}
 }
 
+func TestGenMnemonicBip39_Deterministic(t *testing.T) {
+	gen := func(seed string) string {
+		r, err := security.NewDeterministicReader([]byte(seed))
+		if err != nil {
+			t.Fatalf("NewDeterministicReader() error: %v", err)
+		}
+		mnemonic, err := Bip39GenMnemonic(256, r)
+		if err != nil {
+			t.Fatalf("Bip39GenMnemonic() error: %v", err)
+		}
+		defer mnemonic.Destroy()
+		return string(mnemonic.Bytes())
+	}
+
+	if gen("fixture") != gen("fixture") {
+		t.Error("same seed produced different mnemonics")
+	}
+	if gen("fixture") == gen("other") {
+		t.Error("different seeds produced the same mnemonic")
+	}
+}
+
+func TestGenMnemonicBip39_InvalidSize(t *testing.T) {
+	for _, size := range []int{0, 96, 100, 288} {
+		if _, err := Bip39GenMnemonic(size, nil); err == nil {
+			t.Errorf("Bip39GenMnemonic(%d) expected error", size)
+		}
+	}
+	if _, err := Bip39GenMnemonic(128, bytes.NewReader([]byte{1, 2, 3})); err == nil {
+		t.Error("Bip39GenMnemonic() expected error on short reader")
+	}
+}
+
 func TestMnemonicToSeedBip39(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -104,16 +150,17 @@ func TestMnemonicToSeedBip39(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		seed, err := Bip39MnemonicToSeed(test.mnemonic, "")
+		seed, err := Bip39MnemonicToSeed(secretString(t, test.mnemonic), "")
 		if err != nil {
 			t.Errorf("%v: Bip39MnemonicToSeed err: %v", test.name, err)
 			return
 		}
 
-		if !bytes.Equal(seed, test.seed) {
-			t.Errorf("%v: Bip39MnemonicToSeed failed : want %v got %v", test.name, test.seed, seed)
+		if !bytes.Equal(seed.Bytes(), test.seed) {
+			t.Errorf("%v: Bip39MnemonicToSeed failed : want %v got %v", test.name, test.seed, seed.Bytes())
 			return
 		}
+		seed.Destroy()
 
 	}
 }
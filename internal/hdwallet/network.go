@@ -0,0 +1,92 @@
+package hdwallet
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// ErrUnknownNetwork indicates the requested network name is not supported
+var ErrUnknownNetwork = errors.New("unknown network")
+
+// Network bundles the parameters that differ between main and test networks.
+//
+// Params controls the BIP32 extended key version bytes, WIF prefix and
+// Bitcoin address encoding, while ChainID is the EIP-155 chain ID used
+// for EVM transactions on the matching network.
+type Network struct {
+	Name    string
+	Params  *chaincfg.Params
+	ChainID *big.Int
+}
+
+var (
+	// MainNet is Bitcoin mainnet and Ethereum mainnet
+	MainNet = &Network{Name: "mainnet", Params: &chaincfg.MainNetParams, ChainID: big.NewInt(1)}
+	// TestNet3 is Bitcoin testnet3, paired with Sepolia for EVM
+	TestNet3 = &Network{Name: "testnet3", Params: &chaincfg.TestNet3Params, ChainID: big.NewInt(11155111)}
+	// TestNet4 is Bitcoin testnet4 (BIP94), paired with Sepolia for EVM
+	TestNet4 = &Network{Name: "testnet4", Params: &chaincfg.TestNet4Params, ChainID: big.NewInt(11155111)}
+	// SigNet is the default Bitcoin signet, paired with Sepolia for EVM
+	SigNet = &Network{Name: "signet", Params: &chaincfg.SigNetParams, ChainID: big.NewInt(11155111)}
+	// RegTest is Bitcoin regtest, paired with the Hardhat/Anvil local chain ID
+	RegTest = &Network{Name: "regtest", Params: &chaincfg.RegressionNetParams, ChainID: big.NewInt(31337)}
+	// Sepolia is the Sepolia EVM testnet, using testnet Bitcoin encodings
+	Sepolia = &Network{Name: "sepolia", Params: &chaincfg.TestNet3Params, ChainID: big.NewInt(11155111)}
+	// Holesky is the Holesky EVM testnet, using testnet Bitcoin encodings
+	Holesky = &Network{Name: "holesky", Params: &chaincfg.TestNet3Params, ChainID: big.NewInt(17000)}
+)
+
+// Networks lists every supported network in display order
+var Networks = []*Network{MainNet, TestNet3, TestNet4, SigNet, RegTest, Sepolia, Holesky}
+
+// NetworkNames returns the names of all supported networks
+func NetworkNames() []string {
+	names := make([]string, len(Networks))
+	for i, n := range Networks {
+		names[i] = n.Name
+	}
+	return names
+}
+
+// NetworkByName looks up a network by name (case-insensitive).
+// An empty name selects MainNet.
+func NetworkByName(name string) (*Network, error) {
+	if name == "" {
+		return MainNet, nil
+	}
+	for _, n := range Networks {
+		if strings.EqualFold(n.Name, name) {
+			return n, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q (supported: %s)", ErrUnknownNetwork, name, strings.Join(NetworkNames(), ", "))
+}
+
+// IsMainNet reports whether the network moves real funds
+func (n *Network) IsMainNet() bool {
+	return n.Params.Net == chaincfg.MainNetParams.Net
+}
+
+// PubkeyToAddress encodes a P2PKH address using the network's version byte
+func (n *Network) PubkeyToAddress(key []byte) string {
+	return PubkeyToAddress(key, n.Params.PubKeyHashAddrID)
+}
+
+// WIF encodes a private key in Wallet Import Format for the network
+func (n *Network) WIF(privateKey *btcec.PrivateKey, compressed bool) (string, error) {
+	wif, err := btcutil.NewWIF(privateKey, n.Params, compressed)
+	if err != nil {
+		return "", err
+	}
+	return wif.String(), nil
+}
+
+func (n *Network) String() string {
+	return n.Name
+}
@@ -0,0 +1,113 @@
+package hdwallet
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNetworkByName(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    *Network
+		wantErr bool
+	}{
+		{"empty defaults to mainnet", "", MainNet, false},
+		{"mainnet", "mainnet", MainNet, false},
+		{"case insensitive", "TestNet3", TestNet3, false},
+		{"testnet4", "testnet4", TestNet4, false},
+		{"signet", "signet", SigNet, false},
+		{"regtest", "regtest", RegTest, false},
+		{"sepolia", "sepolia", Sepolia, false},
+		{"holesky", "holesky", Holesky, false},
+		{"unknown", "ropsten", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NetworkByName(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NetworkByName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, ErrUnknownNetwork) {
+					t.Errorf("NetworkByName() error = %v, want %v", err, ErrUnknownNetwork)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("NetworkByName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathFromMnemonic_Networks(t *testing.T) {
+	// Well-known BIP39 test mnemonic
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	path := "m/44'/0'/0'/0/0"
+
+	tests := []struct {
+		net        *Network
+		xprvPrefix string
+		xpubPrefix string
+		wifPrefix  []string
+		addrPrefix []string
+	}{
+		{MainNet, "xprv", "xpub", []string{"K", "L"}, []string{"1"}},
+		{TestNet3, "tprv", "tpub", []string{"c"}, []string{"m", "n"}},
+		{TestNet4, "tprv", "tpub", []string{"c"}, []string{"m", "n"}},
+		{SigNet, "tprv", "tpub", []string{"c"}, []string{"m", "n"}},
+		{RegTest, "tprv", "tpub", []string{"c"}, []string{"m", "n"}},
+	}
+
+	mainInfo, err := PathFromMnemonic(secretString(t, mnemonic), path, MainNet)
+	if err != nil {
+		t.Fatalf("PathFromMnemonic failed: %v", err)
+	}
+	defer mainInfo.Destroy()
+	if mainInfo.BTCAddress != "1LqBGSKuX5yYUonjxT5qGfpUsXKYYWeabA" {
+		t.Errorf("unexpected mainnet address %s", mainInfo.BTCAddress)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.net.Name, func(t *testing.T) {
+			info, err := PathFromMnemonic(secretString(t, mnemonic), path, tt.net)
+			if err != nil {
+				t.Fatalf("PathFromMnemonic failed: %v", err)
+			}
+			defer info.Destroy()
+			xprv, wif := string(info.ExtendedPrivateKey.Bytes()), string(info.WIF.Bytes())
+			if info.Network != tt.net {
+				t.Errorf("Network = %v, want %v", info.Network, tt.net)
+			}
+			if !strings.HasPrefix(xprv, tt.xprvPrefix) {
+				t.Errorf("extended private key %s does not start with %s", xprv, tt.xprvPrefix)
+			}
+			if !strings.HasPrefix(info.ExtendedPublicKey, tt.xpubPrefix) {
+				t.Errorf("extended public key %s does not start with %s", info.ExtendedPublicKey, tt.xpubPrefix)
+			}
+			if !hasAnyPrefix(wif, tt.wifPrefix) {
+				t.Errorf("WIF %s does not start with any of %v", wif, tt.wifPrefix)
+			}
+			if !hasAnyPrefix(info.BTCAddress, tt.addrPrefix) {
+				t.Errorf("address %s does not start with any of %v", info.BTCAddress, tt.addrPrefix)
+			}
+
+			// The key material itself must not depend on the network
+			if !info.PrivateKey.Equal(mainInfo.PrivateKey.Bytes()) || info.Address != mainInfo.Address {
+				t.Errorf("key material differs between %s and mainnet", tt.net.Name)
+			}
+		})
+	}
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
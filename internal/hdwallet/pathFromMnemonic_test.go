@@ -65,7 +65,7 @@ func TestPathFromMnemonic(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := PathFromMnemonic(tt.mnemonic, tt.path)
+			result, err := PathFromMnemonic(secretString(t, tt.mnemonic), tt.path, MainNet)
 
 			if tt.expectError {
 				if err == nil {
@@ -82,7 +82,7 @@ func TestPathFromMnemonic(t *testing.T) {
 			}
 
 			// Check result format: should be address:privateKey
-			parts := strings.Split(result, ":")
+			parts := strings.Split(result.String(), ":")
 			if len(parts) != 2 {
 				t.Errorf("Expected result format 'address:privateKey', got: %s", result)
 				return
@@ -108,17 +108,17 @@ func TestPathFromMnemonic_Deterministic(t *testing.T) {
 	mnemonic := "close same tongue random ice cave aim input whale salute squirrel vivid"
 	path := "m/44'/60'/0'/0/0"
 
-	result1, err1 := PathFromMnemonic(mnemonic, path)
+	result1, err1 := PathFromMnemonic(secretString(t, mnemonic), path, MainNet)
 	if err1 != nil {
 		t.Fatalf("First call failed: %v", err1)
 	}
 
-	result2, err2 := PathFromMnemonic(mnemonic, path)
+	result2, err2 := PathFromMnemonic(secretString(t, mnemonic), path, MainNet)
 	if err2 != nil {
 		t.Fatalf("Second call failed: %v", err2)
 	}
 
-	if result1 != result2 {
+	if result1.String() != result2.String() {
 		t.Errorf("Results are not deterministic: '%s' != '%s'", result1, result2)
 	}
 }
@@ -129,23 +129,23 @@ func TestPathFromMnemonic_DifferentPaths(t *testing.T) {
 	path1 := "m/44'/60'/0'/0/0"
 	path2 := "m/44'/60'/0'/0/1"
 
-	result1, err1 := PathFromMnemonic(mnemonic, path1)
+	result1, err1 := PathFromMnemonic(secretString(t, mnemonic), path1, MainNet)
 	if err1 != nil {
 		t.Fatalf("Path1 failed: %v", err1)
 	}
 
-	result2, err2 := PathFromMnemonic(mnemonic, path2)
+	result2, err2 := PathFromMnemonic(secretString(t, mnemonic), path2, MainNet)
 	if err2 != nil {
 		t.Fatalf("Path2 failed: %v", err2)
 	}
 
-	if result1 == result2 {
+	if result1.String() == result2.String() {
 		t.Errorf("Different paths should produce different results")
 	}
 
 	// Extract addresses
-	addr1 := strings.Split(result1, ":")[0]
-	addr2 := strings.Split(result2, ":")[0]
+	addr1 := strings.Split(result1.String(), ":")[0]
+	addr2 := strings.Split(result2.String(), ":")[0]
 
 	if addr1 == addr2 {
 		t.Errorf("Different paths should produce different addresses")
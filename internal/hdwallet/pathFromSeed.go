@@ -1,68 +1,24 @@
 package hdwallet
 
 import (
-	"crypto/ecdsa"
 	"encoding/hex"
 	"fmt"
 
-	"github.com/btcsuite/btcd/btcutil/hdkeychain"
-	"github.com/btcsuite/btcd/chaincfg"
-	"github.com/ethereum/go-ethereum/accounts"
-	"github.com/ethereum/go-ethereum/common/hexutil"
-	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spark8899/gowallet/internal/security"
 )
 
-func PathFromSeed(seedStr string, pathStr string) (string, error) {
-	var err error
-	seed, err := hex.DecodeString(seedStr)
+// PathFromSeed derives the key at pathStr from a hex encoded BIP39 seed on
+// net. A nil net selects MainNet. The decoded seed is wiped before returning.
+func PathFromSeed(seedHex *security.SecretBuffer, pathStr string, net *Network) (*KeyInfo, error) {
+	seed, err := security.NewSecretBuffer(hex.DecodedLen(seedHex.Len()))
 	if err != nil {
-		fmt.Println("seed str to bytes error")
-		return "seed str to bytes", err
+		return nil, err
 	}
+	defer seed.Destroy()
 
-	path, err := accounts.ParseDerivationPath(pathStr)
-	if err != nil {
-		fmt.Println("path str to path error")
-		return "path str to path", err
-	}
-
-	// crate master private key
-	masterKey, err := hdkeychain.NewMaster(seed, &chaincfg.MainNetParams)
-	if err != nil {
-		fmt.Println("Get masterKey error")
-		return "Get masterKey error", err
-	}
-	//fmt.Println("masterKey:", masterKey)
-
-	// derivation path
-	fixIssue172 := true
-	for _, n := range path {
-		if fixIssue172 && masterKey.IsAffectedByIssue172() {
-			masterKey, err = masterKey.Derive(n)
-		} else {
-			masterKey, err = masterKey.DeriveNonStandard(n)
-		}
-		if err != nil {
-			return "", err
-		}
-	}
-
-	//fmt.Println("masterKey:", masterKey)
-	privateKey, err := masterKey.ECPrivKey()
-	privateKeyECDSA := privateKey.ToECDSA()
-	if err != nil {
-		return "", err
-	}
-
-	// get eth address
-	publicKey := privateKeyECDSA.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		fmt.Println("Unable to get public key")
-		return "", err
+	if _, err := hex.Decode(seed.Bytes(), seedHex.Bytes()); err != nil {
+		return nil, fmt.Errorf("seed str to bytes: %w", err)
 	}
-	address := crypto.PubkeyToAddress(*publicKeyECDSA).Hex()
 
-	info := fmt.Sprintf("%v:%v", address, hexutil.Encode(crypto.FromECDSA(privateKeyECDSA)))
-	return info, nil
+	return deriveKeyInfo(seed.Bytes(), pathStr, net)
 }
@@ -1,9 +1,12 @@
 package hdwallet
 
 import (
+	"bytes"
 	"fmt"
 	"strings"
 	"testing"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
 )
 
 func TestPathFromSeed(t *testing.T) {
@@ -64,13 +67,13 @@ func TestPathFromSeed(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := PathFromSeed(tt.seedHex, tt.path)
+			result, err := PathFromSeed(secretString(t, tt.seedHex), tt.path, MainNet)
 
 			if tt.expectError {
 				if err == nil {
 					t.Errorf("Expected error containing '%s', got nil", tt.errorMsg)
-				} else if !strings.Contains(result, tt.errorMsg) && !strings.Contains(err.Error(), tt.errorMsg) {
-					t.Logf("Expected error containing '%s', got result='%s', err='%v'", tt.errorMsg, result, err)
+				} else if !strings.Contains(err.Error(), tt.errorMsg) {
+					t.Logf("Expected error containing '%s', got '%v'", tt.errorMsg, err)
 				}
 				return
 			}
@@ -81,7 +84,7 @@ func TestPathFromSeed(t *testing.T) {
 			}
 
 			// Check result format: should be address:privateKey
-			parts := strings.Split(result, ":")
+			parts := strings.Split(result.String(), ":")
 			if len(parts) != 2 {
 				t.Errorf("Expected result format 'address:privateKey', got: %s", result)
 				return
@@ -107,17 +110,17 @@ func TestPathFromSeed_Deterministic(t *testing.T) {
 	seedHex := "126b7f8653ce2b1f05dd78d33c57737df4edf889ee2729338202d164831e2ab43d40d2a26d73739570cf816cb96d766b8d3850258d58c89f7e9901edf13e80a8"
 	path := "m/44'/60'/0'/0/0"
 
-	result1, err1 := PathFromSeed(seedHex, path)
+	result1, err1 := PathFromSeed(secretString(t, seedHex), path, MainNet)
 	if err1 != nil {
 		t.Fatalf("First call failed: %v", err1)
 	}
 
-	result2, err2 := PathFromSeed(seedHex, path)
+	result2, err2 := PathFromSeed(secretString(t, seedHex), path, MainNet)
 	if err2 != nil {
 		t.Fatalf("Second call failed: %v", err2)
 	}
 
-	if result1 != result2 {
+	if result1.String() != result2.String() {
 		t.Errorf("Results are not deterministic: '%s' != '%s'", result1, result2)
 	}
 }
@@ -128,23 +131,23 @@ func TestPathFromSeed_DifferentPaths(t *testing.T) {
 	path1 := "m/44'/60'/0'/0/0"
 	path2 := "m/44'/60'/0'/0/1"
 
-	result1, err1 := PathFromSeed(seedHex, path1)
+	result1, err1 := PathFromSeed(secretString(t, seedHex), path1, MainNet)
 	if err1 != nil {
 		t.Fatalf("Path1 failed: %v", err1)
 	}
 
-	result2, err2 := PathFromSeed(seedHex, path2)
+	result2, err2 := PathFromSeed(secretString(t, seedHex), path2, MainNet)
 	if err2 != nil {
 		t.Fatalf("Path2 failed: %v", err2)
 	}
 
-	if result1 == result2 {
+	if result1.String() == result2.String() {
 		t.Errorf("Different paths should produce different results")
 	}
 
 	// Extract addresses
-	addr1 := strings.Split(result1, ":")[0]
-	addr2 := strings.Split(result2, ":")[0]
+	addr1 := strings.Split(result1.String(), ":")[0]
+	addr2 := strings.Split(result2.String(), ":")[0]
 
 	if addr1 == addr2 {
 		t.Errorf("Different paths should produce different addresses")
@@ -157,35 +160,90 @@ func TestPathFromSeed_MnemonicConsistency(t *testing.T) {
 	path := "m/44'/60'/0'/0/0"
 
 	// Get result from mnemonic directly
-	resultFromMnemonic, err1 := PathFromMnemonic(mnemonic, path)
+	resultFromMnemonic, err1 := PathFromMnemonic(secretString(t, mnemonic), path, MainNet)
 	if err1 != nil {
 		t.Fatalf("PathFromMnemonic failed: %v", err1)
 	}
 
 	// Convert mnemonic to seed then derive path
-	seed, err2 := Bip39MnemonicToSeed(mnemonic, "")
+	seed, err2 := Bip39MnemonicToSeed(secretString(t, mnemonic), "")
 	if err2 != nil {
 		t.Fatalf("Bip39MnemonicToSeed failed: %v", err2)
 	}
+	defer seed.Destroy()
 
-	seedHex := fmt.Sprintf("%x", seed)
-	resultFromSeed, err3 := PathFromSeed(seedHex, path)
+	seedHex := fmt.Sprintf("%x", seed.Bytes())
+	resultFromSeed, err3 := PathFromSeed(secretString(t, seedHex), path, MainNet)
 	if err3 != nil {
 		t.Fatalf("PathFromSeed failed: %v", err3)
 	}
 
 	// Both methods should produce the same result
-	if resultFromMnemonic != resultFromSeed {
+	if resultFromMnemonic.String() != resultFromSeed.String() {
 		t.Errorf("Mismatch between PathFromMnemonic and PathFromSeed:\nMnemonic: %s\nSeed: %s", resultFromMnemonic, resultFromSeed)
 	}
 }
 
+func TestPathFromSeed_WipesIntermediateKeys(t *testing.T) {
+	var wiped []*hdkeychain.ExtendedKey
+	orig := zeroExtendedKey
+	zeroExtendedKey = func(k *hdkeychain.ExtendedKey) {
+		wiped = append(wiped, k)
+		orig(k)
+	}
+	t.Cleanup(func() { zeroExtendedKey = orig })
+
+	seedHex := "126b7f8653ce2b1f05dd78d33c57737df4edf889ee2729338202d164831e2ab43d40d2a26d73739570cf816cb96d766b8d3850258d58c89f7e9901edf13e80a8"
+	info, err := PathFromSeed(secretString(t, seedHex), "m/44'/60'/0'/0/0", MainNet)
+	if err != nil {
+		t.Fatalf("PathFromSeed() error: %v", err)
+	}
+	defer info.Destroy()
+
+	// The master key and one key per path level
+	if len(wiped) != 6 {
+		t.Fatalf("wiped %d extended keys, want 6", len(wiped))
+	}
+	seen := map[*hdkeychain.ExtendedKey]bool{}
+	for i, k := range wiped {
+		if seen[k] {
+			t.Errorf("key %d wiped twice, another key was missed", i)
+		}
+		seen[k] = true
+		if k.IsPrivate() {
+			t.Errorf("key %d still private after wipe", i)
+		}
+		if _, err := k.ECPrivKey(); err == nil {
+			t.Errorf("key %d still yields a private key", i)
+		}
+		if chainCode := k.ChainCode(); !bytes.Equal(chainCode, make([]byte, len(chainCode))) {
+			t.Errorf("key %d chain code %x not wiped", i, chainCode)
+		}
+	}
+}
+
+func TestPathFromSeed_MasterFingerprint(t *testing.T) {
+	// BIP32 test vector 1
+	info, err := PathFromSeed(secretString(t, "000102030405060708090a0b0c0d0e0f"), "m/0'", MainNet)
+	if err != nil {
+		t.Fatalf("PathFromSeed() error: %v", err)
+	}
+	defer info.Destroy()
+	if info.MasterFingerprint != 0x3442193e {
+		t.Errorf("MasterFingerprint = %08x, want 3442193e", info.MasterFingerprint)
+	}
+	if want := "xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBFA1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2dZXvgGDnw"; info.ExtendedPublicKey != want {
+		t.Errorf("ExtendedPublicKey = %s, want %s", info.ExtendedPublicKey, want)
+	}
+}
+
 // Benchmark for performance testing
 func BenchmarkPathFromSeed(b *testing.B) {
 	seedHex := "126b7f8653ce2b1f05dd78d33c57737df4edf889ee2729338202d164831e2ab43d40d2a26d73739570cf816cb96d766b8d3850258d58c89f7e9901edf13e80a8"
 	path := "m/44'/60'/0'/0/0"
 
+	seed := secretString(b, seedHex)
 	for i := 0; i < b.N; i++ {
-		_, _ = PathFromSeed(seedHex, path)
+		_, _ = PathFromSeed(seed, path, MainNet)
 	}
 }
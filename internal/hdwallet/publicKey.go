@@ -0,0 +1,74 @@
+package hdwallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/spark8899/gowallet/internal/schnorr"
+)
+
+// ErrInvalidPublicKey indicates input that is not a secp256k1 public key in
+// any supported form
+var ErrInvalidPublicKey = errors.New("invalid public key")
+
+// ParsePublicKey parses a secp256k1 public key given as hex (optionally 0x
+// prefixed) in compressed (33 bytes), uncompressed (65 bytes) or raw X||Y
+// (64 bytes, as Ethereum tools print it) form, or as an extended public key
+// (xpub, ypub, zpub, tpub, ...), in which case the key at its depth is used.
+// A BIP340 x-only key (32 bytes) is rejected: it drops the parity of y, and
+// the key it names is only right for half of all keys; use
+// schnorr.ParseXOnlyPublicKey where x alone is meant
+func ParsePublicKey(s string) (*btcec.PublicKey, error) {
+	s = strings.TrimSpace(s)
+	if len(s) == 111 && (strings.HasSuffix(s[:4], "pub") || strings.HasSuffix(s[:4], "prv")) {
+		key, err := hdkeychain.NewKeyFromString(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPublicKey, err)
+		}
+		if key.IsPrivate() {
+			return nil, fmt.Errorf("%w: this is an extended private key; pass the xpub", ErrInvalidPublicKey)
+		}
+		return key.ECPubKey()
+	}
+
+	raw, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: not hex or an extended key", ErrInvalidPublicKey)
+	}
+	switch len(raw) {
+	case 32:
+		return nil, fmt.Errorf("%w: 32 bytes is an x-only key, which lacks the parity of y; pass the compressed (33-byte) key", ErrInvalidPublicKey)
+	case 64:
+		raw = append([]byte{0x04}, raw...)
+	case 33, 65:
+	default:
+		return nil, fmt.Errorf("%w: %d bytes, want 33, 64 or 65", ErrInvalidPublicKey, len(raw))
+	}
+	pub, err := btcec.ParsePubKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPublicKey, err)
+	}
+	return pub, nil
+}
+
+// PublicKeyForm is one encoding of a public key
+type PublicKeyForm struct {
+	Name string
+	Hex  string // 0x-prefixed
+}
+
+// PublicKeyForms returns the compressed, uncompressed, raw X||Y and BIP340
+// x-only hex encodings of pub
+func PublicKeyForms(pub *btcec.PublicKey) []PublicKeyForm {
+	uncompressed := pub.SerializeUncompressed()
+	return []PublicKeyForm{
+		{"compressed", "0x" + hex.EncodeToString(pub.SerializeCompressed())},
+		{"uncompressed", "0x" + hex.EncodeToString(uncompressed)},
+		{"raw", "0x" + hex.EncodeToString(uncompressed[1:])},
+		{"x-only", "0x" + hex.EncodeToString(schnorr.XOnlyPublicKey(pub))},
+	}
+}
@@ -0,0 +1,75 @@
+package hdwallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestParsePublicKey(t *testing.T) {
+	// Hardhat/Anvil default account #0
+	compressed := "038318535b54105d4a7aae60c08fc45f9687181b4fdfc625bd1a753fa7397fed75"
+	uncompressed := "048318535b54105d4a7aae60c08fc45f9687181b4fdfc625bd1a753fa7397fed753547f11ca8696646f2f3acb08e31016afac23e630c5d11f59f61fef57b0d2aa5"
+
+	for _, in := range []string{compressed, "0x" + compressed, uncompressed, "0x" + uncompressed[2:], " " + uncompressed + "\n"} {
+		pub, err := ParsePublicKey(in)
+		if err != nil {
+			t.Errorf("ParsePublicKey(%q): %v", in, err)
+			continue
+		}
+		if got := hex.EncodeToString(pub.SerializeCompressed()); got != compressed {
+			t.Errorf("ParsePublicKey(%q) = %s, want %s", in, got, compressed)
+		}
+		if got := Ethereum.Address(pub); got != "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266" {
+			t.Errorf("Ethereum address of %q = %s", in, got)
+		}
+	}
+
+	forms := PublicKeyForms(mustPublicKey(t, compressed))
+	if forms[0].Hex != "0x"+compressed || forms[1].Hex != "0x"+uncompressed || forms[2].Hex != "0x"+uncompressed[2:] || forms[3].Hex != "0x"+compressed[2:] {
+		t.Errorf("PublicKeyForms = %v", forms)
+	}
+
+	// BIP32 test vector 1, chain m
+	pub, err := ParsePublicKey("xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hex.EncodeToString(pub.SerializeCompressed()); got != "0339a36013301597daef41fbe593a02cc513d0b55527ec2df1050e2e8ff49c85c2" {
+		t.Errorf("xpub public key = %s", got)
+	}
+
+	for _, bad := range []string{
+		"xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi",
+		compressed[:64],
+		compressed[2:], // x-only: the parity of y is lost
+		"05" + compressed[2:],
+		uncompressed[:128] + "a6", // not on the curve
+		"not a key",
+	} {
+		if _, err := ParsePublicKey(bad); !errors.Is(err, ErrInvalidPublicKey) {
+			t.Errorf("ParsePublicKey(%q) error = %v, want ErrInvalidPublicKey", bad, err)
+		}
+	}
+}
+
+func TestBitcoinChain(t *testing.T) {
+	pub := mustPublicKey(t, "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	// BIP173 example: the generator point's P2WPKH address
+	if got := BitcoinChain(MainNet).Address(pub); got != "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4" {
+		t.Errorf("mainnet address = %s", got)
+	}
+	if got := BitcoinChain(TestNet3).Address(pub); got != "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx" {
+		t.Errorf("testnet address = %s", got)
+	}
+	legacy, err := BitcoinChain(MainNet).WithAddressType(P2PKH)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := legacy.Address(pub); got != "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH" {
+		t.Errorf("P2PKH address = %s", got)
+	}
+	if c, err := ChainForNetwork("bitcoin", TestNet3); err != nil || c.DefaultPath != "m/84'/1'/0'/0/0" {
+		t.Errorf("ChainForNetwork(bitcoin, testnet3) = %v, %v", c, err)
+	}
+}
@@ -0,0 +1,68 @@
+package hdwallet
+
+import (
+	"bytes"
+
+	"github.com/spark8899/gowallet/internal/entropy"
+	"github.com/spark8899/gowallet/internal/security"
+)
+
+// publicMnemonics are mnemonics printed in public documentation: development
+// chain defaults, BIP39 reference vectors and the examples in this
+// repository's README and tests. Any funds sent to them are swept by bots.
+var publicMnemonics = map[string]string{
+	"test test test test test test test test test test test junk":                                                                                                  "Hardhat/Anvil default mnemonic",
+	"myth like bonus scare over problem client lizard pioneer submit female collect":                                                                               "Ganache --deterministic mnemonic",
+	"tag volcano eight thank tide danger coast health above argue embrace heavy":                                                                                   "gowallet README example",
+	"close same tongue random ice cave aim input whale salute squirrel vivid":                                                                                      "gowallet test fixture",
+	"ozone drill grab fiber curtain grace pudding thank cruise elder eight picnic":                                                                                 "BIP39 test vector",
+	"scheme spot photo card baby mountain device kick cradle pact join borrow":                                                                                     "BIP39 test vector",
+	"cat swing flag economy stadium alone churn speed unique patch report train":                                                                                   "BIP39 test vector",
+	"vessel ladder alter error federal sibling chat ability sun glass valve picture":                                                                               "BIP39 test vector",
+	"gravity machine north sort system female filter attitude volume fold club stay feature office ecology stable narrow fog":                                      "BIP39 test vector",
+	"horn tenant knee talent sponsor spell gate clip pulse soap slush warm silver nephew swap uncle crack brave":                                                   "BIP39 test vector",
+	"light rule cinnamon wrap drastic word pride squirrel upgrade then income fatal apart sustain crack supply proud access":                                       "BIP39 test vector",
+	"scissors invite lock maple supreme raw rapid void congress muscle digital elegant little brisk hair mango congress clump":                                     "BIP39 test vector",
+	"hamster diagram private dutch cause delay private meat slide toddler razor book happy fancy gospel tennis maple dilemma loan word shrug inflict delay length": "BIP39 test vector",
+	"panda eyebrow bullet gorilla call smoke muffin taste mesh discover soft ostrich alcohol speed nation flash devote level hobby quick inner drive ghost inside": "BIP39 test vector",
+	"all hour make first leader extend hole alien behind guard gospel lava path output census museum junior mass reopen famous sing advance salt reform":           "BIP39 test vector",
+	"void come effort suffer camp survey warrior heavy shoot primary clutch crush open amazing screen patrol group space point ten exist slush involve unfold":     "BIP39 test vector",
+}
+
+// CheckPublicMnemonic reports whether mnemonic is publicly known, returning
+// where it was published. Besides the fixed list, any mnemonic whose
+// entropy fails the entropy health tests is reported, which covers the
+// patterned BIP39 vectors such as "abandon ... about" and "zoo ... wrong".
+// The normalized copy and the decoded entropy are wiped before returning.
+func CheckPublicMnemonic(mnemonic *security.SecretBuffer) (string, bool) {
+	normalized, err := security.NewSecretBuffer(mnemonic.Len())
+	if err != nil {
+		return "", false
+	}
+	defer destroySecret(normalized)
+	buf := normalized.Bytes()[:0]
+	for _, word := range bytes.Fields(mnemonic.Bytes()) {
+		if len(buf) > 0 {
+			buf = append(buf, ' ')
+		}
+		for _, c := range word {
+			if 'A' <= c && c <= 'Z' {
+				c += 'a' - 'A'
+			}
+			buf = append(buf, c)
+		}
+	}
+	if source, ok := publicMnemonics[string(buf)]; ok {
+		return source, true
+	}
+
+	entropyBytes, err := entropyFromMnemonic(buf)
+	if err != nil {
+		return "", false
+	}
+	defer destroySecret(entropyBytes)
+	if err := entropy.Validate(entropyBytes.Bytes()); err != nil {
+		return "patterned entropy (" + err.Error() + ")", true
+	}
+	return "", false
+}
@@ -0,0 +1,82 @@
+package hdwallet
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tyler-smith/go-bip39"
+)
+
+func TestCheckPublicMnemonic(t *testing.T) {
+	tests := []struct {
+		name     string
+		mnemonic string
+		want     string
+	}{
+		{"Hardhat", "test test test test test test test test test test test junk", "Hardhat"},
+		{"case and whitespace", "  Test TEST test test test test\ntest test test test test junk ", "Hardhat"},
+		{"Ganache", "myth like bonus scare over problem client lizard pioneer submit female collect", "Ganache"},
+		{"BIP39 random vector", "ozone drill grab fiber curtain grace pudding thank cruise elder eight picnic", "BIP39"},
+		{"BIP39 zero vector", strings.Repeat("abandon ", 11) + "about", "patterned entropy"},
+		{"BIP39 ff vector", strings.Repeat("zoo ", 11) + "wrong", "patterned entropy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, ok := CheckPublicMnemonic(secretString(t, tt.mnemonic))
+			if !ok || !strings.Contains(source, tt.want) {
+				t.Errorf("CheckPublicMnemonic() = %q, %v; want source containing %q", source, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckPublicMnemonic_ListIsValid(t *testing.T) {
+	for mnemonic := range publicMnemonics {
+		if !bip39.IsMnemonicValid(mnemonic) {
+			t.Errorf("invalid mnemonic in list: %q", mnemonic)
+		}
+	}
+}
+
+func TestCheckPublicMnemonic_Fresh(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		mnemonic, err := Bip39GenMnemonic(128, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if source, ok := CheckPublicMnemonic(mnemonic); ok {
+			t.Errorf("fresh mnemonic flagged as %s", source)
+		}
+		mnemonic.Destroy()
+	}
+	if _, ok := CheckPublicMnemonic(secretString(t, "not a mnemonic")); ok {
+		t.Error("invalid mnemonic flagged as public")
+	}
+}
+
+func TestCheckPublicMnemonic_Wipes(t *testing.T) {
+	tests := []struct {
+		name     string
+		mnemonic string
+		want     int
+	}{
+		{"listed", "Test test test test test test test test test test test junk", 1},
+		{"patterned", strings.Repeat("abandon ", 11) + "about", 2},
+		{"invalid", "not a mnemonic", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			destroyed := captureDestroyed(t)
+			CheckPublicMnemonic(secretString(t, tt.mnemonic))
+			if len(*destroyed) != tt.want {
+				t.Fatalf("destroyed %d buffers, want %d (the normalized copy and any entropy)", len(*destroyed), tt.want)
+			}
+			for i, s := range *destroyed {
+				if !s.Destroyed() {
+					t.Errorf("buffer %d not wiped", i)
+				}
+			}
+		})
+	}
+}
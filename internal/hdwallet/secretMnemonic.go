@@ -0,0 +1,121 @@
+package hdwallet
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/spark8899/gowallet/internal/security"
+	"github.com/tyler-smith/go-bip39"
+	"github.com/tyler-smith/go-bip39/wordlists"
+)
+
+// bip39WordIndexes maps each English BIP39 word to its 11-bit index. Lookups
+// with m[string(b)] do not allocate, so words never become heap strings.
+var bip39WordIndexes = func() map[string]int {
+	indexes := make(map[string]int, len(wordlists.English))
+	for i, word := range wordlists.English {
+		indexes[word] = i
+	}
+	return indexes
+}()
+
+// mnemonicFromEntropy encodes entropy as English BIP39 words directly into a
+// SecretBuffer. It matches bip39.NewMnemonic without creating a string.
+func mnemonicFromEntropy(entropyBytes []byte) (*security.SecretBuffer, error) {
+	bits := len(entropyBytes) * 8
+	if bits%32 != 0 || bits < 128 || bits > 256 {
+		return nil, bip39.ErrEntropyLengthInvalid
+	}
+	checksumBits := bits / 32
+	words := (bits + checksumBits) / 11
+
+	// Entropy followed by the first checksum byte, enough for all words
+	sum := sha256.Sum256(entropyBytes)
+	data := make([]byte, len(entropyBytes)+1)
+	defer security.ZeroBytes(data)
+	defer security.ZeroBytes(sum[:])
+	copy(data, entropyBytes)
+	data[len(entropyBytes)] = sum[0]
+
+	indexes := make([]int, words)
+	defer func() {
+		for i := range indexes {
+			indexes[i] = 0
+		}
+	}()
+	length := words - 1
+	for i := range indexes {
+		indexes[i] = readBits(data, i*11, 11)
+		length += len(wordlists.English[indexes[i]])
+	}
+
+	out, err := security.NewSecretBuffer(length)
+	if err != nil {
+		return nil, err
+	}
+	buf := out.Bytes()[:0]
+	for i, index := range indexes {
+		if i > 0 {
+			buf = append(buf, ' ')
+		}
+		buf = append(buf, wordlists.English[index]...)
+	}
+	return out, nil
+}
+
+// entropyFromMnemonic decodes and checksums English BIP39 words (separated
+// by any whitespace) into a SecretBuffer, like bip39.EntropyFromMnemonic
+func entropyFromMnemonic(mnemonic []byte) (*security.SecretBuffer, error) {
+	fields := bytes.Fields(mnemonic)
+	switch len(fields) {
+	case 12, 15, 18, 21, 24:
+	default:
+		return nil, fmt.Errorf("%w: %d words", bip39.ErrInvalidMnemonic, len(fields))
+	}
+
+	totalBits := len(fields) * 11
+	checksumBits := totalBits / 33
+	data := make([]byte, (totalBits+7)/8)
+	defer security.ZeroBytes(data)
+	for i, word := range fields {
+		index, ok := bip39WordIndexes[string(word)]
+		if !ok {
+			return nil, fmt.Errorf("%w: word %d is not in the word list", bip39.ErrInvalidMnemonic, i+1)
+		}
+		writeBits(data, i*11, 11, index)
+	}
+
+	entropyLen := (totalBits - checksumBits) / 8
+	sum := sha256.Sum256(data[:entropyLen])
+	defer security.ZeroBytes(sum[:])
+	if readBits(data, entropyLen*8, checksumBits) != int(sum[0]>>(8-checksumBits)) {
+		return nil, bip39.ErrChecksumIncorrect
+	}
+
+	out, err := security.NewSecretBuffer(entropyLen)
+	if err != nil {
+		return nil, err
+	}
+	copy(out.Bytes(), data[:entropyLen])
+	return out, nil
+}
+
+// readBits returns n bits of data starting at bit offset, MSB first
+func readBits(data []byte, offset, n int) int {
+	v := 0
+	for i := offset; i < offset+n; i++ {
+		v = v<<1 | int(data[i/8]>>(7-uint(i%8))&1)
+	}
+	return v
+}
+
+// writeBits stores the low n bits of v at bit offset, MSB first
+func writeBits(data []byte, offset, n, v int) {
+	for i := 0; i < n; i++ {
+		if v>>(n-1-i)&1 == 1 {
+			pos := offset + i
+			data[pos/8] |= 1 << (7 - uint(pos%8))
+		}
+	}
+}
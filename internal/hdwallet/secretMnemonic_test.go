@@ -0,0 +1,132 @@
+package hdwallet
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/tyler-smith/go-bip39"
+)
+
+func TestMnemonicFromEntropy_MatchesBip39(t *testing.T) {
+	for _, size := range []int{16, 20, 24, 28, 32} {
+		for i := 0; i < 100; i++ {
+			entropy := make([]byte, size)
+			if _, err := rand.Read(entropy); err != nil {
+				t.Fatal(err)
+			}
+			want, err := bip39.NewMnemonic(entropy)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := mnemonicFromEntropy(entropy)
+			if err != nil {
+				t.Fatalf("mnemonicFromEntropy(%x) error: %v", entropy, err)
+			}
+			if !got.Equal([]byte(want)) {
+				t.Fatalf("mnemonicFromEntropy(%x) = %q, want %q", entropy, got.Bytes(), want)
+			}
+
+			decoded, err := entropyFromMnemonic(got.Bytes())
+			if err != nil {
+				t.Fatalf("entropyFromMnemonic(%q) error: %v", want, err)
+			}
+			if !decoded.Equal(entropy) {
+				t.Fatalf("round trip %x -> %x", entropy, decoded.Bytes())
+			}
+			got.Destroy()
+			decoded.Destroy()
+		}
+	}
+
+	if _, err := mnemonicFromEntropy(make([]byte, 15)); !errors.Is(err, bip39.ErrEntropyLengthInvalid) {
+		t.Errorf("mnemonicFromEntropy(15 bytes) error = %v, want %v", err, bip39.ErrEntropyLengthInvalid)
+	}
+}
+
+func TestEntropyFromMnemonic_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		mnemonic string
+		wantErr  error
+	}{
+		{"bad checksum", strings.Repeat("abandon ", 12), bip39.ErrChecksumIncorrect},
+		{"unknown word", strings.Repeat("abandon ", 11) + "gowallet", bip39.ErrInvalidMnemonic},
+		{"wrong count", strings.Repeat("abandon ", 11), bip39.ErrInvalidMnemonic},
+		{"empty", "", bip39.ErrInvalidMnemonic},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := entropyFromMnemonic([]byte(tt.mnemonic)); !errors.Is(err, tt.wantErr) {
+				t.Errorf("entropyFromMnemonic() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBip39MnemonicToSeed_MatchesBip39(t *testing.T) {
+	// Extra whitespace is accepted for validation but hashed as given, so
+	// seeds stay identical to bip39.NewSeed for every input
+	for _, mnemonic := range []string{
+		"close same tongue random ice cave aim input whale salute squirrel vivid",
+		"close  same tongue random ice cave aim input whale salute squirrel vivid\n",
+	} {
+		for _, password := range []string{"", "TREZOR"} {
+			seed, err := Bip39MnemonicToSeed(secretString(t, mnemonic), password)
+			if err != nil {
+				t.Fatalf("Bip39MnemonicToSeed(%q) error: %v", mnemonic, err)
+			}
+			if !seed.Equal(bip39.NewSeed(mnemonic, password)) {
+				t.Errorf("Bip39MnemonicToSeed(%q, %q) does not match bip39.NewSeed", mnemonic, password)
+			}
+			seed.Destroy()
+		}
+	}
+
+	if _, err := Bip39MnemonicToSeed(secretString(t, "close same tongue"), ""); err == nil {
+		t.Error("Bip39MnemonicToSeed() expected error for invalid mnemonic")
+	}
+}
+
+func TestKeyInfo_Destroy(t *testing.T) {
+	info, err := PathFromMnemonic(secretString(t, "close same tongue random ice cave aim input whale salute squirrel vivid"), "m/44'/60'/0'/0/0", MainNet)
+	if err != nil {
+		t.Fatalf("PathFromMnemonic() error: %v", err)
+	}
+
+	var b bytes.Buffer
+	if _, err := info.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != info.String() {
+		t.Errorf("WriteTo() = %q, String() = %q", b.String(), info.String())
+	}
+	b.Reset()
+	if _, err := info.WriteDetails(&b); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != info.Details() {
+		t.Errorf("WriteDetails() = %q, Details() = %q", b.String(), info.Details())
+	}
+	if got := fmt.Sprint(info.PrivateKey); got != "[secret]" {
+		t.Errorf("fmt.Sprint(PrivateKey) = %q, want redacted", got)
+	}
+
+	info.Destroy()
+	for name, secret := range map[string][]byte{
+		"PrivateKey":         info.PrivateKey.Bytes(),
+		"WIF":                info.WIF.Bytes(),
+		"ExtendedPrivateKey": info.ExtendedPrivateKey.Bytes(),
+	} {
+		if secret != nil {
+			t.Errorf("%s still readable after Destroy", name)
+		}
+	}
+	if got := info.String(); got != info.Address+":" {
+		t.Errorf("String() after Destroy = %q", got)
+	}
+}
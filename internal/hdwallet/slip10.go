@@ -0,0 +1,93 @@
+package hdwallet
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/spark8899/gowallet/internal/security"
+)
+
+// ErrNonHardenedPath indicates a non-hardened level in an ed25519 path.
+// SLIP-10 has no public parent to public child derivation for ed25519, so
+// every level must be hardened.
+var ErrNonHardenedPath = errors.New("ed25519 derivation requires every path level to be hardened")
+
+// slip10Key is a SLIP-10 ed25519 node: the 32-byte private key followed by
+// the 32-byte chain code, exactly as split from the HMAC-SHA512 output
+type slip10Key [64]byte
+
+// zeroSlip10Key wipes a node. It is a variable so tests can check that
+// every node in a derivation is wiped.
+var zeroSlip10Key = func(k *slip10Key) { security.ZeroBytes(k[:]) }
+
+func slip10Master(seed []byte) *slip10Key {
+	mac := hmac.New(sha512.New, []byte("ed25519 seed"))
+	mac.Write(seed)
+	var k slip10Key
+	mac.Sum(k[:0])
+	return &k
+}
+
+// child derives the hardened child index. The HMAC keeps its own copy of
+// the chain code, which cannot be wiped.
+func (k *slip10Key) child(index uint32) *slip10Key {
+	var data [1 + 32 + 4]byte
+	defer security.ZeroBytes(data[:])
+	copy(data[1:], k[:32])
+	binary.BigEndian.PutUint32(data[33:], index)
+
+	mac := hmac.New(sha512.New, k[32:])
+	mac.Write(data[:])
+	var c slip10Key
+	mac.Sum(c[:0])
+	return &c
+}
+
+// ValidateEd25519Path checks pathStr like ValidateDerivationPath and also
+// requires every level to be hardened
+func ValidateEd25519Path(pathStr string) error {
+	if err := ValidateDerivationPath(pathStr); err != nil {
+		return err
+	}
+	path, err := accounts.ParseDerivationPath(pathStr)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidPathFormat, err)
+	}
+	for _, n := range path {
+		if n < hdkeychain.HardenedKeyStart {
+			return fmt.Errorf("%w: level %d of %s", ErrNonHardenedPath, n, pathStr)
+		}
+	}
+	return nil
+}
+
+// deriveEd25519 walks pathStr from the SLIP-10 ed25519 master key of seed
+// and returns the 32-byte private key. Every intermediate node is wiped.
+func deriveEd25519(seed []byte, pathStr string) (*security.SecretBuffer, error) {
+	if err := ValidateEd25519Path(pathStr); err != nil {
+		return nil, fmt.Errorf("invalid derivation path: %w", err)
+	}
+	path, err := accounts.ParseDerivationPath(pathStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse derivation path: %w", err)
+	}
+
+	key := slip10Master(seed)
+	defer func() { zeroSlip10Key(key) }()
+	for _, n := range path {
+		child := key.child(n)
+		zeroSlip10Key(key)
+		key = child
+	}
+	privateKey, err := security.NewSecretBuffer(32)
+	if err != nil {
+		return nil, err
+	}
+	copy(privateKey.Bytes(), key[:32])
+	return privateKey, nil
+}
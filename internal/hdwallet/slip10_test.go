@@ -0,0 +1,89 @@
+package hdwallet
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestSlip10Ed25519Vectors(t *testing.T) {
+	// SLIP-10 test vector 1 for ed25519
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	tests := []struct {
+		path, chainCode, privateKey, publicKey string
+	}{
+		{"m", "90046a93de5380a72b5e45010748567d5ea02bbf6522f979e05c0d8d8ca9fffb", "2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7", "a4b2856bfec510abab89753fac1ac0e1112364e7d250545963f135f2a33188ed"},
+		{"m/0'", "8b59aa11380b624e81507a27fedda59fea6d0b779a778918a2fd3590e16e9c69", "68e0fe46dfb67e368c75379acec591dad19df3cde26e63b93a8e704f1dade7a3", "8c8a13df77a28f3445213a0f432fde644acaa215fc72dcdf300d5efaa85d350c"},
+		{"m/0'/1'", "a320425f77d1b5c2505a6b1b27382b37368ee640e3557c315416801243552f14", "b1d0bad404bf35da785a64ca1ac54b2617211d2777696fbffaf208f746ae84f2", "1932a5270f335bed617d5b935c80aedb1a35bd9fc1e31acafd5372c30f5c1187"},
+		{"m/0'/1'/2'", "2e69929e00b5ab250f49c3fb1c12f252de4fed2c1db88387094a0f8c4c9ccd6c", "92a5b23c0b8a99e37d07df3fb9966917f5d06e02ddbd909c7e184371463e9fc9", "ae98736566d30ed0e9d2f4486a64bc95740d89c7db33f52121f8ea8f76ff0fc1"},
+	}
+	for _, tt := range tests {
+		key := slip10Master(seed)
+		if tt.path != "m" {
+			for _, n := range map[string][]uint32{
+				"m/0'":       {0x80000000},
+				"m/0'/1'":    {0x80000000, 0x80000001},
+				"m/0'/1'/2'": {0x80000000, 0x80000001, 0x80000002},
+			}[tt.path] {
+				key = key.child(n)
+			}
+		}
+		if got := hex.EncodeToString(key[32:]); got != tt.chainCode {
+			t.Errorf("%s chain code = %s, want %s", tt.path, got, tt.chainCode)
+		}
+		if got := hex.EncodeToString(key[:32]); got != tt.privateKey {
+			t.Errorf("%s private key = %s, want %s", tt.path, got, tt.privateKey)
+		}
+		pub := ed25519.NewKeyFromSeed(key[:32]).Public().(ed25519.PublicKey)
+		if got := hex.EncodeToString(pub); got != tt.publicKey {
+			t.Errorf("%s public key = %s, want %s", tt.path, got, tt.publicKey)
+		}
+	}
+}
+
+func TestValidateEd25519Path(t *testing.T) {
+	tests := []struct {
+		path string
+		want error
+	}{
+		{"m/44'/501'/0'/0'", nil},
+		{"m/44'/148'/0'", nil},
+		{"m/44'/501'/0'/0", ErrNonHardenedPath},
+		{"m/44'/501'/0/0'", ErrNonHardenedPath},
+		{"m/45'/501'/0'", ErrInvalidPurpose},
+		{"m/44'/501'/x'", ErrInvalidPathFormat},
+	}
+	for _, tt := range tests {
+		if err := ValidateEd25519Path(tt.path); !errors.Is(err, tt.want) {
+			t.Errorf("ValidateEd25519Path(%q) = %v, want %v", tt.path, err, tt.want)
+		}
+	}
+}
+
+func TestDeriveEd25519_WipesIntermediateKeys(t *testing.T) {
+	var wiped []*slip10Key
+	orig := zeroSlip10Key
+	zeroSlip10Key = func(k *slip10Key) {
+		wiped = append(wiped, k)
+		orig(k)
+	}
+	t.Cleanup(func() { zeroSlip10Key = orig })
+
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	key, err := deriveEd25519(seed, "m/44'/501'/0'/0'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer key.Destroy()
+
+	// The master node and one node per path level
+	if len(wiped) != 5 {
+		t.Fatalf("wiped %d nodes, want 5", len(wiped))
+	}
+	for i, k := range wiped {
+		if *k != (slip10Key{}) {
+			t.Errorf("node %d not wiped", i)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package hdwallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil/base58"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TronAddressPrefix is the version byte of Tron addresses; it makes every
+// base58 address start with "T" and every hex address with "41"
+const TronAddressPrefix = 0x41
+
+// ErrInvalidTronAddress indicates a malformed Tron address or a bad checksum
+var ErrInvalidTronAddress = errors.New("invalid Tron address")
+
+// TronAddress encodes the 20-byte EVM address of a key (the same Keccak-256
+// hash of the public key) as a base58check Tron address
+func TronAddress(addr common.Address) string {
+	return base58.CheckEncode(addr.Bytes(), TronAddressPrefix)
+}
+
+// DecodeTronAddress parses a base58 ("T...") or hex ("41...") Tron address
+// and returns the underlying 20-byte address
+func DecodeTronAddress(s string) (common.Address, error) {
+	if strings.HasPrefix(s, "T") {
+		payload, version, err := base58.CheckDecode(s)
+		if err != nil {
+			return common.Address{}, fmt.Errorf("%w: %w", ErrInvalidTronAddress, err)
+		}
+		if version != TronAddressPrefix || len(payload) != common.AddressLength {
+			return common.Address{}, fmt.Errorf("%w: wrong version or length", ErrInvalidTronAddress)
+		}
+		return common.BytesToAddress(payload), nil
+	}
+
+	raw, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil || len(raw) != 1+common.AddressLength || raw[0] != TronAddressPrefix {
+		return common.Address{}, fmt.Errorf("%w: expected T... base58 or 41... hex", ErrInvalidTronAddress)
+	}
+	return common.BytesToAddress(raw[1:]), nil
+}
+
+// TronToHex converts a Tron address to its 21-byte hex form ("41...")
+func TronToHex(s string) (string, error) {
+	addr, err := DecodeTronAddress(s)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%02x%x", TronAddressPrefix, addr.Bytes()), nil
+}
+
+// TronFromHex converts a hex Tron address ("41...") or a 0x EVM address to
+// the base58 form
+func TronFromHex(s string) (string, error) {
+	if common.IsHexAddress(s) {
+		return TronAddress(common.HexToAddress(s)), nil
+	}
+	addr, err := DecodeTronAddress(s)
+	if err != nil {
+		return "", err
+	}
+	return TronAddress(addr), nil
+}
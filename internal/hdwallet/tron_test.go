@@ -0,0 +1,44 @@
+package hdwallet
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestTronAddress(t *testing.T) {
+	// Example from the Tron developer documentation
+	const base58Addr = "TNPeeaaFB7K9cmo4uQpcU32zGK8G1NYqeL"
+	const hexAddr = "418840e6c55b9ada326d211d818c34a994aeced808"
+	evm := common.HexToAddress("0x8840E6C55B9ADA326D211D818C34A994AECED808")
+
+	if got := TronAddress(evm); got != base58Addr {
+		t.Errorf("TronAddress() = %s, want %s", got, base58Addr)
+	}
+	if got, err := TronToHex(base58Addr); err != nil || got != hexAddr {
+		t.Errorf("TronToHex() = %s, %v, want %s", got, err, hexAddr)
+	}
+	for _, in := range []string{hexAddr, "0x" + hexAddr, "418840E6C55B9ADA326D211D818C34A994AECED808", evm.Hex()} {
+		if got, err := TronFromHex(in); err != nil || got != base58Addr {
+			t.Errorf("TronFromHex(%s) = %s, %v, want %s", in, got, err, base58Addr)
+		}
+	}
+	if got, err := DecodeTronAddress(base58Addr); err != nil || got != evm {
+		t.Errorf("DecodeTronAddress() = %s, %v", got, err)
+	}
+}
+
+func TestDecodeTronAddress_Invalid(t *testing.T) {
+	for _, in := range []string{
+		"TNPeeaaFB7K9cmo4uQpcU32zGK8G1NYqeM",         // checksum
+		"1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2",         // Bitcoin version byte
+		"428840e6c55b9ada326d211d818c34a994aeced808", // wrong hex prefix
+		"418840e6c55b9ada326d211d818c34a994aeced8",   // short
+		"",
+	} {
+		if _, err := DecodeTronAddress(in); !errors.Is(err, ErrInvalidTronAddress) {
+			t.Errorf("DecodeTronAddress(%q) error = %v, want ErrInvalidTronAddress", in, err)
+		}
+	}
+}
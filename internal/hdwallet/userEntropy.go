@@ -0,0 +1,158 @@
+package hdwallet
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/spark8899/gowallet/internal/security"
+)
+
+var (
+	// ErrInvalidDiceRoll indicates a character other than 1-6 in dice input
+	ErrInvalidDiceRoll = errors.New("dice rolls must only contain digits 1-6")
+	// ErrInvalidCoinFlip indicates a character other than 0/1/H/T in coin input
+	ErrInvalidCoinFlip = errors.New("coin flips must only contain 0/1 or H/T")
+	// ErrNotEnoughEntropy indicates the user input carries fewer bits than requested
+	ErrNotEnoughEntropy = errors.New("not enough user entropy")
+	// ErrInvalidEntropyLength indicates entropy that is not 16-32 bytes in steps of 4
+	ErrInvalidEntropyLength = errors.New("entropy must be 16, 20, 24, 28 or 32 bytes")
+)
+
+// bitsPerDiceRoll is the entropy of one fair six-sided die
+var bitsPerDiceRoll = math.Log2(6)
+
+// MinDiceRolls returns the number of fair dice rolls needed for bits of entropy.
+// This is 50 rolls for 128 bits and 100 for 256 bits (Coldcard asks for 99,
+// which is 255.9 bits).
+func MinDiceRolls(bits int) int {
+	return int(math.Ceil(float64(bits) / bitsPerDiceRoll))
+}
+
+// DiceEntropy converts dice rolls to bits of entropy using the Coldcard
+// method: SHA256 over the ASCII digits (whitespace removed), truncated to
+// bits/8 bytes. The same result is obtained by hashing the rolls with
+// `echo -n 123456... | sha256sum` and pasting the hex into Ian Coleman's
+// BIP39 tool as raw entropy.
+//
+// It returns the entropy and the number of bits the rolls contribute.
+func DiceEntropy(rolls string, bits int) ([]byte, float64, error) {
+	if err := validateEntropyBits(bits); err != nil {
+		return nil, 0, err
+	}
+
+	digits := stripSpace(rolls)
+	for _, c := range digits {
+		if c < '1' || c > '6' {
+			return nil, 0, fmt.Errorf("%w: got %q", ErrInvalidDiceRoll, c)
+		}
+	}
+
+	contributed := float64(len(digits)) * bitsPerDiceRoll
+	if len(digits) < MinDiceRolls(bits) {
+		return nil, contributed, fmt.Errorf("%w: %d rolls give %.1f bits, need %d rolls for %d bits",
+			ErrNotEnoughEntropy, len(digits), contributed, MinDiceRolls(bits), bits)
+	}
+
+	sum := sha256.Sum256([]byte(digits))
+	defer security.ZeroBytes(sum[:])
+
+	entropy := make([]byte, bits/8)
+	copy(entropy, sum[:])
+	return entropy, contributed, nil
+}
+
+// CoinEntropy converts coin flips (0/1 or H/T, H=1) directly to entropy bits.
+// Exactly the first bits flips are used; extra flips are ignored.
+func CoinEntropy(flips string, bits int) ([]byte, float64, error) {
+	if err := validateEntropyBits(bits); err != nil {
+		return nil, 0, err
+	}
+
+	values := stripSpace(flips)
+	if len(values) < bits {
+		return nil, float64(len(values)), fmt.Errorf("%w: %d flips give %d bits, need %d",
+			ErrNotEnoughEntropy, len(values), len(values), bits)
+	}
+
+	entropy := make([]byte, bits/8)
+	for i, c := range values[:bits] {
+		var bit byte
+		switch unicode.ToUpper(c) {
+		case '1', 'H':
+			bit = 1
+		case '0', 'T':
+			bit = 0
+		default:
+			security.ZeroBytes(entropy)
+			return nil, 0, fmt.Errorf("%w: got %q", ErrInvalidCoinFlip, c)
+		}
+		entropy[i/8] |= bit << (7 - uint(i%8))
+	}
+	return entropy, float64(bits), nil
+}
+
+// HexEntropy decodes hex entropy of 16-32 bytes (multiple of 4)
+func HexEntropy(entropyHex string) ([]byte, float64, error) {
+	entropy, err := hex.DecodeString(strings.TrimPrefix(stripSpace(entropyHex), "0x"))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to decode entropy hex: %w", err)
+	}
+	if err := validateEntropyBits(len(entropy) * 8); err != nil {
+		security.ZeroBytes(entropy)
+		return nil, 0, err
+	}
+	return entropy, float64(len(entropy) * 8), nil
+}
+
+// MnemonicFromUserEntropy turns user supplied entropy into a BIP39 mnemonic.
+//
+// If mix is not nil, the same number of bytes is read from it and XORed into
+// the entropy, so the result is at least as strong as the stronger of the two
+// sources. The combined entropy must pass ValidateEntropy. entropy is wiped
+// before returning; the caller must Destroy the returned mnemonic.
+func MnemonicFromUserEntropy(entropy []byte, mix io.Reader) (*security.SecretBuffer, error) {
+	defer security.ZeroBytes(entropy)
+
+	if err := validateEntropyBits(len(entropy) * 8); err != nil {
+		return nil, err
+	}
+
+	if mix != nil {
+		pad := make([]byte, len(entropy))
+		defer security.ZeroBytes(pad)
+		if _, err := io.ReadFull(mix, pad); err != nil {
+			return nil, fmt.Errorf("failed to read random entropy: %w", err)
+		}
+		for i := range entropy {
+			entropy[i] ^= pad[i]
+		}
+	}
+
+	if err := ValidateEntropy(entropy); err != nil {
+		return nil, fmt.Errorf("entropy validation failed: %w", err)
+	}
+
+	return mnemonicFromEntropy(entropy)
+}
+
+func validateEntropyBits(bits int) error {
+	if bits%32 != 0 || bits < 128 || bits > 256 {
+		return fmt.Errorf("%w: got %d bits", ErrInvalidEntropyLength, bits)
+	}
+	return nil
+}
+
+func stripSpace(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,185 @@
+package hdwallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tyler-smith/go-bip39"
+)
+
+func TestMinDiceRolls(t *testing.T) {
+	tests := []struct {
+		bits int
+		want int
+	}{
+		{128, 50},
+		{160, 62},
+		{256, 100},
+	}
+	for _, tt := range tests {
+		if got := MinDiceRolls(tt.bits); got != tt.want {
+			t.Errorf("MinDiceRolls(%d) = %d, want %d", tt.bits, got, tt.want)
+		}
+	}
+}
+
+func TestDiceEntropy(t *testing.T) {
+	// Expected values are `echo -n <rolls> | sha256sum`, truncated
+	tests := []struct {
+		name    string
+		rolls   string
+		bits    int
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "128 bits from 54 rolls",
+			rolls: strings.Repeat("123456", 9),
+			bits:  128,
+			want:  "edceb2d86ed94b3b67b707e8721ca00f",
+		},
+		{
+			name:  "whitespace is ignored",
+			rolls: strings.Repeat("123 456\n", 9),
+			bits:  128,
+			want:  "edceb2d86ed94b3b67b707e8721ca00f",
+		},
+		{
+			name:  "256 bits from 100 rolls",
+			rolls: strings.Repeat("6", 100),
+			bits:  256,
+			want:  "495bd978198b68c4b68900f20bf8662f505fb07c7bdc4da8fc1094cc8f021305",
+		},
+		{
+			name:    "not enough rolls",
+			rolls:   strings.Repeat("1", 49),
+			bits:    128,
+			wantErr: ErrNotEnoughEntropy,
+		},
+		{
+			name:    "invalid digit",
+			rolls:   strings.Repeat("7", 50),
+			bits:    128,
+			wantErr: ErrInvalidDiceRoll,
+		},
+		{
+			name:    "invalid size",
+			rolls:   strings.Repeat("1", 100),
+			bits:    100,
+			wantErr: ErrInvalidEntropyLength,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entropy, contributed, err := DiceEntropy(tt.rolls, tt.bits)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("DiceEntropy() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DiceEntropy() error: %v", err)
+			}
+			if got := hex.EncodeToString(entropy); got != tt.want {
+				t.Errorf("DiceEntropy() = %s, want %s", got, tt.want)
+			}
+			if contributed < float64(tt.bits)-3 {
+				t.Errorf("DiceEntropy() contributed %.1f bits, want about %d", contributed, tt.bits)
+			}
+		})
+	}
+}
+
+func TestCoinEntropy(t *testing.T) {
+	flips := strings.Repeat("HTTHHTHT", 16) // 0x9a repeated
+	entropy, bits, err := CoinEntropy(flips, 128)
+	if err != nil {
+		t.Fatalf("CoinEntropy() error: %v", err)
+	}
+	if !bytes.Equal(entropy, bytes.Repeat([]byte{0x9a}, 16)) {
+		t.Errorf("CoinEntropy() = %x", entropy)
+	}
+	if bits != 128 {
+		t.Errorf("CoinEntropy() bits = %v, want 128", bits)
+	}
+
+	binary, _, err := CoinEntropy(strings.Repeat("10011010", 16), 128)
+	if err != nil || !bytes.Equal(binary, entropy) {
+		t.Errorf("CoinEntropy() 0/1 form = %x, %v; want same as H/T form", binary, err)
+	}
+
+	if _, _, err := CoinEntropy(strings.Repeat("1", 127), 128); !errors.Is(err, ErrNotEnoughEntropy) {
+		t.Errorf("CoinEntropy() error = %v, want %v", err, ErrNotEnoughEntropy)
+	}
+	if _, _, err := CoinEntropy(strings.Repeat("X", 128), 128); !errors.Is(err, ErrInvalidCoinFlip) {
+		t.Errorf("CoinEntropy() error = %v, want %v", err, ErrInvalidCoinFlip)
+	}
+}
+
+func TestHexEntropy(t *testing.T) {
+	entropy, bits, err := HexEntropy("0x7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f")
+	if err != nil {
+		t.Fatalf("HexEntropy() error: %v", err)
+	}
+	if bits != 128 || len(entropy) != 16 {
+		t.Errorf("HexEntropy() = %x (%v bits)", entropy, bits)
+	}
+
+	if _, _, err := HexEntropy("00112233"); !errors.Is(err, ErrInvalidEntropyLength) {
+		t.Errorf("HexEntropy() error = %v, want %v", err, ErrInvalidEntropyLength)
+	}
+	if _, _, err := HexEntropy("zz"); err == nil {
+		t.Error("HexEntropy() expected error for invalid hex")
+	}
+}
+
+func TestMnemonicFromUserEntropy(t *testing.T) {
+	entropy, _, err := DiceEntropy(strings.Repeat("123456", 9), 128)
+	if err != nil {
+		t.Fatalf("DiceEntropy() error: %v", err)
+	}
+	want, _ := bip39.NewMnemonic(append([]byte(nil), entropy...))
+
+	mnemonic, err := MnemonicFromUserEntropy(entropy, nil)
+	if err != nil {
+		t.Fatalf("MnemonicFromUserEntropy() error: %v", err)
+	}
+	defer mnemonic.Destroy()
+	if got := string(mnemonic.Bytes()); got != want {
+		t.Errorf("MnemonicFromUserEntropy() = %q, want %q", got, want)
+	}
+	if !bytes.Equal(entropy, make([]byte, len(entropy))) {
+		t.Error("MnemonicFromUserEntropy() did not wipe the entropy")
+	}
+}
+
+func TestMnemonicFromUserEntropy_XOR(t *testing.T) {
+	entropy, _ := hex.DecodeString("edceb2d86ed94b3b67b707e8721ca00f")
+	pad := bytes.Repeat([]byte{0xff}, 16)
+
+	inverted := make([]byte, 16)
+	for i := range entropy {
+		inverted[i] = entropy[i] ^ 0xff
+	}
+	want, _ := bip39.NewMnemonic(inverted)
+
+	got, err := MnemonicFromUserEntropy(entropy, bytes.NewReader(pad))
+	if err != nil {
+		t.Fatalf("MnemonicFromUserEntropy() error: %v", err)
+	}
+	defer got.Destroy()
+	if !got.Equal([]byte(want)) {
+		t.Errorf("MnemonicFromUserEntropy() XOR = %q, want %q", got.Bytes(), want)
+	}
+}
+
+func TestMnemonicFromUserEntropy_RejectsWeak(t *testing.T) {
+	if _, err := MnemonicFromUserEntropy(make([]byte, 16), nil); !errors.Is(err, ErrEntropyAllZeros) {
+		t.Errorf("MnemonicFromUserEntropy() error = %v, want %v", err, ErrEntropyAllZeros)
+	}
+}
@@ -0,0 +1,349 @@
+package hdwallet
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/base58"
+	"github.com/btcsuite/btcd/btcutil/bech32"
+	"github.com/spark8899/gowallet/internal/schnorr"
+	"github.com/spark8899/gowallet/internal/security"
+)
+
+var (
+	// ErrUnsupportedAddressType indicates an address type the chain does not use
+	ErrUnsupportedAddressType = errors.New("unsupported address type")
+	// ErrInvalidAddress indicates an address that fails to decode or whose
+	// checksum does not match
+	ErrInvalidAddress = errors.New("invalid address")
+	// ErrWrongNetwork indicates a well-formed address of another network
+	ErrWrongNetwork = errors.New("address is for a different network")
+)
+
+// AddressType is the output script an address pays to
+type AddressType string
+
+const (
+	P2PKH AddressType = "p2pkh"
+	// P2SH is pay-to-script-hash; addresses derived from a public key use it
+	// for BIP49 nested SegWit (P2SH-P2WPKH)
+	P2SH   AddressType = "p2sh"
+	P2WPKH AddressType = "p2wpkh"
+	P2WSH  AddressType = "p2wsh"
+)
+
+// purpose is the BIP44/49/84/86 derivation purpose of a single-key address
+// type
+var purpose = map[AddressType]string{P2PKH: "44'", P2SH: "49'", P2WPKH: "84'", P2TR: "86'"}
+
+// UTXOParams holds the address and WIF version bytes of a Bitcoin-derived
+// chain, so callers never pass magic version bytes around
+type UTXOParams struct {
+	PubKeyHashAddrID byte
+	ScriptHashAddrID byte
+	// LegacyScriptHashAddrIDs are older P2SH version bytes still accepted
+	// when decoding (Litecoin's "3" addresses)
+	LegacyScriptHashAddrIDs []byte
+	PrivateKeyID            byte   // WIF version byte
+	Bech32HRP               string // empty without SegWit
+	CashAddrPrefix          string // set for Bitcoin Cash
+	AddressTypes            []AddressType
+	DefaultType             AddressType
+}
+
+var (
+	// Litecoin defaults to native SegWit (ltc1) at the BIP84 path
+	Litecoin = NewUTXOChain("litecoin", 2, UTXOParams{
+		PubKeyHashAddrID:        0x30, // L
+		ScriptHashAddrID:        0x32, // M
+		LegacyScriptHashAddrIDs: []byte{0x05},
+		PrivateKeyID:            0xb0,
+		Bech32HRP:               "ltc",
+		AddressTypes:            []AddressType{P2PKH, P2SH, P2WPKH},
+		DefaultType:             P2WPKH,
+	})
+	// Dogecoin has no SegWit
+	Dogecoin = NewUTXOChain("dogecoin", 3, UTXOParams{
+		PubKeyHashAddrID: 0x1e, // D
+		ScriptHashAddrID: 0x16, // 9 or A
+		PrivateKeyID:     0x9e,
+		AddressTypes:     []AddressType{P2PKH},
+		DefaultType:      P2PKH,
+	})
+	// Dash has no SegWit
+	Dash = NewUTXOChain("dash", 5, UTXOParams{
+		PubKeyHashAddrID: 0x4c, // X
+		ScriptHashAddrID: 0x10, // 7
+		PrivateKeyID:     0xcc,
+		AddressTypes:     []AddressType{P2PKH},
+		DefaultType:      P2PKH,
+	})
+	// BitcoinCash encodes addresses as CashAddr; legacy Bitcoin-style
+	// addresses are still accepted when decoding
+	BitcoinCash = NewUTXOChain("bitcoincash", 145, UTXOParams{
+		PubKeyHashAddrID: 0x00,
+		ScriptHashAddrID: 0x05,
+		PrivateKeyID:     0x80,
+		CashAddrPrefix:   "bitcoincash",
+		AddressTypes:     []AddressType{P2PKH},
+		DefaultType:      P2PKH,
+	})
+)
+
+// BitcoinChain returns Bitcoin on net, defaulting to native SegWit at the
+// BIP84 path; Taproot (BIP86) is available through WithAddressType. Unlike
+// the other presets it follows the network's version bytes, so testnet keys
+// give tb1 addresses and "c" WIF keys.
+func BitcoinChain(net *Network) *Chain {
+	return NewUTXOChain("bitcoin", net.Params.HDCoinType, UTXOParams{
+		PubKeyHashAddrID: net.Params.PubKeyHashAddrID,
+		ScriptHashAddrID: net.Params.ScriptHashAddrID,
+		PrivateKeyID:     net.Params.PrivateKeyID,
+		Bech32HRP:        net.Params.Bech32HRPSegwit,
+		AddressTypes:     []AddressType{P2PKH, P2SH, P2WPKH, P2TR},
+		DefaultType:      P2WPKH,
+	})
+}
+
+// ChainForNetwork looks up a secp256k1 chain by name like ChainByName, and
+// also accepts "bitcoin", built for net
+func ChainForNetwork(name string, net *Network) (*Chain, error) {
+	if strings.EqualFold(name, "bitcoin") {
+		return BitcoinChain(net), nil
+	}
+	return ChainByName(name)
+}
+
+// ChainForWIF returns the chain whose WIF version byte is version, trying
+// Bitcoin on net before the other networks. Bitcoin Cash shares Bitcoin's
+// byte, so its keys come back as Bitcoin.
+func ChainForWIF(version byte, net *Network) (*Chain, error) {
+	for _, n := range append([]*Network{net}, Networks...) {
+		if n.Params.PrivateKeyID == version {
+			return BitcoinChain(n), nil
+		}
+	}
+	for _, c := range Chains {
+		if c.UTXO != nil && c.UTXO.PrivateKeyID == version {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: WIF version byte 0x%02x", ErrUnknownChain, version)
+}
+
+// NewUTXOChain returns a Bitcoin-derived chain encoding params.DefaultType
+// addresses, derived at the matching BIP44/49/84/86 path. Its private keys
+// are compressed WIF.
+func NewUTXOChain(name string, coinType uint32, params UTXOParams) *Chain {
+	return &Chain{
+		Name:        name,
+		CoinType:    coinType,
+		DefaultPath: fmt.Sprintf("m/%s/%d'/0'/0/0", purpose[params.DefaultType], coinType),
+		Address: func(pub *btcec.PublicKey) string {
+			address, err := params.Address(pub, params.DefaultType)
+			if err != nil {
+				// only reachable with a DefaultType the chain lacks, which
+				// WithAddressType rejects
+				panic(err)
+			}
+			return address
+		},
+		UTXO: &params,
+		privateKey: func(raw []byte) (*security.SecretBuffer, error) {
+			return params.WIF(raw)
+		},
+	}
+}
+
+// WithAddressType returns a copy of a UTXO chain that encodes addresses of
+// type t, with the default path moved to the matching BIP44/49/84/86 purpose
+func (c *Chain) WithAddressType(t AddressType) (*Chain, error) {
+	if c.UTXO == nil {
+		return nil, fmt.Errorf("%w: %s has no address types", ErrUnsupportedAddressType, c.Name)
+	}
+	if !c.UTXO.supports(t) {
+		return nil, fmt.Errorf("%w: %s on %s", ErrUnsupportedAddressType, t, c.Name)
+	}
+	params := *c.UTXO
+	params.DefaultType = t
+	return NewUTXOChain(c.Name, c.CoinType, params), nil
+}
+
+func (p *UTXOParams) supports(t AddressType) bool {
+	for _, s := range p.AddressTypes {
+		if s == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Address encodes a public key as an address of type t
+func (p *UTXOParams) Address(pub *btcec.PublicKey, t AddressType) (string, error) {
+	if !p.supports(t) {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedAddressType, t)
+	}
+	keyHash := btcutil.Hash160(pub.SerializeCompressed())
+	switch t {
+	case P2PKH:
+		if p.CashAddrPrefix != "" {
+			return EncodeCashAddr(p.CashAddrPrefix, P2PKH, keyHash)
+		}
+		return base58.CheckEncode(keyHash, p.PubKeyHashAddrID), nil
+	case P2SH:
+		// OP_0 <20-byte key hash>
+		redeemScript := append([]byte{0x00, 0x14}, keyHash...)
+		return base58.CheckEncode(btcutil.Hash160(redeemScript), p.ScriptHashAddrID), nil
+	case P2WPKH:
+		return encodeSegWit(p.Bech32HRP, 0, keyHash)
+	case P2TR:
+		// BIP86: no script tree
+		return p.TaprootAddress(pub, nil)
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnsupportedAddressType, t)
+}
+
+// TaprootAddress encodes the P2TR address of an internal key tweaked by a
+// script tree's merkle root (BIP341), or by nothing if merkleRoot is nil
+func (p *UTXOParams) TaprootAddress(internal *btcec.PublicKey, merkleRoot []byte) (string, error) {
+	if !p.supports(P2TR) {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedAddressType, P2TR)
+	}
+	output, err := schnorr.TweakPublicKey(internal, merkleRoot)
+	if err != nil {
+		return "", err
+	}
+	return encodeSegWit(p.Bech32HRP, 1, schnorr.XOnlyPublicKey(output))
+}
+
+// UncompressedAddress encodes the P2PKH address of the uncompressed public
+// key, the address of an uncompressed WIF key. SegWit only allows
+// compressed keys, so every other type fails.
+func (p *UTXOParams) UncompressedAddress(pub *btcec.PublicKey, t AddressType) (string, error) {
+	if t != P2PKH || !p.supports(P2PKH) {
+		return "", fmt.Errorf("%w: %s with an uncompressed key", ErrUnsupportedAddressType, t)
+	}
+	keyHash := btcutil.Hash160(pub.SerializeUncompressed())
+	if p.CashAddrPrefix != "" {
+		return EncodeCashAddr(p.CashAddrPrefix, P2PKH, keyHash)
+	}
+	return base58.CheckEncode(keyHash, p.PubKeyHashAddrID), nil
+}
+
+// WIF encodes a 32-byte private key in compressed Wallet Import Format
+func (p *UTXOParams) WIF(raw []byte) (*security.SecretBuffer, error) {
+	payload, err := security.NewSecretBuffer(len(raw) + 1)
+	if err != nil {
+		return nil, err
+	}
+	defer payload.Destroy()
+	copy(payload.Bytes(), raw)
+	payload.Bytes()[len(raw)] = 0x01 // compressed public key
+	return security.NewSecretBufferFromString(base58.CheckEncode(payload.Bytes(), p.PrivateKeyID))
+}
+
+// DecodeAddress checks that address belongs to this chain and returns its
+// type and hash. Well-formed addresses of other networks fail with
+// ErrWrongNetwork, malformed ones with ErrInvalidAddress.
+func (p *UTXOParams) DecodeAddress(address string) (AddressType, []byte, error) {
+	if p.CashAddrPrefix != "" {
+		prefix, t, hash, err := DecodeCashAddr(address, p.CashAddrPrefix)
+		switch {
+		case err == nil && prefix != p.CashAddrPrefix:
+			return "", nil, fmt.Errorf("%w: prefix %q, want %q", ErrWrongNetwork, prefix, p.CashAddrPrefix)
+		case err == nil:
+			return t, hash, nil
+		case strings.Contains(address, ":") || !isBase58(address):
+			return "", nil, fmt.Errorf("%w: %v", ErrInvalidAddress, err)
+		}
+		// otherwise try the legacy format below
+	}
+
+	if p.Bech32HRP != "" && strings.HasPrefix(strings.ToLower(address), p.Bech32HRP+"1") {
+		return decodeSegWit(address)
+	}
+	if hrp, _, err := bech32.DecodeNoLimit(address); err == nil {
+		return "", nil, fmt.Errorf("%w: bech32 prefix %q", ErrWrongNetwork, hrp)
+	}
+
+	hash, version, err := base58.CheckDecode(address)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %v", ErrInvalidAddress, err)
+	}
+	if len(hash) != 20 {
+		return "", nil, fmt.Errorf("%w: %d-byte hash", ErrInvalidAddress, len(hash))
+	}
+	switch {
+	case version == p.PubKeyHashAddrID:
+		return P2PKH, hash, nil
+	case version == p.ScriptHashAddrID:
+		return P2SH, hash, nil
+	}
+	for _, v := range p.LegacyScriptHashAddrIDs {
+		if version == v {
+			return P2SH, hash, nil
+		}
+	}
+	return "", nil, fmt.Errorf("%w: version byte 0x%02x", ErrWrongNetwork, version)
+}
+
+// ValidateAddress reports whether address is a valid address of this chain
+func (p *UTXOParams) ValidateAddress(address string) error {
+	_, _, err := p.DecodeAddress(address)
+	return err
+}
+
+// encodeSegWit encodes a witness program, version 0 in bech32 and later
+// versions in bech32m (BIP350)
+func encodeSegWit(hrp string, version byte, program []byte) (string, error) {
+	words, err := bech32.ConvertBits(program, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	if version == 0 {
+		return bech32.Encode(hrp, append([]byte{version}, words...))
+	}
+	return bech32.EncodeM(hrp, append([]byte{version}, words...))
+}
+
+// decodeSegWit decodes a version 0 or version 1 (Taproot) witness program
+// address
+func decodeSegWit(address string) (AddressType, []byte, error) {
+	_, data, encoding, err := bech32.DecodeGeneric(address)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %v", ErrInvalidAddress, err)
+	}
+	if len(data) == 0 || data[0] > 1 {
+		return "", nil, fmt.Errorf("%w: unsupported witness version", ErrInvalidAddress)
+	}
+	if (data[0] == 0) != (encoding == bech32.Version0) {
+		return "", nil, fmt.Errorf("%w: witness version %d with the wrong checksum", ErrInvalidAddress, data[0])
+	}
+	program, err := bech32.ConvertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %v", ErrInvalidAddress, err)
+	}
+	switch {
+	case data[0] == 0 && len(program) == 20:
+		return P2WPKH, program, nil
+	case data[0] == 0 && len(program) == 32:
+		return P2WSH, program, nil
+	case data[0] == 1 && len(program) == 32:
+		return P2TR, program, nil
+	}
+	return "", nil, fmt.Errorf("%w: %d-byte version %d witness program", ErrInvalidAddress, len(program), data[0])
+}
+
+func isBase58(s string) bool {
+	for _, c := range s {
+		if !strings.ContainsRune(base58Alphabet, c) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
@@ -0,0 +1,189 @@
+package hdwallet
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestUTXOChainPathFromMnemonic(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	litecoinLegacy, err := Litecoin.WithAddressType(P2PKH)
+	if err != nil {
+		t.Fatal(err)
+	}
+	litecoinNested, err := Litecoin.WithAddressType(P2SH)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		chain   *Chain
+		path    string
+		address string
+		wif     string
+	}{
+		// first receive address of each account type
+		{Litecoin, "m/84'/2'/0'/0/0", "ltc1qjmxnz78nmc8nq77wuxh25n2es7rzm5c2rkk4wh", "T5ZCYhLqXu6EJKk2nhjvwsaLH357CisixhLGWpKXEiqWTUtzte6o"},
+		{litecoinLegacy, "m/44'/2'/0'/0/0", "LUWPbpM43E2p7ZSh8cyTBEkvpHmr3cB8Ez", "T5b4RiWRs7XG8xZ2bCHBoJcn4JrpMTbGRFYXgoZHd7nD8izwqhMK"},
+		{litecoinNested, "m/49'/2'/0'/0/0", "M7wtsL7wSHDBJVMWWhtQfTMSYYkyooAAXM", "T8xSEcthDYN4rNUu4eTqtZTDSvphsjgBNbKawBeCkUqZLZ9MH8Ff"},
+		{Dogecoin, "m/44'/3'/0'/0/0", "DBus3bamQjgJULBJtYXpEzDWQRwF5iwxgC", "QPkeC1ZfHx3c9g7WTj9cQ8gnvk2iSAfAcbq1aVAWjNTwDAKfZUzx"},
+		{Dash, "m/44'/5'/0'/0/0", "XoJA8qE3N2Y3jMLEtZ3vcN42qseZ8LvFf5", "XGihgbi7c1nVqrjkPSvzJydLVWYW7hTrcXdfSdpFMwi3Xhbabw93"},
+		{BitcoinCash, "m/44'/145'/0'/0/0", "bitcoincash:qqyx49mu0kkn9ftfj6hje6g2wfer34yfnq5tahq3q6", "KxbEv3FeYig2afQp7QEA9R3gwqdTBFwAJJ6Ma7j1SkmZoxC9bAXZ"},
+	}
+	for _, test := range tests {
+		info, err := ChainPathFromMnemonic(secretString(t, mnemonic), "", test.chain, MainNet)
+		if err != nil {
+			t.Fatalf("%s: %v", test.chain, err)
+		}
+		if info.Path != test.path || info.Address != test.address || string(info.PrivateKey.Bytes()) != test.wif {
+			t.Errorf("%s = %s at %s, want %s:%s at %s", test.chain, info, info.Path, test.address, test.wif, test.path)
+		}
+		if err := test.chain.UTXO.ValidateAddress(info.Address); err != nil {
+			t.Errorf("%s rejects its own address %s: %v", test.chain, info.Address, err)
+		}
+		info.Destroy()
+	}
+}
+
+func TestUTXOValidateAddress(t *testing.T) {
+	tests := []struct {
+		chain   *Chain
+		address string
+		t       AddressType
+		err     error
+	}{
+		{Litecoin, "ltc1qjmxnz78nmc8nq77wuxh25n2es7rzm5c2rkk4wh", P2WPKH, nil},
+		{Litecoin, "LTC1QJMXNZ78NMC8NQ77WUXH25N2ES7RZM5C2RKK4WH", P2WPKH, nil},
+		{Litecoin, "M7wtsL7wSHDBJVMWWhtQfTMSYYkyooAAXM", P2SH, nil},
+		{Litecoin, "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", P2SH, nil}, // legacy P2SH version
+		{Litecoin, "ltc1qjmxnz78nmc8nq77wuxh25n2es7rzm5c2rkk4wj", "", ErrInvalidAddress},
+		{Litecoin, "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu", "", ErrWrongNetwork},
+		{Litecoin, "DBus3bamQjgJULBJtYXpEzDWQRwF5iwxgC", "", ErrWrongNetwork},
+		{Dogecoin, "DBus3bamQjgJULBJtYXpEzDWQRwF5iwxgC", P2PKH, nil},
+		{Dogecoin, "LUWPbpM43E2p7ZSh8cyTBEkvpHmr3cB8Ez", "", ErrWrongNetwork},
+		{Dogecoin, "DBus3bamQjgJULBJtYXpEzDWQRwF5iwxgD", "", ErrInvalidAddress},
+		{Dash, "XoJA8qE3N2Y3jMLEtZ3vcN42qseZ8LvFf5", P2PKH, nil},
+		{Dash, "1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu", "", ErrWrongNetwork},
+		{BitcoinCash, "bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a", P2PKH, nil},
+		{BitcoinCash, "qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a", P2PKH, nil},
+		{BitcoinCash, "1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu", P2PKH, nil}, // legacy format
+		{BitcoinCash, "bchtest:pr6m7j9njldwwzlg9v7v53unlr4jkmx6eyvwc0uz5t", "", ErrWrongNetwork},
+		{BitcoinCash, "bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6b", "", ErrInvalidAddress},
+		{BitcoinCash, "ltc1qjmxnz78nmc8nq77wuxh25n2es7rzm5c2rkk4wh", "", ErrInvalidAddress},
+	}
+	for _, test := range tests {
+		typ, _, err := test.chain.UTXO.DecodeAddress(test.address)
+		if test.err != nil {
+			if !errors.Is(err, test.err) {
+				t.Errorf("%s.DecodeAddress(%s) error = %v, want %v", test.chain, test.address, err, test.err)
+			}
+			continue
+		}
+		if err != nil || typ != test.t {
+			t.Errorf("%s.DecodeAddress(%s) = %s, %v, want %s", test.chain, test.address, typ, err, test.t)
+		}
+	}
+}
+
+func TestWithAddressType(t *testing.T) {
+	if _, err := Dogecoin.WithAddressType(P2WPKH); !errors.Is(err, ErrUnsupportedAddressType) {
+		t.Errorf("Dogecoin.WithAddressType(p2wpkh) error = %v, want ErrUnsupportedAddressType", err)
+	}
+	if _, err := Ethereum.WithAddressType(P2PKH); !errors.Is(err, ErrUnsupportedAddressType) {
+		t.Errorf("Ethereum.WithAddressType(p2pkh) error = %v, want ErrUnsupportedAddressType", err)
+	}
+	c, err := Litecoin.WithAddressType(P2PKH)
+	if err != nil || c.DefaultPath != "m/44'/2'/0'/0/0" {
+		t.Errorf("Litecoin.WithAddressType(p2pkh) = %v, %v", c.DefaultPath, err)
+	}
+}
+
+func TestChainForWIF(t *testing.T) {
+	tests := []struct {
+		version byte
+		net     *Network
+		want    string
+		prefix  string
+	}{
+		{0x80, MainNet, "bitcoin", "bc1"},
+		{0xef, MainNet, "bitcoin", "tb1"},
+		{0xef, RegTest, "bitcoin", "bcrt1"},
+		{0xb0, MainNet, "litecoin", "ltc1"},
+		{0x9e, TestNet3, "dogecoin", "D"},
+	}
+	pub := mustPublicKey(t, "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	for _, tt := range tests {
+		c, err := ChainForWIF(tt.version, tt.net)
+		if err != nil || c.Name != tt.want || !strings.HasPrefix(c.Address(pub), tt.prefix) {
+			t.Errorf("ChainForWIF(0x%02x, %s) = %v, %v", tt.version, tt.net, c, err)
+		}
+	}
+	if _, err := ChainForWIF(0x01, MainNet); !errors.Is(err, ErrUnknownChain) {
+		t.Errorf("ChainForWIF(0x01) error = %v, want ErrUnknownChain", err)
+	}
+}
+
+func TestUncompressedAddress(t *testing.T) {
+	// private key 1
+	pub := mustPublicKey(t, "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	bitcoin := BitcoinChain(MainNet)
+	if got, err := bitcoin.UTXO.UncompressedAddress(pub, P2PKH); err != nil || got != "1EHNa6Q4Jz2uvNExL497mE43ikXhwF6kZm" {
+		t.Errorf("UncompressedAddress(p2pkh) = %s, %v", got, err)
+	}
+	if _, err := bitcoin.UTXO.UncompressedAddress(pub, P2WPKH); !errors.Is(err, ErrUnsupportedAddressType) {
+		t.Errorf("UncompressedAddress(p2wpkh) error = %v, want ErrUnsupportedAddressType", err)
+	}
+}
+
+func TestTaprootBIP86(t *testing.T) {
+	// BIP86 test vectors
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	taproot, err := BitcoinChain(MainNet).WithAddressType(P2TR)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path     string
+		internal string
+		address  string
+	}{
+		{"", "cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115", "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr"},
+		{"m/86'/0'/0'/0/1", "83dfe85a3151d2517290da461fe2815591ef69f2b18a2ce63f01697a8b313145", "bc1p4qhjn9zdvkux4e44uhx8tc55attvtyu358kutcqkudyccelu0was9fqzwh"},
+		{"m/86'/0'/0'/1/0", "399f1b2f4393f29a18c937859c5dd8a77350103157eb880f02e8c08214277cef", "bc1p3qkhfews2uk44qtvauqyr2ttdsw7svhkl9nkm9s9c3x4ax5h60wqwruhk7"},
+	}
+	for _, tt := range tests {
+		info, err := ChainPathFromMnemonic(secretString(t, mnemonic), tt.path, taproot, MainNet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Address != tt.address || info.XOnlyPublicKey != tt.internal {
+			t.Errorf("%s = %s (internal key %s), want %s (%s)", info.Path, info.Address, info.XOnlyPublicKey, tt.address, tt.internal)
+		}
+		if typ, _, err := taproot.UTXO.DecodeAddress(info.Address); err != nil || typ != P2TR {
+			t.Errorf("DecodeAddress(%s) = %s, %v, want p2tr", info.Address, typ, err)
+		}
+		info.Destroy()
+
+		keyInfo, err := PathFromMnemonic(secretString(t, mnemonic), info.Path, MainNet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if keyInfo.TaprootAddress != tt.address || keyInfo.XOnlyPublicKey != tt.internal {
+			t.Errorf("PathFromMnemonic(%s) taproot = %s, %s", info.Path, keyInfo.TaprootAddress, keyInfo.XOnlyPublicKey)
+		}
+		keyInfo.Destroy()
+	}
+	if taproot.DefaultPath != "m/86'/0'/0'/0/0" {
+		t.Errorf("DefaultPath = %s, want m/86'/0'/0'/0/0", taproot.DefaultPath)
+	}
+	if _, err := Litecoin.WithAddressType(P2TR); !errors.Is(err, ErrUnsupportedAddressType) {
+		t.Errorf("Litecoin.WithAddressType(p2tr) error = %v, want ErrUnsupportedAddressType", err)
+	}
+
+	// version 1 programs need the bech32m checksum
+	bitcoin := BitcoinChain(MainNet)
+	if _, _, err := bitcoin.UTXO.DecodeAddress("bc1pw508d6qejxtdg4y5r3zarvary0c5xw7kw508d6qejxtdg4y5r3zarvary0c5xw7k7grplx"); !errors.Is(err, ErrInvalidAddress) {
+		t.Errorf("DecodeAddress(bech32 v1) error = %v, want ErrInvalidAddress", err)
+	}
+}
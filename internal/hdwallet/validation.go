@@ -6,6 +6,8 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/spark8899/gowallet/internal/entropy"
 )
 
 var (
@@ -38,7 +40,7 @@ var pathRegex = regexp.MustCompile(`^m(/\d+'?)+$`)
 //  2. Follow the format m/level1/level2/.../levelN
 //  3. Each level is a non-negative integer, optionally followed by ' for hardened
 //  4. Not exceed maximum depth (default 10)
-//  5. Have a standard purpose (44, 49, or 84) if specified
+//  5. Have a standard purpose (44, 49, 84, or 86) if specified
 func ValidateDerivationPath(pathStr string) error {
 	if pathStr == "" {
 		return ErrInvalidPathFormat
@@ -67,7 +69,8 @@ func ValidateDerivationPath(pathStr string) error {
 		// 44 - BIP44 (original HD wallet standard)
 		// 49 - BIP49 (SegWit in P2SH)
 		// 84 - BIP84 (Native SegWit)
-		validPurposes := []int{44, 49, 84}
+		// 86 - BIP86 (Taproot, key path only)
+		validPurposes := []int{44, 49, 84, 86}
 		valid := false
 		for _, vp := range validPurposes {
 			if purpose == vp {
@@ -76,7 +79,7 @@ func ValidateDerivationPath(pathStr string) error {
 			}
 		}
 		if !valid {
-			return fmt.Errorf("%w: %d (expected 44, 49, or 84)", ErrInvalidPurpose, purpose)
+			return fmt.Errorf("%w: %d (expected 44, 49, 84, or 86)", ErrInvalidPurpose, purpose)
 		}
 	}
 
@@ -87,18 +90,20 @@ func ValidateDerivationPath(pathStr string) error {
 //
 // Good entropy should:
 //  1. Be at least 128 bits (16 bytes) for BIP39
-//  2. Not be all zeros or any obvious pattern
-//  3. Have roughly 50% ones in binary representation (Hamming weight)
-func ValidateEntropy(entropy []byte) error {
+//  2. Not be all zeros
+//  3. Pass the health tests in the entropy package (repetition count,
+//     adaptive proportion, frequency, runs, nibble chi-square and repeating
+//     patterns), which reject random entropy at most 6 times in a million
+func ValidateEntropy(entropyBytes []byte) error {
 	// Check minimum length
-	entropyBits := len(entropy) * 8
+	entropyBits := len(entropyBytes) * 8
 	if entropyBits < minEntropyBits {
 		return fmt.Errorf("%w: got %d bits, need at least %d", ErrEntropyTooShort, entropyBits, minEntropyBits)
 	}
 
 	// Check for all zeros (extremely weak entropy)
 	allZero := true
-	for _, b := range entropy {
+	for _, b := range entropyBytes {
 		if b != 0 {
 			allZero = false
 			break
@@ -108,33 +113,9 @@ func ValidateEntropy(entropy []byte) error {
 		return ErrEntropyAllZeros
 	}
 
-	// Check Hamming weight (count of 1 bits)
-	// Good random data should have approximately 50% ones
-	ones := 0
-	for _, b := range entropy {
-		ones += hammingWeight(b)
-	}
-
-	totalBits := len(entropy) * 8
-	onesRatio := float64(ones) / float64(totalBits)
-
-	// Allow 40%-60% ones (looser than ideal 50% for practical use)
-	// This catches obvious non-random patterns while allowing normal variance
-	if onesRatio < 0.40 || onesRatio > 0.60 {
-		return fmt.Errorf("%w: hamming weight %.2f%% (expected 40-60%%)",
-			ErrEntropyPoorQuality, onesRatio*100)
+	if err := entropy.Validate(entropyBytes); err != nil {
+		return fmt.Errorf("%w: %v", ErrEntropyPoorQuality, err)
 	}
 
 	return nil
 }
-
-// hammingWeight counts the number of 1 bits in a byte
-func hammingWeight(b byte) int {
-	count := 0
-	for i := 0; i < 8; i++ {
-		if b&(1<<i) != 0 {
-			count++
-		}
-	}
-	return count
-}
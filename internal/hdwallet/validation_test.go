@@ -1,6 +1,7 @@
 package hdwallet
 
 import (
+	"encoding/hex"
 	"strings"
 	"testing"
 )
@@ -56,6 +57,11 @@ func TestValidateDerivationPath(t *testing.T) {
 			path:    "m/44'",
 			wantErr: false,
 		},
+		{
+			name:    "BIP86 taproot path",
+			path:    "m/86'/0'/0'/0/0",
+			wantErr: false,
+		},
 		{
 			name:    "path with invalid purpose",
 			path:    "m/99'/60'/0'/0/0",
@@ -88,59 +94,72 @@ func TestValidateDerivationPath(t *testing.T) {
 func TestValidateEntropy(t *testing.T) {
 	tests := []struct {
 		name    string
-		entropy []byte
+		entropy string
 		wantErr bool
 		errType error
 	}{
 		{
 			name:    "too short entropy",
-			entropy: []byte{0x01, 0x02, 0x03},
+			entropy: "010203",
 			wantErr: true,
 			errType: ErrEntropyTooShort,
 		},
 		{
 			name:    "all zeros (weak)",
-			entropy: make([]byte, 16), // 128 bits of zeros
+			entropy: strings.Repeat("00", 16), // 128 bits of zeros
 			wantErr: true,
 			errType: ErrEntropyAllZeros,
 		},
 		{
 			name:    "all ones (poor quality)",
-			entropy: []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+			entropy: strings.Repeat("ff", 16),
+			wantErr: true,
+			errType: ErrEntropyPoorQuality,
+		},
+		{
+			// 50% ones, which the old Hamming weight check accepted
+			name:    "alternating bits (poor quality)",
+			entropy: strings.Repeat("aa", 16),
 			wantErr: true,
 			errType: ErrEntropyPoorQuality,
 		},
 		{
-			name: "good quality entropy (50% ones)",
-			entropy: []byte{
-				0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, // 10101010 repeated
-				0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, // 50% ones
-			},
+			name:    "alternating bytes (poor quality)",
+			entropy: strings.Repeat("55", 8) + strings.Repeat("aa", 8),
+			wantErr: true,
+			errType: ErrEntropyPoorQuality,
+		},
+		{
+			name:    "256-bit alternating (poor quality)",
+			entropy: strings.Repeat("aa", 16) + strings.Repeat("55", 16),
+			wantErr: true,
+			errType: ErrEntropyPoorQuality,
+		},
+		{
+			name:    "good quality entropy",
+			entropy: "9e885d952ad362caeb4efe34a8e91bd2",
 			wantErr: false,
 		},
 		{
-			name: "acceptable entropy (45% ones)",
-			entropy: []byte{
-				0x55, 0x55, 0x55, 0x55, 0x55, 0x55, 0x55, 0x55, // 01010101 repeated
-				0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, // Mix to get ~45%
-			},
+			// 37.5% ones, outside the old 40-60% band
+			name:    "random entropy with low Hamming weight",
+			entropy: "e39a604aa14a7319039414010a22a927",
 			wantErr: false,
 		},
 		{
-			name: "256-bit good entropy",
-			entropy: []byte{
-				0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa,
-				0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa,
-				0x55, 0x55, 0x55, 0x55, 0x55, 0x55, 0x55, 0x55,
-				0x55, 0x55, 0x55, 0x55, 0x55, 0x55, 0x55, 0x55,
-			},
+			name:    "256-bit good entropy",
+			entropy: "68a79eaca2324873eacc50cb9c6eca8cc68ea5d936f98787c60c7ebc74e6ce7c",
 			wantErr: false,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := ValidateEntropy(tt.entropy)
+			entropy, err := hex.DecodeString(tt.entropy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = ValidateEntropy(entropy)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ValidateEntropy() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -153,27 +172,3 @@ func TestValidateEntropy(t *testing.T) {
 		})
 	}
 }
-
-func TestHammingWeight(t *testing.T) {
-	tests := []struct {
-		name string
-		b    byte
-		want int
-	}{
-		{"all zeros", 0x00, 0},
-		{"all ones", 0xff, 8},
-		{"alternating 1", 0xaa, 4}, // 10101010
-		{"alternating 2", 0x55, 4}, // 01010101
-		{"single bit", 0x01, 1},
-		{"single bit", 0x80, 1},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := hammingWeight(tt.b)
-			if got != tt.want {
-				t.Errorf("hammingWeight(%08b) = %d, want %d", tt.b, got, tt.want)
-			}
-		})
-	}
-}
@@ -0,0 +1,76 @@
+package paperwallet
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+)
+
+// htmlTemplate holds the page around the fields, which renderHTML writes
+// itself so that secret values go from their SecretBuffers straight into
+// the output instead of through template strings
+var htmlTemplate = template.Must(template.New("paper").Parse(`{{define "head"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta http-equiv="Content-Security-Policy" content="default-src 'none'; style-src 'unsafe-inline'; img-src data:">
+<title>{{.Title}}</title>
+<style>
+@page { size: A4; margin: 15mm; }
+body { font-family: Helvetica, Arial, sans-serif; color: #000; background: #fff; max-width: 180mm; margin: 0 auto; }
+h1 { margin-bottom: 0; }
+.sub { font-size: 9pt; color: #444; margin-top: 2px; }
+.field { display: flex; gap: 6mm; align-items: flex-start; padding: 4mm 0; border-bottom: 1px dashed #999; page-break-inside: avoid; }
+.field svg { flex: none; }
+.label { font-weight: bold; margin-bottom: 2mm; }
+.secret .label { color: #b00; }
+.value { font-family: "Courier New", Courier, monospace; font-size: 10pt; word-break: break-all; }
+.words { columns: 3; font-family: "Courier New", Courier, monospace; font-size: 10pt; margin: 0; padding-left: 6mm; }
+.warning { font-size: 8pt; margin-top: 6mm; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="sub">{{.Subtitle}}</p>
+{{end}}{{define "tail"}}<p class="warning">{{.Warning}}</p>
+</body>
+</html>
+{{end}}`))
+
+// renderHTML writes a single HTML file with inline CSS and SVG QR codes.
+// The Content-Security-Policy forbids every external load.
+func renderHTML(out io.Writer, w *Wallet, fields []field) error {
+	data := struct{ Title, Subtitle, Warning string }{title, w.subtitle(), warning}
+	if err := htmlTemplate.ExecuteTemplate(out, "head", data); err != nil {
+		return err
+	}
+
+	for _, f := range fields {
+		class := "field"
+		if f.Secret {
+			class += " secret"
+		}
+		fmt.Fprintf(out, "<div class=\"%s\">\n", class)
+		if f.QR != nil {
+			// The markup is generated from module coordinates only
+			writeQRSVG(out, f.QR, 150)
+		}
+		fmt.Fprintf(out, "<div>\n<div class=\"label\">%s</div>\n", template.HTMLEscapeString(f.Label))
+		if f.Words != nil {
+			io.WriteString(out, `<ol class="words">`)
+			for _, word := range bytes.Fields(f.Value) {
+				io.WriteString(out, "<li>")
+				template.HTMLEscape(out, word)
+				io.WriteString(out, "</li>")
+			}
+			io.WriteString(out, "</ol>")
+		} else {
+			io.WriteString(out, `<div class="value">`)
+			template.HTMLEscape(out, f.Value)
+			io.WriteString(out, "</div>")
+		}
+		io.WriteString(out, "\n</div>\n</div>\n")
+	}
+	return htmlTemplate.ExecuteTemplate(out, "tail", data)
+}
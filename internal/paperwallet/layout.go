@@ -0,0 +1,71 @@
+package paperwallet
+
+// A4 page size in points
+const (
+	pageWidth  = 595.0
+	pageHeight = 842.0
+	margin     = 50.0
+	qrSide     = 120.0
+	// valueChars is how many 9pt Courier characters fit beside a QR code
+	valueChars = 66
+)
+
+type fontKind int
+
+const (
+	fontRegular fontKind = iota
+	fontBold
+	fontMono
+)
+
+// item is a positioned piece of a page shared by the SVG and PDF renderers.
+// Coordinates are in points from the top left corner; y is the text
+// baseline or the top edge of a QR code. The text of a secret field aliases
+// its SecretBuffer.
+type item struct {
+	x, y float64
+	text []byte
+	font fontKind
+	size float64
+	qr   QR
+	side float64
+}
+
+// layout places the wallet fields on an A4 page
+func layout(w *Wallet, fields []field) []item {
+	items := []item{
+		{x: margin, y: 70, text: []byte(title), font: fontBold, size: 20},
+		{x: margin, y: 88, text: []byte(w.subtitle()), font: fontRegular, size: 9},
+	}
+
+	y := 110.0
+	for _, f := range fields {
+		textX := margin
+		if f.QR != nil {
+			items = append(items, item{x: margin, y: y, qr: f.QR, side: qrSide})
+			textX = margin + qrSide + 15
+		}
+		items = append(items, item{x: textX, y: y + 14, text: []byte(f.Label), font: fontBold, size: 11})
+
+		value := f.Value
+		if f.Words != nil {
+			value = f.Words.Bytes()
+		}
+		lineY := y + 30
+		for _, line := range wrap(value, valueChars) {
+			items = append(items, item{x: textX, y: lineY, text: line, font: fontMono, size: 9})
+			lineY += 12
+		}
+
+		if f.QR != nil {
+			y += qrSide + 15
+		} else {
+			y = lineY + 10
+		}
+	}
+
+	for i, line := range wrap([]byte(warning), 100) {
+		items = append(items, item{x: margin, y: pageHeight - 50 + float64(i)*11, text: line, font: fontRegular, size: 8})
+	}
+	return items
+}
@@ -0,0 +1,110 @@
+package paperwallet
+
+import (
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+var pdfFonts = map[fontKind]string{
+	fontRegular: "/F1",
+	fontBold:    "/F2",
+	fontMono:    "/F3",
+}
+
+// contentObject is the index of the page content stream among the objects
+const contentObject = 3
+
+// renderPDF writes the page as a single-page PDF 1.4 document using only
+// the standard base fonts, so nothing needs to be embedded. The content
+// stream is written twice, first only to measure its length, so the page
+// is never held in a buffer of its own.
+func renderPDF(out io.Writer, items []item) error {
+	var length countingWriter
+	writePDFContent(&length, items)
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %g %g] /Contents 4 0 R "+
+			"/Resources << /Font << /F1 5 0 R /F2 6 0 R /F3 7 0 R >> >> >>", pageWidth, pageHeight),
+		fmt.Sprintf("<< /Length %d >>\nstream\n", length.n),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>",
+	}
+
+	w := &countingWriter{w: out}
+	io.WriteString(w, "%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = w.n
+		fmt.Fprintf(w, "%d 0 obj\n%s", i+1, obj)
+		if i == contentObject {
+			writePDFContent(w, items)
+			io.WriteString(w, "endstream")
+		}
+		io.WriteString(w, "\nendobj\n")
+	}
+	xref := w.n
+	fmt.Fprintf(w, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(w, "%010d 00000 n \n", off)
+	}
+	_, err := fmt.Fprintf(w, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return err
+}
+
+// writePDFContent writes the page content stream
+func writePDFContent(w io.Writer, items []item) {
+	for _, it := range items {
+		if it.qr != nil {
+			io.WriteString(w, "q 0 g\n")
+			it.qr.writePDFRects(w, it.x, pageHeight-it.y, it.side)
+			io.WriteString(w, "f Q\n")
+			continue
+		}
+		fmt.Fprintf(w, "BT %s %g Tf %g %g Td (", pdfFonts[it.font], it.size, it.x, pageHeight-it.y)
+		escapePDF(w, it.text)
+		io.WriteString(w, ") Tj ET\n")
+	}
+}
+
+// countingWriter counts the bytes written to w, or discards them if w is nil
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.w == nil {
+		c.n += len(p)
+		return len(p), nil
+	}
+	n, err := c.w.Write(p)
+	c.n += n
+	return n, err
+}
+
+// escapePDF writes s as the body of a PDF literal string, replacing
+// characters outside printable ASCII, which the base fonts cannot show
+// reliably
+func escapePDF(w io.Writer, s []byte) {
+	start := 0
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRune(s[i:])
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			// the character itself is written with the next run
+			w.Write(s[start:i])
+			io.WriteString(w, "\\")
+			start = i
+		case r < 0x20 || r > 0x7e:
+			w.Write(s[start:i])
+			io.WriteString(w, "?")
+			start = i + size
+		}
+		i += size
+	}
+	w.Write(s[start:])
+}
@@ -0,0 +1,145 @@
+// Package paperwallet renders paper backups of keys and mnemonics: QR codes
+// for the terminal, and self-contained HTML, SVG and PDF documents. Nothing
+// is fetched from the network; fonts are the PDF base fonts or the viewer's
+// own, and QR codes are drawn as vector paths.
+package paperwallet
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	qrcode "github.com/skip2/go-qrcode"
+	"github.com/spark8899/gowallet/internal/security"
+)
+
+// QR is a QR code bitmap including its 4-module quiet zone. QR[y][x] is
+// true for a dark module.
+type QR [][]bool
+
+// NewQR encodes content with medium (15%) error correction
+func NewQR(content string) (QR, error) {
+	code, err := qrcode.New(content, qrcode.Medium)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode QR code: %w", err)
+	}
+	return QR(code.Bitmap()), nil
+}
+
+// newSecretQR encodes a secret held in a SecretBuffer. go-qrcode only takes
+// a string, so the string is made to alias content instead of copying it;
+// the encoder does not keep it past Bitmap. Its own bit streams are heap
+// memory that cannot be wiped, but the returned bitmap can: see zeroQR.
+func newSecretQR(content []byte) (QR, error) {
+	return NewQR(unsafe.String(unsafe.SliceData(content), len(content)))
+}
+
+// zeroQR clears every module of q, which encodes a secret as surely as the
+// text does. It is a variable so tests can check that Render wipes every
+// secret code.
+var zeroQR = func(q QR) {
+	for _, row := range q {
+		clear(row)
+	}
+}
+
+// Size returns the width (and height) in modules
+func (q QR) Size() int {
+	return len(q)
+}
+
+// Terminal renders the code for a terminal. The Unicode form packs two rows
+// into each line with half blocks and draws the light modules, so it scans
+// on the usual light-on-dark terminal. The ASCII form uses "##" for dark
+// modules, for light backgrounds and printouts.
+func (q QR) Terminal(ascii bool) string {
+	var b strings.Builder
+	if ascii {
+		for _, row := range q {
+			for _, dark := range row {
+				if dark {
+					b.WriteString("##")
+				} else {
+					b.WriteString("  ")
+				}
+			}
+			b.WriteByte('\n')
+		}
+		return b.String()
+	}
+
+	for y := 0; y < len(q); y += 2 {
+		for x := range q[y] {
+			top := !q[y][x]
+			bottom := y+1 < len(q) && !q[y+1][x]
+			switch {
+			case top && bottom:
+				b.WriteString("█")
+			case top:
+				b.WriteString("▀")
+			case bottom:
+				b.WriteString("▄")
+			default:
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// runs calls fn for each horizontal run of dark modules
+func (q QR) runs(fn func(x, y, length int)) {
+	for y, row := range q {
+		for x := 0; x < len(row); {
+			if !row[x] {
+				x++
+				continue
+			}
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+			fn(start, y, x-start)
+		}
+	}
+}
+
+// writeSVGPath writes SVG path data drawing the dark modules as unit
+// squares. The numbers go through a small buffer that is wiped afterwards
+// rather than through fmt, whose buffers are pooled.
+func (q QR) writeSVGPath(w io.Writer) {
+	buf := make([]byte, 0, 64)
+	defer security.ZeroBytes(buf[:cap(buf)])
+	q.runs(func(x, y, length int) {
+		buf = append(buf[:0], 'M')
+		buf = strconv.AppendInt(buf, int64(x), 10)
+		buf = append(buf, ' ')
+		buf = strconv.AppendInt(buf, int64(y), 10)
+		buf = append(buf, 'h')
+		buf = strconv.AppendInt(buf, int64(length), 10)
+		buf = append(buf, "v1h-"...)
+		buf = strconv.AppendInt(buf, int64(length), 10)
+		buf = append(buf, 'z')
+		w.Write(buf)
+	})
+}
+
+// writePDFRects writes PDF "re" operators for the dark modules of q drawn
+// side x side with its top left corner at (x, top) in PDF coordinates
+func (q QR) writePDFRects(w io.Writer, x, top, side float64) {
+	module := side / float64(q.Size())
+	buf := make([]byte, 0, 64)
+	defer security.ZeroBytes(buf[:cap(buf)])
+	q.runs(func(mx, my, length int) {
+		buf = buf[:0]
+		for _, v := range [4]float64{x + float64(mx)*module, top - float64(my+1)*module, float64(length) * module, module} {
+			buf = strconv.AppendFloat(buf, v, 'f', 3, 64)
+			buf = append(buf, ' ')
+		}
+		buf = append(buf, "re\n"...)
+		w.Write(buf)
+	})
+}
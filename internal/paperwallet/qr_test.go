@@ -0,0 +1,83 @@
+package paperwallet
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestNewQR(t *testing.T) {
+	q, err := NewQR("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	if err != nil {
+		t.Fatalf("NewQR() error: %v", err)
+	}
+	// Version v is 17+4v modules plus a 4-module quiet zone on each side
+	if (q.Size()-8-17)%4 != 0 {
+		t.Fatalf("unexpected size %d", q.Size())
+	}
+	for y, row := range q {
+		if len(row) != q.Size() {
+			t.Fatalf("row %d has %d modules, want %d", y, len(row), q.Size())
+		}
+	}
+	// Top left finder pattern: dark 7x7 ring inside the quiet zone
+	for i := 0; i < 7; i++ {
+		for _, p := range [][2]int{{4 + i, 4}, {4 + i, 10}, {4, 4 + i}, {10, 4 + i}} {
+			if !q[p[1]][p[0]] {
+				t.Fatalf("finder pattern module (%d,%d) is light", p[0], p[1])
+			}
+		}
+	}
+	if q[0][0] || q[5][5] {
+		t.Error("quiet zone or finder separator is dark")
+	}
+}
+
+func TestQR_Terminal(t *testing.T) {
+	q, err := NewQR("bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ascii := strings.Split(strings.TrimSuffix(q.Terminal(true), "\n"), "\n")
+	if len(ascii) != q.Size() {
+		t.Fatalf("ASCII has %d lines, want %d", len(ascii), q.Size())
+	}
+	for y, line := range ascii {
+		for x := 0; x < q.Size(); x++ {
+			if dark := line[2*x:2*x+2] == "##"; dark != q[y][x] {
+				t.Fatalf("ASCII module (%d,%d) = %v, want %v", x, y, dark, q[y][x])
+			}
+		}
+	}
+
+	unicode := strings.Split(strings.TrimSuffix(q.Terminal(false), "\n"), "\n")
+	if len(unicode) != (q.Size()+1)/2 {
+		t.Fatalf("Unicode has %d lines, want %d", len(unicode), (q.Size()+1)/2)
+	}
+	for _, line := range unicode {
+		if n := utf8.RuneCountInString(line); n != q.Size() {
+			t.Fatalf("Unicode line has %d cells, want %d", n, q.Size())
+		}
+	}
+}
+
+func TestQR_Runs(t *testing.T) {
+	q, err := NewQR("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	covered := make(map[[2]int]bool)
+	q.runs(func(x, y, length int) {
+		for i := 0; i < length; i++ {
+			covered[[2]int{x + i, y}] = true
+		}
+	})
+	for y, row := range q {
+		for x, dark := range row {
+			if covered[[2]int{x, y}] != dark {
+				t.Fatalf("module (%d,%d): covered %v, dark %v", x, y, covered[[2]int{x, y}], dark)
+			}
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package paperwallet
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+var svgFonts = map[fontKind]string{
+	fontRegular: `font-family="Helvetica, Arial, sans-serif"`,
+	fontBold:    `font-family="Helvetica, Arial, sans-serif" font-weight="bold"`,
+	fontMono:    `font-family="Courier New, Courier, monospace"`,
+}
+
+// renderSVG writes the page as a standalone A4 SVG document. Text is
+// escaped straight from the items into w.
+func renderSVG(w io.Writer, items []item) error {
+	fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<svg xmlns="http://www.w3.org/2000/svg" width="210mm" height="297mm" viewBox="0 0 %g %g">
+<rect width="100%%" height="100%%" fill="#fff"/>
+`, pageWidth, pageHeight)
+	for _, it := range items {
+		if it.qr != nil {
+			fmt.Fprintf(w, `<path transform="translate(%g %g) scale(%g)" fill="#000" shape-rendering="crispEdges" d="`,
+				it.x, it.y, it.side/float64(it.qr.Size()))
+			it.qr.writeSVGPath(w)
+			io.WriteString(w, "\"/>\n")
+			continue
+		}
+		fmt.Fprintf(w, `<text x="%g" y="%g" font-size="%g" %s>`, it.x, it.y, it.size, svgFonts[it.font])
+		if err := xml.EscapeText(w, it.text); err != nil {
+			return err
+		}
+		io.WriteString(w, "</text>\n")
+	}
+	_, err := io.WriteString(w, "</svg>\n")
+	return err
+}
+
+// writeQRSVG writes q as a standalone inline SVG element of side x side
+// pixels
+func writeQRSVG(w io.Writer, q QR, side int) {
+	fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d"><rect width="%d" height="%d" fill="#fff"/><path fill="#000" shape-rendering="crispEdges" d="`,
+		side, side, q.Size(), q.Size(), q.Size(), q.Size())
+	q.writeSVGPath(w)
+	io.WriteString(w, `"/></svg>`)
+}
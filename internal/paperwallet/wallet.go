@@ -0,0 +1,302 @@
+package paperwallet
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spark8899/gowallet/internal/security"
+)
+
+// ErrUnknownFormat indicates an output format other than html, svg or pdf
+var ErrUnknownFormat = errors.New("unknown paper wallet format, use html, svg or pdf")
+
+// ErrNoSecret indicates a wallet with neither a private key nor a mnemonic
+var ErrNoSecret = errors.New("paper wallet needs a private key or a mnemonic")
+
+// Format is a paper wallet document format
+type Format string
+
+const (
+	HTML Format = "html"
+	SVG  Format = "svg"
+	PDF  Format = "pdf"
+)
+
+// ParseFormat returns the format named by s, e.g. "pdf" or ".pdf"
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(strings.TrimPrefix(s, ".")) {
+	case "html", "htm":
+		return HTML, nil
+	case "svg":
+		return SVG, nil
+	case "pdf":
+		return PDF, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownFormat, s)
+}
+
+// FormatFromPath returns the format implied by a file name's extension
+func FormatFromPath(path string) (Format, error) {
+	return ParseFormat(filepath.Ext(path))
+}
+
+// Wallet is the content of a paper wallet. Empty optional fields are left
+// off the page.
+//
+// The secrets stay in SecretBuffers owned by the caller. Render writes them
+// from Bytes() into the document and wipes its QR codes, buffers and other
+// copies of them before returning.
+type Wallet struct {
+	Network    string
+	Address    string
+	PrivateKey *security.SecretBuffer // 0x-prefixed hex
+	WIF        *security.SecretBuffer // optional
+	Mnemonic   *security.SecretBuffer // optional
+	Path       string                 // optional derivation path of the key
+	Created    time.Time
+}
+
+// field is one labelled value on the page. The value of a secret field
+// aliases the Wallet's SecretBuffer.
+type field struct {
+	Label  string
+	Value  []byte
+	Secret bool
+	QR     QR
+	// Words holds a mnemonic's words numbered "1.word 2.word ..."
+	Words *security.SecretBuffer
+}
+
+func (w *Wallet) fields() (fields []field, err error) {
+	if w.PrivateKey.Len() == 0 && w.Mnemonic.Len() == 0 {
+		return nil, ErrNoSecret
+	}
+	defer func() {
+		if err != nil {
+			wipeFields(fields)
+			fields = nil
+		}
+	}()
+
+	add := func(label string, value []byte, secret, qr, words bool) error {
+		if len(value) == 0 {
+			return nil
+		}
+		fields = append(fields, field{Label: label, Value: value, Secret: secret})
+		f := &fields[len(fields)-1]
+		if words {
+			numbered, err := numberWords(value)
+			if err != nil {
+				return err
+			}
+			f.Words = numbered
+		}
+		if qr {
+			newQR := func(b []byte) (QR, error) { return NewQR(string(b)) }
+			if secret {
+				newQR = newSecretQR
+			}
+			code, err := newQR(value)
+			if err != nil {
+				return err
+			}
+			f.QR = code
+		}
+		return nil
+	}
+
+	for _, f := range []struct {
+		label             string
+		value             []byte
+		secret, qr, words bool
+	}{
+		{"Address (public, share to receive)", []byte(w.Address), false, true, false},
+		{"Private key (SECRET)", w.PrivateKey.Bytes(), true, true, false},
+		{"WIF (SECRET)", w.WIF.Bytes(), true, true, false},
+		{"Mnemonic (SECRET)", w.Mnemonic.Bytes(), true, true, true},
+		{"Derivation path", []byte(w.Path), false, false, false},
+	} {
+		if err := add(f.label, f.value, f.secret, f.qr, f.words); err != nil {
+			return fields, err
+		}
+	}
+	return fields, nil
+}
+
+// wipeFields clears the QR codes and numbered words of the secret fields
+func wipeFields(fields []field) {
+	for _, f := range fields {
+		if !f.Secret {
+			continue
+		}
+		if f.QR != nil {
+			zeroQR(f.QR)
+		}
+		if f.Words != nil {
+			f.Words.Destroy()
+		}
+	}
+}
+
+// subtitle returns the line under the title
+func (w *Wallet) subtitle() string {
+	created := w.Created
+	if created.IsZero() {
+		created = time.Now()
+	}
+	s := "Generated offline by gowallet on " + created.UTC().Format("2006-01-02 15:04 MST")
+	if w.Network != "" {
+		s += " - network " + w.Network
+	}
+	return s
+}
+
+const (
+	title   = "Paper Wallet"
+	warning = "Anyone who sees the private key or mnemonic can spend the funds. Store this page offline and never photograph it."
+)
+
+// Render writes w as a self-contained document in format. Output is
+// buffered in memory that is wiped once it has been written to out.
+func Render(out io.Writer, w *Wallet, format Format) error {
+	fields, err := w.fields()
+	if err != nil {
+		return err
+	}
+	defer wipeFields(fields)
+
+	bw := &wipingWriter{w: out, buf: make([]byte, 0, 4096)}
+	defer bw.wipe()
+	switch format {
+	case HTML:
+		err = renderHTML(bw, w, fields)
+	case SVG:
+		err = renderSVG(bw, layout(w, fields))
+	case PDF:
+		err = renderPDF(bw, layout(w, fields))
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// numberWords turns a mnemonic into "1.word 2.word ..." in a new
+// SecretBuffer
+func numberWords(mnemonic []byte) (*security.SecretBuffer, error) {
+	words := bytes.Fields(mnemonic)
+	length := 0
+	for i, w := range words {
+		length += len(strconv.Itoa(i+1)) + 1 + len(w) + 1
+	}
+	out, err := security.NewSecretBuffer(max(length-1, 0))
+	if err != nil {
+		return nil, err
+	}
+	buf := out.Bytes()[:0]
+	for i, w := range words {
+		if i > 0 {
+			buf = append(buf, ' ')
+		}
+		buf = strconv.AppendInt(buf, int64(i+1), 10)
+		buf = append(buf, '.')
+		buf = append(buf, w...)
+	}
+	return out, nil
+}
+
+// wrap breaks s into lines of at most width bytes, preferring spaces and
+// splitting longer words. The lines are slices of s, not copies.
+func wrap(s []byte, width int) [][]byte {
+	var lines [][]byte
+	lineStart, lineEnd := -1, -1 // the current line is s[lineStart:lineEnd]
+	flush := func() {
+		if lineStart >= 0 {
+			lines = append(lines, s[lineStart:lineEnd])
+			lineStart = -1
+		}
+	}
+	for start := 0; ; {
+		for start < len(s) && isSpace(s[start]) {
+			start++
+		}
+		if start == len(s) {
+			break
+		}
+		end := start
+		for end < len(s) && !isSpace(s[end]) {
+			end++
+		}
+		for end-start > width {
+			flush()
+			lines = append(lines, s[start:start+width])
+			start += width
+		}
+		switch {
+		case lineStart < 0:
+			lineStart, lineEnd = start, end
+		case end-lineStart <= width:
+			lineEnd = end
+		default:
+			flush()
+			lineStart, lineEnd = start, end
+		}
+		start = end
+	}
+	flush()
+	return lines
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// wipingWriter buffers output like bufio.Writer, but its buffer is wiped
+// after every flush and can be wiped for good once rendering is done. The
+// first write error sticks, as with bufio.Writer.
+type wipingWriter struct {
+	w   io.Writer
+	buf []byte
+	err error
+}
+
+func (b *wipingWriter) Write(p []byte) (int, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+	if len(b.buf)+len(p) > cap(b.buf) {
+		if err := b.Flush(); err != nil {
+			return 0, err
+		}
+	}
+	if len(p) > cap(b.buf) {
+		n, err := b.w.Write(p)
+		b.err = err
+		return n, err
+	}
+	b.buf = append(b.buf, p...)
+	return len(p), nil
+}
+
+// Flush writes the buffered data and wipes the buffer
+func (b *wipingWriter) Flush() error {
+	if b.err != nil || len(b.buf) == 0 {
+		return b.err
+	}
+	_, b.err = b.w.Write(b.buf)
+	security.ZeroBytes(b.buf)
+	b.buf = b.buf[:0]
+	return b.err
+}
+
+func (b *wipingWriter) wipe() {
+	security.ZeroBytes(b.buf[:cap(b.buf)])
+}
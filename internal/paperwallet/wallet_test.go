@@ -0,0 +1,260 @@
+package paperwallet
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spark8899/gowallet/internal/security"
+)
+
+const (
+	testKey      = "0x1ab42cc412b618bdea3a599e3c9bae199ebf030895b039e9db1e30dafb12b727"
+	testMnemonic = "test test test test test test test test test test test junk"
+)
+
+func secretString(t *testing.T, s string) *security.SecretBuffer {
+	t.Helper()
+	secret, err := security.NewSecretBufferFromString(s)
+	if err != nil {
+		t.Fatalf("NewSecretBufferFromString() error: %v", err)
+	}
+	t.Cleanup(secret.Destroy)
+	return secret
+}
+
+func newTestWallet(t *testing.T) *Wallet {
+	return &Wallet{
+		Network:    "mainnet",
+		Address:    "0x9858EfFD232B4033E47d90003D41EC34EcaEda94",
+		PrivateKey: secretString(t, testKey),
+		Mnemonic:   secretString(t, testMnemonic),
+		Path:       "m/44'/60'/0'/0/0",
+		Created:    time.Date(2024, 1, 2, 3, 4, 0, 0, time.UTC),
+	}
+}
+
+func TestRender_HTML(t *testing.T) {
+	testWallet := newTestWallet(t)
+	var buf bytes.Buffer
+	if err := Render(&buf, testWallet, HTML); err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+	page := buf.String()
+
+	for _, want := range []string{testWallet.Address, testKey, "m/44&#39;/60&#39;/0&#39;/0/0", "<li>junk</li>", "2024-01-02 03:04 UTC"} {
+		if !strings.Contains(page, want) {
+			t.Errorf("HTML does not contain %q", want)
+		}
+	}
+	if n := strings.Count(page, "<svg"); n != 3 {
+		t.Errorf("HTML has %d QR codes, want 3", n)
+	}
+	// Self-contained: no scripts and nothing loaded from elsewhere
+	for _, banned := range []string{"<script", "src=", "href=", "https://", "url("} {
+		if strings.Contains(page, banned) {
+			t.Errorf("HTML contains %q", banned)
+		}
+	}
+}
+
+func TestRender_HTMLEscapes(t *testing.T) {
+	w := *newTestWallet(t)
+	w.Path = `<script>alert(1)</script>`
+	var buf bytes.Buffer
+	if err := Render(&buf, &w, HTML); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "<script>") {
+		t.Error("field value was not escaped")
+	}
+}
+
+func TestRender_SVG(t *testing.T) {
+	testWallet := newTestWallet(t)
+	var buf bytes.Buffer
+	if err := Render(&buf, testWallet, SVG); err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+
+	var texts []string
+	dec := xml.NewDecoder(&buf)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("SVG is not well formed: %v", err)
+		}
+		if cd, ok := tok.(xml.CharData); ok {
+			texts = append(texts, string(cd))
+		}
+	}
+	all := strings.Join(texts, "\n")
+	for _, want := range []string{testWallet.Address, testKey, "12.junk", "m/44'/60'/0'/0/0"} {
+		if !strings.Contains(all, want) {
+			t.Errorf("SVG text does not contain %q", want)
+		}
+	}
+}
+
+func TestRender_PDF(t *testing.T) {
+	testWallet := newTestWallet(t)
+	var buf bytes.Buffer
+	if err := Render(&buf, testWallet, PDF); err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+	doc := buf.Bytes()
+
+	if !bytes.HasPrefix(doc, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(doc, []byte("%%EOF\n")) {
+		t.Fatal("missing PDF header or trailer")
+	}
+	for _, want := range []string{"(" + testWallet.Address + ")", "(" + testKey + ")", "(m/44'/60'/0'/0/0)"} {
+		if !bytes.Contains(doc, []byte(want)) {
+			t.Errorf("PDF does not contain %s", want)
+		}
+	}
+
+	// startxref and every xref entry must point at the right byte offsets
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(doc)
+	if m == nil {
+		t.Fatal("no startxref")
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	if !bytes.HasPrefix(doc[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref %d does not point at the xref table", xref)
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(doc[xref:], -1)
+	if len(entries) != 7 {
+		t.Fatalf("xref has %d objects, want 7", len(entries))
+	}
+	for i, e := range entries {
+		off, _ := strconv.Atoi(string(e[1]))
+		if want := fmt.Sprintf("%d 0 obj\n", i+1); !bytes.HasPrefix(doc[off:], []byte(want)) {
+			t.Errorf("xref entry %d points at %q", i+1, doc[off:off+10])
+		}
+	}
+
+	// The stream length must match the bytes between stream and endstream
+	lm := regexp.MustCompile(`(?s)<< /Length (\d+) >>\nstream\n(.*)endstream`).FindSubmatch(doc)
+	if lm == nil {
+		t.Fatal("no content stream")
+	}
+	if n, _ := strconv.Atoi(string(lm[1])); n != len(lm[2]) {
+		t.Errorf("stream /Length %d, actual %d", n, len(lm[2]))
+	}
+}
+
+func TestRender_Errors(t *testing.T) {
+	if err := Render(io.Discard, &Wallet{Address: "0x1"}, PDF); !errors.Is(err, ErrNoSecret) {
+		t.Errorf("Render() without secret error = %v, want %v", err, ErrNoSecret)
+	}
+	if err := Render(io.Discard, newTestWallet(t), Format("png")); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Render(png) error = %v, want %v", err, ErrUnknownFormat)
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	tests := map[string]Format{"backup.pdf": PDF, "a/b.SVG": SVG, "w.htm": HTML, "w.html": HTML}
+	for path, want := range tests {
+		if got, err := FormatFromPath(path); err != nil || got != want {
+			t.Errorf("FormatFromPath(%q) = %q, %v, want %q", path, got, err, want)
+		}
+	}
+	if _, err := FormatFromPath("wallet.txt"); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("FormatFromPath(txt) error = %v, want %v", err, ErrUnknownFormat)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		in    string
+		width int
+		want  []string
+	}{
+		{"a bb ccc", 4, []string{"a bb", "ccc"}},
+		{"0123456789", 4, []string{"0123", "4567", "89"}},
+		{"x 0123456789", 4, []string{"x", "0123", "4567", "89"}},
+		{"", 4, nil},
+	}
+	for _, tt := range tests {
+		got := wrap([]byte(tt.in), tt.width)
+		if string(bytes.Join(got, []byte("|"))) != strings.Join(tt.want, "|") {
+			t.Errorf("wrap(%q, %d) = %q, want %q", tt.in, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestEscapePDF(t *testing.T) {
+	var b strings.Builder
+	escapePDF(&b, []byte(`a(b)\c`+"é"))
+	if got := b.String(); got != `a\(b\)\\c?` {
+		t.Errorf("escapePDF() = %q", got)
+	}
+}
+
+func TestRender_WipesSecrets(t *testing.T) {
+	for _, format := range []Format{HTML, SVG, PDF} {
+		t.Run(string(format), func(t *testing.T) {
+			var wiped []QR
+			orig := zeroQR
+			zeroQR = func(q QR) {
+				wiped = append(wiped, q)
+				orig(q)
+			}
+			t.Cleanup(func() { zeroQR = orig })
+
+			w := newTestWallet(t)
+			w.WIF = secretString(t, "KxFC1jmwwCoACiCAWZ3eXa96mBM6tb3TYzGmf6YwgdGWZgawvrtJ")
+			var buf bytes.Buffer
+			if err := Render(&buf, w, format); err != nil {
+				t.Fatalf("Render() error: %v", err)
+			}
+
+			// The private key, WIF and mnemonic codes, but not the address
+			if len(wiped) != 3 {
+				t.Fatalf("wiped %d QR codes, want 3", len(wiped))
+			}
+			for i, q := range wiped {
+				for _, row := range q {
+					if slices.Contains(row, true) {
+						t.Fatalf("QR code %d still has dark modules", i)
+					}
+				}
+			}
+			// The caller's buffers are borrowed, not destroyed
+			if string(w.PrivateKey.Bytes()) != testKey || string(w.Mnemonic.Bytes()) != testMnemonic {
+				t.Error("Render() changed the wallet's secrets")
+			}
+		})
+	}
+}
+
+func TestWipeFields(t *testing.T) {
+	fields, err := newTestWallet(t).fields()
+	if err != nil {
+		t.Fatalf("fields() error: %v", err)
+	}
+	var words *security.SecretBuffer
+	for _, f := range fields {
+		if f.Words != nil {
+			words = f.Words
+		}
+	}
+	if words == nil || string(words.Bytes()) != "1.test 2.test 3.test 4.test 5.test 6.test 7.test 8.test 9.test 10.test 11.test 12.junk" {
+		t.Fatalf("numbered words = %q", words.Bytes())
+	}
+	wipeFields(fields)
+	if !words.Destroyed() {
+		t.Error("wipeFields() did not destroy the numbered words")
+	}
+}
@@ -0,0 +1,204 @@
+// Package schnorr implements BIP340 Schnorr signatures over secp256k1 and
+// the BIP341 Taproot key tweak. Unlike btcec's schnorr package it signs
+// messages of any length, as the BIP340 test vectors 15-18 require.
+package schnorr
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	btcschnorr "github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/spark8899/gowallet/internal/commonPrivateKey"
+	"github.com/spark8899/gowallet/internal/security"
+)
+
+var (
+	// ErrInvalidSignature indicates a signature that does not verify
+	ErrInvalidSignature = errors.New("invalid Schnorr signature")
+	// ErrInvalidPublicKey indicates bytes that are not a 32-byte x-only key
+	// of a point on the curve
+	ErrInvalidPublicKey = errors.New("invalid x-only public key")
+	// ErrAuxRandLength indicates auxiliary randomness that is not 32 bytes
+	ErrAuxRandLength = errors.New("auxiliary randomness must be 32 bytes")
+)
+
+const (
+	// PublicKeySize is the length of an x-only public key
+	PublicKeySize = 32
+	// SignatureSize is the length of a signature
+	SignatureSize = 64
+)
+
+// TaggedHash is BIP340's hash_tag(x) = SHA256(SHA256(tag) || SHA256(tag) || x)
+func TaggedHash(tag string, msgs ...[]byte) [32]byte {
+	tagHash := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+	for _, m := range msgs {
+		h.Write(m)
+	}
+	var out [32]byte
+	h.Sum(out[:0])
+	return out
+}
+
+// XOnlyPublicKey returns the 32-byte x coordinate BIP340 uses as the
+// public key, dropping the parity of y
+func XOnlyPublicKey(pub *btcec.PublicKey) []byte {
+	return pub.SerializeCompressed()[1:]
+}
+
+// ParseXOnlyPublicKey lifts a 32-byte x-only key to the point with that x
+// and even y
+func ParseXOnlyPublicKey(b []byte) (*btcec.PublicKey, error) {
+	if len(b) != PublicKeySize {
+		return nil, fmt.Errorf("%w: %d bytes", ErrInvalidPublicKey, len(b))
+	}
+	pub, err := btcschnorr.ParsePubKey(b)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPublicKey, err)
+	}
+	return pub, nil
+}
+
+// Sign signs msg with the private key in secret (hex or WIF). auxRand is
+// the 32 bytes of auxiliary randomness mixed into the nonce; nil draws
+// fresh bytes from crypto/rand. The signature is verified before it is
+// returned.
+func Sign(secret *security.SecretBuffer, msg, auxRand []byte) ([]byte, error) {
+	d, err := privateScalar(secret)
+	if err != nil {
+		return nil, err
+	}
+	defer d.Zero()
+	return sign(d, msg, auxRand)
+}
+
+// privateScalar parses the key in secret as a scalar
+func privateScalar(secret *security.SecretBuffer) (*btcec.ModNScalar, error) {
+	raw, err := commonPrivateKey.PrivateKeyBytes(secret)
+	if err != nil {
+		return nil, err
+	}
+	defer raw.Destroy()
+	d := new(btcec.ModNScalar)
+	if overflow := d.SetByteSlice(raw.Bytes()); overflow || d.IsZero() {
+		d.Zero()
+		return nil, errors.New("private key is out of range")
+	}
+	return d, nil
+}
+
+// sign is the BIP340 signing algorithm; key is not modified
+func sign(key *btcec.ModNScalar, msg, auxRand []byte) ([]byte, error) {
+	if auxRand == nil {
+		auxRand = make([]byte, 32)
+		if _, err := io.ReadFull(rand.Reader, auxRand); err != nil {
+			return nil, err
+		}
+	}
+	if len(auxRand) != 32 {
+		return nil, ErrAuxRandLength
+	}
+
+	var d btcec.ModNScalar
+	d.Set(key)
+	defer d.Zero()
+	var p btcec.JacobianPoint
+	btcec.ScalarBaseMultNonConst(&d, &p)
+	p.ToAffine()
+	if p.Y.IsOdd() {
+		d.Negate()
+	}
+	px := *p.X.Bytes()
+
+	// t = bytes(d) xor hash_BIP0340/aux(a)
+	t := d.Bytes()
+	defer security.ZeroBytes(t[:])
+	aux := TaggedHash("BIP0340/aux", auxRand)
+	for i := range t {
+		t[i] ^= aux[i]
+	}
+
+	nonce := TaggedHash("BIP0340/nonce", t[:], px[:], msg)
+	defer security.ZeroBytes(nonce[:])
+	var k btcec.ModNScalar
+	k.SetByteSlice(nonce[:])
+	defer k.Zero()
+	if k.IsZero() {
+		return nil, errors.New("nonce is zero")
+	}
+
+	var r btcec.JacobianPoint
+	btcec.ScalarBaseMultNonConst(&k, &r)
+	r.ToAffine()
+	if r.Y.IsOdd() {
+		k.Negate()
+	}
+	rx := *r.X.Bytes()
+
+	e := challenge(rx[:], px[:], msg)
+	var s btcec.ModNScalar
+	s.Mul2(&e, &d).Add(&k)
+	sBytes := s.Bytes()
+
+	sig := make([]byte, SignatureSize)
+	copy(sig, rx[:])
+	copy(sig[32:], sBytes[:])
+	if err := Verify(px[:], msg, sig); err != nil {
+		return nil, fmt.Errorf("signature failed verification: %w", err)
+	}
+	return sig, nil
+}
+
+// Verify checks a 64-byte signature of msg by the 32-byte x-only public key
+func Verify(pubKey, msg, sig []byte) error {
+	pub, err := ParseXOnlyPublicKey(pubKey)
+	if err != nil {
+		return err
+	}
+	if len(sig) != SignatureSize {
+		return fmt.Errorf("%w: %d bytes", ErrInvalidSignature, len(sig))
+	}
+	var r btcec.FieldVal
+	if overflow := r.SetByteSlice(sig[:32]); overflow {
+		return fmt.Errorf("%w: r is not below the field size", ErrInvalidSignature)
+	}
+	var s btcec.ModNScalar
+	if overflow := s.SetByteSlice(sig[32:]); overflow {
+		return fmt.Errorf("%w: s is not below the curve order", ErrInvalidSignature)
+	}
+
+	// R = s*G - e*P
+	e := challenge(sig[:32], pubKey, msg)
+	e.Negate()
+	var p, sG, eP, point btcec.JacobianPoint
+	pub.AsJacobian(&p)
+	btcec.ScalarBaseMultNonConst(&s, &sG)
+	btcec.ScalarMultNonConst(&e, &p, &eP)
+	btcec.AddNonConst(&sG, &eP, &point)
+	if (point.X.IsZero() && point.Y.IsZero()) || point.Z.IsZero() {
+		return fmt.Errorf("%w: R is the point at infinity", ErrInvalidSignature)
+	}
+	point.ToAffine()
+	if point.Y.IsOdd() {
+		return fmt.Errorf("%w: R has odd y", ErrInvalidSignature)
+	}
+	if !point.X.Equals(&r) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// challenge is e = int(hash_BIP0340/challenge(r || P || m)) mod n
+func challenge(r, pubKey, msg []byte) btcec.ModNScalar {
+	h := TaggedHash("BIP0340/challenge", r, pubKey, msg)
+	var e btcec.ModNScalar
+	e.SetByteSlice(h[:])
+	return e
+}
@@ -0,0 +1,122 @@
+package schnorr
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/spark8899/gowallet/internal/commonPrivateKey"
+	"github.com/spark8899/gowallet/internal/security"
+)
+
+func secretString(t *testing.T, s string) *security.SecretBuffer {
+	t.Helper()
+	secret, err := security.NewSecretBufferFromString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(secret.Destroy)
+	return secret
+}
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// xOnlyKey returns the x-only public key of a hex private key
+func xOnlyKey(t *testing.T, key string) []byte {
+	t.Helper()
+	pub, err := commonPrivateKey.CompressedPublicKeyBytes(secretString(t, key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pub[1:]
+}
+
+// TestBIP340Vectors runs the test-vectors.csv of BIP340
+func TestBIP340Vectors(t *testing.T) {
+	f, err := os.Open("testdata/bip340-vectors.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 20 {
+		t.Fatalf("read %d records, want a header and 19 vectors", len(records))
+	}
+
+	for _, r := range records[1:] {
+		index, secretKey, pubKey, auxRand, msg, sig, result, comment := r[0], r[1], r[2], r[3], r[4], r[5], r[6], r[7]
+		pub := mustHex(t, pubKey)
+		m := mustHex(t, msg)
+		want := mustHex(t, sig)
+
+		if secretKey != "" {
+			got, err := Sign(secretString(t, secretKey), m, mustHex(t, auxRand))
+			if err != nil {
+				t.Errorf("vector %s: Sign error = %v", index, err)
+			} else if !bytes.Equal(got, want) {
+				t.Errorf("vector %s: Sign = %X, want %s", index, got, sig)
+			}
+			if derived := xOnlyKey(t, secretKey); !bytes.Equal(derived, pub) {
+				t.Errorf("vector %s: public key = %X, want %s", index, derived, pubKey)
+			}
+		}
+
+		err := Verify(pub, m, want)
+		if valid := err == nil; valid != (result == "TRUE") {
+			t.Errorf("vector %s (%s): Verify error = %v, want valid %s", index, comment, err, result)
+		}
+	}
+}
+
+func TestVerify_Errors(t *testing.T) {
+	pub := mustHex(t, "DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659")
+	msg := mustHex(t, "243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89")
+	sig := mustHex(t, "6896BD60EEAE296DB48A229FF71DFE071BDE413E6D43F917DC8DCF8C78DE33418906D11AC976ABCCB20B091292BFF4EA897EFCB639EA871CFA95F6DE339E4B0A")
+
+	if err := Verify(pub[:31], msg, sig); !errors.Is(err, ErrInvalidPublicKey) {
+		t.Errorf("Verify(31-byte key) error = %v, want ErrInvalidPublicKey", err)
+	}
+	if err := Verify(pub, msg, sig[:63]); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify(63-byte signature) error = %v, want ErrInvalidSignature", err)
+	}
+	if err := Verify(pub, msg[1:], sig); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify(other message) error = %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestSign_AuxRand(t *testing.T) {
+	key := secretString(t, "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+	if _, err := Sign(key, []byte("hello"), make([]byte, 31)); !errors.Is(err, ErrAuxRandLength) {
+		t.Errorf("Sign(31-byte aux) error = %v, want ErrAuxRandLength", err)
+	}
+
+	// fresh randomness gives a different, still valid, signature each time
+	pub := xOnlyKey(t, string(key.Bytes()))
+	a, err := Sign(key, []byte("hello"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Sign(key, []byte("hello"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(a, b) {
+		t.Error("Sign with nil aux returned the same signature twice")
+	}
+	if err := Verify(pub, []byte("hello"), b); err != nil {
+		t.Error(err)
+	}
+}
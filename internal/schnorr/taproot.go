@@ -0,0 +1,108 @@
+package schnorr
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/spark8899/gowallet/internal/security"
+)
+
+// ErrInvalidMerkleRoot indicates a script tree root that is not 32 bytes
+var ErrInvalidMerkleRoot = errors.New("script tree merkle root must be 32 bytes")
+
+// tapTweak returns t = int(hash_TapTweak(x(P) || merkleRoot)), failing in
+// the negligible case t >= n
+func tapTweak(internalX, merkleRoot []byte) (*btcec.ModNScalar, error) {
+	if len(merkleRoot) != 0 && len(merkleRoot) != 32 {
+		return nil, fmt.Errorf("%w: %d bytes", ErrInvalidMerkleRoot, len(merkleRoot))
+	}
+	h := TaggedHash("TapTweak", internalX, merkleRoot)
+	t := new(btcec.ModNScalar)
+	if overflow := t.SetByteSlice(h[:]); overflow {
+		return nil, errors.New("taproot tweak is not below the curve order")
+	}
+	return t, nil
+}
+
+// TweakPublicKey returns the BIP341 output key Q = P + tG of an internal
+// key, P taken with even y. A nil merkleRoot commits to no scripts, the
+// key-path-only output of BIP86.
+func TweakPublicKey(internal *btcec.PublicKey, merkleRoot []byte) (*btcec.PublicKey, error) {
+	internalX := XOnlyPublicKey(internal)
+	t, err := tapTweak(internalX, merkleRoot)
+	if err != nil {
+		return nil, err
+	}
+	p, err := ParseXOnlyPublicKey(internalX)
+	if err != nil {
+		return nil, err
+	}
+
+	var pj, tG, q btcec.JacobianPoint
+	p.AsJacobian(&pj)
+	btcec.ScalarBaseMultNonConst(t, &tG)
+	btcec.AddNonConst(&pj, &tG, &q)
+	if (q.X.IsZero() && q.Y.IsZero()) || q.Z.IsZero() {
+		return nil, errors.New("taproot output key is the point at infinity")
+	}
+	q.ToAffine()
+	return btcec.NewPublicKey(&q.X, &q.Y), nil
+}
+
+// tweakScalar returns the private key of the output key, d negated if its
+// point has odd y, plus t. The caller zeroes it.
+func tweakScalar(secret *security.SecretBuffer, merkleRoot []byte) (*btcec.ModNScalar, error) {
+	d, err := privateScalar(secret)
+	if err != nil {
+		return nil, err
+	}
+	var p btcec.JacobianPoint
+	btcec.ScalarBaseMultNonConst(d, &p)
+	p.ToAffine()
+	if p.Y.IsOdd() {
+		d.Negate()
+	}
+	t, err := tapTweak(p.X.Bytes()[:], merkleRoot)
+	if err != nil {
+		d.Zero()
+		return nil, err
+	}
+	if d.Add(t).IsZero() {
+		return nil, errors.New("tweaked private key is zero")
+	}
+	return d, nil
+}
+
+// TweakPrivateKey returns the private key of TweakPublicKey's output key as
+// 0x-prefixed hex in a new SecretBuffer, for wallets that sign key-path
+// spends with a plain BIP340 signer
+func TweakPrivateKey(secret *security.SecretBuffer, merkleRoot []byte) (*security.SecretBuffer, error) {
+	d, err := tweakScalar(secret, merkleRoot)
+	if err != nil {
+		return nil, err
+	}
+	defer d.Zero()
+	raw := d.Bytes()
+	defer security.ZeroBytes(raw[:])
+
+	out, err := security.NewSecretBuffer(2 + hex.EncodedLen(len(raw)))
+	if err != nil {
+		return nil, err
+	}
+	copy(out.Bytes(), "0x")
+	hex.Encode(out.Bytes()[2:], raw[:])
+	return out, nil
+}
+
+// SignTaproot signs msg for a key-path spend: a BIP340 signature by the
+// output key of the internal key in secret and merkleRoot
+func SignTaproot(secret *security.SecretBuffer, merkleRoot, msg, auxRand []byte) ([]byte, error) {
+	d, err := tweakScalar(secret, merkleRoot)
+	if err != nil {
+		return nil, err
+	}
+	defer d.Zero()
+	return sign(d, msg, auxRand)
+}
@@ -0,0 +1,98 @@
+package schnorr
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/txscript"
+)
+
+func TestTweakPublicKey(t *testing.T) {
+	tests := []struct {
+		internal   string
+		merkleRoot string
+		output     string
+	}{
+		// BIP86 m/86'/0'/0'/0/0 of "abandon ... about"
+		{"cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115", "", "a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c"},
+		// BIP341 wallet-test-vectors.json scriptPubKey 0 and 1
+		{"d6889cb081036e0faefa3a35157ad71086b123b2b144b649798b494c300a961d", "", "53a1f6e454df1aa2776a2814a721372d6258050de330b3c6d10ee8f4e0dda343"},
+		{"187791b6f712a8ea41c8ecdd0ee77fab3e85263b37e1ec18a3651926b3a6cf27", "5b75adecf53548f3ec6ad7d78383bf84cc57b55a3127c72b9a2481752dd88b21", "147c9c57132f6e7ecddba9800bb0c4449251c92a1e60371ee77557b6620f3ea3"},
+	}
+	for _, tt := range tests {
+		internal, err := ParseXOnlyPublicKey(mustHex(t, tt.internal))
+		if err != nil {
+			t.Fatal(err)
+		}
+		output, err := TweakPublicKey(internal, mustHex(t, tt.merkleRoot))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := XOnlyPublicKey(output); !bytes.Equal(got, mustHex(t, tt.output)) {
+			t.Errorf("TweakPublicKey(%s, %q) = %x, want %s", tt.internal, tt.merkleRoot, got, tt.output)
+		}
+	}
+
+	internal, _ := ParseXOnlyPublicKey(mustHex(t, tests[0].internal))
+	if _, err := TweakPublicKey(internal, make([]byte, 31)); !errors.Is(err, ErrInvalidMerkleRoot) {
+		t.Errorf("TweakPublicKey(31-byte root) error = %v, want ErrInvalidMerkleRoot", err)
+	}
+}
+
+func TestTweakPrivateKey(t *testing.T) {
+	// Hardhat account #0 has an odd-y public key, Hardhat #1 an even one
+	keys := []string{
+		"0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80",
+		"0x59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d",
+	}
+	roots := [][]byte{nil, bytes.Repeat([]byte{0x42}, 32)}
+	for _, key := range keys {
+		internal, err := ParseXOnlyPublicKey(xOnlyKey(t, key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, root := range roots {
+			output, err := TweakPublicKey(internal, root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tweaked, err := TweakPrivateKey(secretString(t, key), root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := xOnlyKey(t, string(tweaked.Bytes())); !bytes.Equal(got, XOnlyPublicKey(output)) {
+				t.Errorf("TweakPrivateKey(%s, %x) has public key %x, want %x", key, root, got, XOnlyPublicKey(output))
+			}
+			tweaked.Destroy()
+
+			// agrees with btcd's txscript
+			if want := txscript.ComputeTaprootOutputKey(internal, root); !want.IsEqual(output) {
+				t.Errorf("TweakPublicKey(%s, %x) differs from txscript", key, root)
+			}
+
+			msg := []byte("key path spend")
+			sig, err := SignTaproot(secretString(t, key), root, msg, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := Verify(XOnlyPublicKey(output), msg, sig); err != nil {
+				t.Errorf("SignTaproot(%s, %x) does not verify under the output key: %v", key, root, err)
+			}
+		}
+	}
+}
+
+func TestParseXOnlyPublicKey(t *testing.T) {
+	// lift_x picks the even y
+	pub, err := ParseXOnlyPublicKey(mustHex(t, "8318535b54105d4a7aae60c08fc45f9687181b4fdfc625bd1a753fa7397fed75"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := pub.SerializeCompressed()[0]; got != 0x02 {
+		t.Errorf("ParseXOnlyPublicKey prefix = %#x, want 0x02", got)
+	}
+	if _, err := ParseXOnlyPublicKey(mustHex(t, "038318535b54105d4a7aae60c08fc45f9687181b4fdfc625bd1a753fa7397fed75")); !errors.Is(err, ErrInvalidPublicKey) {
+		t.Errorf("ParseXOnlyPublicKey(33 bytes) error = %v, want ErrInvalidPublicKey", err)
+	}
+}
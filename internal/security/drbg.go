@@ -0,0 +1,55 @@
+package security
+
+import (
+	"crypto/sha256"
+	"errors"
+	"io"
+
+	"golang.org/x/crypto/chacha20"
+)
+
+// ErrEmptySeed indicates a deterministic reader was requested without a seed
+var ErrEmptySeed = errors.New("deterministic seed must not be empty")
+
+// drbgDomain separates gowallet's DRBG keys from other uses of SHA-256(seed)
+const drbgDomain = "gowallet deterministic DRBG v1"
+
+// DeterministicReader is a ChaCha20 keystream used as a reproducible
+// randomness source.
+//
+// UNSAFE: anyone who knows the seed can regenerate every key produced from
+// this reader. It exists only so test fixtures and staging wallets can be
+// recreated byte-for-byte and must never be used for real funds.
+type DeterministicReader struct {
+	cipher *chacha20.Cipher
+}
+
+// NewDeterministicReader returns a reader whose output is the ChaCha20
+// keystream keyed by SHA-256(domain || seed) with an all-zero nonce
+func NewDeterministicReader(seed []byte) (*DeterministicReader, error) {
+	if len(seed) == 0 {
+		return nil, ErrEmptySeed
+	}
+
+	h := sha256.New()
+	h.Write([]byte(drbgDomain))
+	h.Write(seed)
+	key := h.Sum(nil)
+	defer ZeroBytes(key)
+
+	nonce := make([]byte, chacha20.NonceSize)
+	c, err := chacha20.NewUnauthenticatedCipher(key, nonce)
+	if err != nil {
+		return nil, err
+	}
+	return &DeterministicReader{cipher: c}, nil
+}
+
+// Read fills p with the next bytes of the keystream. It never fails.
+func (r *DeterministicReader) Read(p []byte) (int, error) {
+	ZeroBytes(p)
+	r.cipher.XORKeyStream(p, p)
+	return len(p), nil
+}
+
+var _ io.Reader = (*DeterministicReader)(nil)
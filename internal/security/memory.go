@@ -1,6 +1,9 @@
 package security
 
-import "math/big"
+import (
+	"crypto/ecdsa"
+	"math/big"
+)
 
 // ZeroBytes securely overwrites a byte slice with zeros
 //
@@ -15,9 +18,24 @@ func ZeroBytes(b []byte) {
 
 // ZeroBigInt securely overwrites a big.Int with zero
 //
-// This is useful for clearing large numbers like private keys.
+// This is useful for clearing large numbers like private keys. SetInt64(0)
+// alone only shortens the slice, so the backing words (up to their capacity)
+// are overwritten first.
 func ZeroBigInt(n *big.Int) {
 	if n != nil {
+		words := n.Bits()
+		words = words[:cap(words)]
+		for i := range words {
+			words[i] = 0
+		}
 		n.SetInt64(0)
 	}
 }
+
+// ZeroPrivateKey wipes the secret scalar D of an ECDSA private key. The
+// public key is left intact, so values derived from it stay usable.
+func ZeroPrivateKey(k *ecdsa.PrivateKey) {
+	if k != nil {
+		ZeroBigInt(k.D)
+	}
+}
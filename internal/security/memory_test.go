@@ -2,6 +2,9 @@ package security
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"math/big"
 	"testing"
 )
@@ -79,6 +82,51 @@ func TestZeroBigInt(t *testing.T) {
 	}
 }
 
+func TestZeroBigInt_WipesBackingWords(t *testing.T) {
+	n := new(big.Int).SetBytes(bytes.Repeat([]byte{0xff}, 32))
+	words := n.Bits()
+	// Shrink the value so stale words sit beyond len but within cap
+	n.Rsh(n, 128)
+	if len(n.Bits()) >= len(words) {
+		t.Fatalf("Rsh did not shrink the value")
+	}
+
+	ZeroBigInt(n)
+
+	for i, w := range words[:cap(words)] {
+		if w != 0 {
+			t.Errorf("word %d = %#x after ZeroBigInt, want 0", i, w)
+		}
+	}
+	if n.Sign() != 0 {
+		t.Errorf("ZeroBigInt() result is %v, want 0", n)
+	}
+}
+
+func TestZeroPrivateKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x := new(big.Int).Set(key.X)
+	words := key.D.Bits()
+
+	ZeroPrivateKey(key)
+
+	for i, w := range words {
+		if w != 0 {
+			t.Errorf("D word %d = %#x after ZeroPrivateKey, want 0", i, w)
+		}
+	}
+	if key.D.Sign() != 0 {
+		t.Errorf("D = %v, want 0", key.D)
+	}
+	if key.X.Cmp(x) != 0 {
+		t.Error("ZeroPrivateKey() modified the public key")
+	}
+	ZeroPrivateKey(nil)
+}
+
 func BenchmarkZeroBytes(b *testing.B) {
 	data := make([]byte, 32) // 256 bits like a private key
 	for i := 0; i < b.N; i++ {
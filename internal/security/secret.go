@@ -0,0 +1,170 @@
+package security
+
+import (
+	"crypto/subtle"
+	"errors"
+	"io"
+	"runtime"
+	"sync"
+)
+
+// ErrSecretDestroyed indicates use of a SecretBuffer after Destroy
+var ErrSecretDestroyed = errors.New("secret buffer has been destroyed")
+
+// ErrInvalidSecretSize indicates a negative buffer size
+var ErrInvalidSecretSize = errors.New("secret buffer size must not be negative")
+
+// SecretBuffer holds sensitive data such as private keys, seeds and
+// mnemonics outside the Go heap.
+//
+// On Unix the data lives in its own mmap'd region: the pages are mlock'd so
+// they are not written to swap, and PROT_NONE guard pages on both sides turn
+// buffer overruns into a crash instead of a silent read of neighbouring
+// secrets. The data ends at the trailing guard page. Where mlock is not
+// permitted (e.g. RLIMIT_MEMLOCK is exhausted) the buffer still works and
+// Locked reports false. Other platforms fall back to heap memory.
+//
+// Destroy wipes and unmaps the memory; it is also run by a finalizer as a
+// last resort, but callers should always defer it explicitly. Unlike Go
+// strings, the contents can never outlive Destroy.
+type SecretBuffer struct {
+	mu     sync.Mutex
+	mem    []byte // the whole mapping, including guard pages
+	data   []byte
+	locked bool
+}
+
+// NewSecretBuffer allocates a zeroed buffer of size bytes
+func NewSecretBuffer(size int) (*SecretBuffer, error) {
+	if size < 0 {
+		return nil, ErrInvalidSecretSize
+	}
+	mem, data, locked, err := allocateSecret(size)
+	if err != nil {
+		return nil, err
+	}
+	s := &SecretBuffer{mem: mem, data: data, locked: locked}
+	runtime.SetFinalizer(s, (*SecretBuffer).Destroy)
+	return s, nil
+}
+
+// NewSecretBufferFrom copies b into a new buffer and wipes b
+func NewSecretBufferFrom(b []byte) (*SecretBuffer, error) {
+	defer ZeroBytes(b)
+	s, err := NewSecretBuffer(len(b))
+	if err != nil {
+		return nil, err
+	}
+	copy(s.data, b)
+	return s, nil
+}
+
+// NewSecretBufferFromString copies s into a new buffer. The string itself
+// is immutable and cannot be wiped, so this is only for input that already
+// arrived as a string, such as command line arguments.
+func NewSecretBufferFromString(str string) (*SecretBuffer, error) {
+	s, err := NewSecretBuffer(len(str))
+	if err != nil {
+		return nil, err
+	}
+	copy(s.data, str)
+	return s, nil
+}
+
+// ReadSecret reads r to EOF into a new buffer without the heap copies of
+// io.ReadAll. sizeHint, such as a file's size from Stat, sets the first
+// allocation; when the data outgrows it the buffer doubles, and every
+// outgrown buffer is destroyed. The result is trimmed to the bytes read.
+func ReadSecret(r io.Reader, sizeHint int) (*SecretBuffer, error) {
+	// One spare byte lets a read of exactly sizeHint bytes reach EOF
+	// without growing
+	buf, err := NewSecretBuffer(max(sizeHint, 512-1) + 1)
+	if err != nil {
+		return nil, err
+	}
+	n := 0
+	for {
+		if n == buf.Len() {
+			grown, err := NewSecretBuffer(2 * n)
+			if err != nil {
+				buf.Destroy()
+				return nil, err
+			}
+			copy(grown.Bytes(), buf.Bytes())
+			buf.Destroy()
+			buf = grown
+		}
+		m, err := r.Read(buf.Bytes()[n:])
+		n += m
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			buf.Destroy()
+			return nil, err
+		}
+	}
+
+	out, err := NewSecretBuffer(n)
+	if err == nil {
+		copy(out.Bytes(), buf.Bytes())
+	}
+	buf.Destroy()
+	return out, err
+}
+
+// Bytes returns the buffer contents. The slice aliases the locked memory
+// and must not be used after Destroy; it is nil once destroyed.
+func (s *SecretBuffer) Bytes() []byte {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data
+}
+
+// Len returns the number of bytes in the buffer
+func (s *SecretBuffer) Len() int {
+	return len(s.Bytes())
+}
+
+// Locked reports whether the memory is mlock'd
+func (s *SecretBuffer) Locked() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.locked
+}
+
+// Destroyed reports whether Destroy has been called
+func (s *SecretBuffer) Destroyed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.mem == nil
+}
+
+// Equal compares the contents with b in constant time
+func (s *SecretBuffer) Equal(b []byte) bool {
+	return subtle.ConstantTimeCompare(s.Bytes(), b) == 1
+}
+
+// String hides the contents so secrets are not printed by accident
+func (s *SecretBuffer) String() string {
+	return "[secret]"
+}
+
+// Destroy wipes and releases the buffer. It is safe to call more than once.
+func (s *SecretBuffer) Destroy() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mem == nil {
+		return
+	}
+	ZeroBytes(s.data)
+	releaseSecret(s.mem, s.locked)
+	s.mem, s.data, s.locked = nil, nil, false
+	runtime.SetFinalizer(s, nil)
+}
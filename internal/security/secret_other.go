@@ -0,0 +1,14 @@
+//go:build !unix
+
+package security
+
+// allocateSecret falls back to heap memory where mmap and mlock are not
+// available. The buffer is still wiped on Destroy.
+func allocateSecret(size int) (mem, data []byte, locked bool, err error) {
+	mem = make([]byte, size+1)
+	return mem, mem[:size:size], false, nil
+}
+
+var releaseSecret = func(mem []byte, locked bool) {
+	ZeroBytes(mem)
+}
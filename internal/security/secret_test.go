@@ -0,0 +1,156 @@
+package security
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestNewSecretBuffer(t *testing.T) {
+	for _, size := range []int{0, 1, 32, 4096, 10000} {
+		s, err := NewSecretBuffer(size)
+		if err != nil {
+			t.Fatalf("NewSecretBuffer(%d) error: %v", size, err)
+		}
+		b := s.Bytes()
+		if len(b) != size || s.Len() != size {
+			t.Errorf("NewSecretBuffer(%d) length = %d", size, len(b))
+		}
+		if !bytes.Equal(b, make([]byte, size)) {
+			t.Errorf("NewSecretBuffer(%d) is not zeroed", size)
+		}
+		// Appending must reallocate rather than write past the buffer
+		if cap(b) != size {
+			t.Errorf("NewSecretBuffer(%d) capacity = %d, want %d", size, cap(b), size)
+		}
+		s.Destroy()
+	}
+
+	if _, err := NewSecretBuffer(-1); !errors.Is(err, ErrInvalidSecretSize) {
+		t.Errorf("NewSecretBuffer(-1) error = %v, want %v", err, ErrInvalidSecretSize)
+	}
+}
+
+func TestSecretBufferFrom(t *testing.T) {
+	src := []byte{1, 2, 3, 4}
+	s, err := NewSecretBufferFrom(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Destroy()
+
+	if !s.Equal([]byte{1, 2, 3, 4}) {
+		t.Errorf("NewSecretBufferFrom() = %v", s.Bytes())
+	}
+	if !bytes.Equal(src, make([]byte, 4)) {
+		t.Error("NewSecretBufferFrom() did not wipe the source")
+	}
+
+	fromString, err := NewSecretBufferFromString("abandon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fromString.Destroy()
+	if string(fromString.Bytes()) != "abandon" {
+		t.Errorf("NewSecretBufferFromString() = %q", fromString.Bytes())
+	}
+	if fromString.Equal([]byte("abandoN")) {
+		t.Error("Equal() matched different contents")
+	}
+}
+
+func TestSecretBufferDestroy(t *testing.T) {
+	// Keep the mapping alive so the memory can be inspected after Destroy
+	var released []byte
+	original := releaseSecret
+	releaseSecret = func(mem []byte, locked bool) { released = mem }
+	defer func() {
+		releaseSecret = original
+		if released != nil {
+			original(released, false)
+		}
+	}()
+
+	s, err := NewSecretBufferFromString("0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := s.Bytes()
+
+	s.Destroy()
+	if !bytes.Equal(data, make([]byte, len(data))) {
+		t.Errorf("Destroy() left secret data in memory: %q", data)
+	}
+	if released == nil {
+		t.Fatal("Destroy() did not release the memory")
+	}
+	if s.Bytes() != nil || s.Len() != 0 || !s.Destroyed() || s.Locked() {
+		t.Error("destroyed buffer still exposes data")
+	}
+
+	// Destroy is idempotent and nil safe
+	s.Destroy()
+	var nilBuffer *SecretBuffer
+	nilBuffer.Destroy()
+}
+
+func TestReadSecret(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789abcdef"), 300) // 4800 bytes
+	for _, hint := range []int{0, 1, 100, len(data) - 1, len(data), len(data) + 1, 10000} {
+		// short reads of 7 bytes exercise the growth path
+		s, err := ReadSecret(&shortReader{data: data, n: 7}, hint)
+		if err != nil {
+			t.Fatalf("ReadSecret(hint %d) error: %v", hint, err)
+		}
+		if !s.Equal(data) || s.Len() != len(data) {
+			t.Errorf("ReadSecret(hint %d) read %d bytes, want %d", hint, s.Len(), len(data))
+		}
+		s.Destroy()
+	}
+
+	s, err := ReadSecret(bytes.NewReader(nil), 0)
+	if err != nil || s.Len() != 0 {
+		t.Errorf("ReadSecret(empty) = %d bytes, %v", s.Len(), err)
+	}
+	s.Destroy()
+
+	wantErr := errors.New("read failed")
+	if _, err := ReadSecret(&shortReader{data: data, n: 7, err: wantErr}, 0); !errors.Is(err, wantErr) {
+		t.Errorf("ReadSecret() error = %v, want %v", err, wantErr)
+	}
+}
+
+// shortReader returns at most n bytes per Read, then err (io.EOF if nil)
+type shortReader struct {
+	data []byte
+	n    int
+	err  error
+}
+
+func (r *shortReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		return 0, io.EOF
+	}
+	n := copy(p[:min(len(p), r.n)], r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestSecretBufferString(t *testing.T) {
+	s, err := NewSecretBufferFromString("correct horse battery staple")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Destroy()
+
+	for _, out := range []string{s.String(), fmt.Sprint(s), fmt.Sprintf("%v", s)} {
+		if out != "[secret]" {
+			t.Errorf("formatted SecretBuffer = %q, want [secret]", out)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+//go:build unix
+
+package security
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// allocateSecret maps a guard page, enough pages for size bytes and a second
+// guard page, locks the data pages and returns the data right-aligned
+// against the trailing guard page
+func allocateSecret(size int) (mem, data []byte, locked bool, err error) {
+	pageSize := os.Getpagesize()
+	dataPages := (size + pageSize - 1) / pageSize
+	if dataPages == 0 {
+		dataPages = 1
+	}
+	total := (dataPages + 2) * pageSize
+
+	mem, err = unix.Mmap(-1, 0, total, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_PRIVATE|unix.MAP_ANON)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("failed to map secret memory: %w", err)
+	}
+	if err := unix.Mprotect(mem[:pageSize], unix.PROT_NONE); err != nil {
+		unix.Munmap(mem)
+		return nil, nil, false, fmt.Errorf("failed to protect guard page: %w", err)
+	}
+	if err := unix.Mprotect(mem[total-pageSize:], unix.PROT_NONE); err != nil {
+		unix.Munmap(mem)
+		return nil, nil, false, fmt.Errorf("failed to protect guard page: %w", err)
+	}
+
+	inner := mem[pageSize : total-pageSize]
+	locked = unix.Mlock(inner) == nil
+	return mem, inner[len(inner)-size : len(inner) : len(inner)], locked, nil
+}
+
+// releaseSecret is a variable so tests can keep the mapping alive and
+// inspect the wiped memory
+var releaseSecret = func(mem []byte, locked bool) {
+	pageSize := os.Getpagesize()
+	inner := mem[pageSize : len(mem)-pageSize]
+	ZeroBytes(inner)
+	if locked {
+		unix.Munlock(inner)
+	}
+	unix.Munmap(mem)
+}
@@ -0,0 +1,53 @@
+//go:build unix
+
+package security
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+func TestSecretBufferLocked(t *testing.T) {
+	s, err := NewSecretBuffer(32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Destroy()
+	if !s.Locked() {
+		// mlock can be refused by RLIMIT_MEMLOCK in containers
+		t.Log("secret memory is not mlock'd on this system")
+	}
+}
+
+var guardPageSink byte
+
+// TestSecretBufferGuardPage reads one byte past the end of a buffer in a
+// subprocess and expects the guard page to crash it
+func TestSecretBufferGuardPage(t *testing.T) {
+	if os.Getenv("GOWALLET_GUARD_PAGE_CRASH") == "1" {
+		s, err := NewSecretBuffer(32)
+		if err != nil {
+			os.Exit(3)
+		}
+		b := s.Bytes()
+		past := (*byte)(unsafe.Add(unsafe.Pointer(&b[len(b)-1]), 1))
+		guardPageSink = *past
+		os.Exit(0)
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestSecretBufferGuardPage$")
+	cmd.Env = append(os.Environ(), "GOWALLET_GUARD_PAGE_CRASH=1")
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("reading past the secret buffer did not fault:\n%s", output)
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 3 {
+		t.Fatalf("subprocess failed to allocate:\n%s", output)
+	}
+	if !strings.Contains(string(output), "fault") {
+		t.Errorf("expected a memory fault, got:\n%s", output)
+	}
+}
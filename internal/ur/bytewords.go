@@ -0,0 +1,121 @@
+package ur
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"strings"
+)
+
+var (
+	// ErrInvalidByteword indicates text that is not a valid bytewords encoding
+	ErrInvalidByteword = errors.New("invalid bytewords")
+	// ErrBytewordsChecksum indicates a bytewords CRC32 mismatch
+	ErrBytewordsChecksum = errors.New("bytewords checksum mismatch")
+)
+
+// bytewords is the BCR-2020-012 word list: byte b encodes as bytewords[b]
+var bytewords = strings.Fields(`
+able acid also apex aqua arch atom aunt away axis back bald barn belt beta bias
+blue body brag brew bulb buzz calm cash cats chef city claw code cola cook cost
+crux curl cusp cyan dark data days deli dice diet door down draw drop drum dull
+duty each easy echo edge epic even exam exit eyes fact fair fern figs film fish
+fizz flap flew flux foxy free frog fuel fund gala game gear gems gift girl glow
+good gray grim guru gush gyro half hang hard hawk heat help high hill holy hope
+horn huts iced idea idle inch inky into iris iron item jade jazz join jolt jowl
+judo jugs jump junk jury keep keno kept keys kick kiln king kite kiwi knob lamb
+lava lazy leaf legs liar limp lion list logo loud love luau luck lung main many
+math maze memo menu meow mild mint miss monk nail navy need news next noon note
+numb obey oboe omit onyx open oval owls paid part peck play plus poem pool pose
+puff puma purr quad quiz race ramp real redo rich road rock roof ruby ruin runs
+rust safe saga scar sets silk skew slot soap solo song stub surf swan taco task
+taxi tent tied time tiny toil tomb toys trip tuna twin ugly undo unit urge user
+vast very veto vial vibe view visa void vows wall wand warm wasp wave waxy webs
+what when whiz wolf work yank yawn yell yoga yurt zaps zero zest zinc zone zoom`)
+
+var (
+	bytewordIndex = map[string]byte{}
+	minimalIndex  = map[string]byte{}
+)
+
+func init() {
+	for i, w := range bytewords {
+		bytewordIndex[w] = byte(i)
+		minimalIndex[w[:1]+w[3:]] = byte(i)
+	}
+}
+
+// Style selects how bytewords are written
+type Style int
+
+const (
+	// Standard separates full words with spaces
+	Standard Style = iota
+	// URI separates full words with hyphens
+	URI
+	// Minimal concatenates the first and last letter of each word, as used
+	// in UR strings
+	Minimal
+)
+
+// EncodeBytewords encodes data followed by its CRC32 checksum
+func EncodeBytewords(data []byte, style Style) string {
+	withChecksum := binary.BigEndian.AppendUint32(append([]byte(nil), data...), crc32.ChecksumIEEE(data))
+
+	words := make([]string, len(withChecksum))
+	for i, b := range withChecksum {
+		w := bytewords[b]
+		if style == Minimal {
+			w = w[:1] + w[3:]
+		}
+		words[i] = w
+	}
+	switch style {
+	case URI:
+		return strings.Join(words, "-")
+	case Minimal:
+		return strings.Join(words, "")
+	}
+	return strings.Join(words, " ")
+}
+
+// DecodeBytewords decodes text in the given style (case-insensitive) and
+// verifies and strips the checksum
+func DecodeBytewords(text string, style Style) ([]byte, error) {
+	text = strings.ToLower(text)
+
+	var words []string
+	index := bytewordIndex
+	switch style {
+	case Standard:
+		words = strings.Split(text, " ")
+	case URI:
+		words = strings.Split(text, "-")
+	case Minimal:
+		if len(text)%2 != 0 {
+			return nil, ErrInvalidByteword
+		}
+		for i := 0; i < len(text); i += 2 {
+			words = append(words, text[i:i+2])
+		}
+		index = minimalIndex
+	}
+
+	data := make([]byte, len(words))
+	for i, w := range words {
+		b, ok := index[w]
+		if !ok {
+			return nil, ErrInvalidByteword
+		}
+		data[i] = b
+	}
+	if len(data) < 5 {
+		return nil, ErrInvalidByteword
+	}
+
+	body, checksum := data[:len(data)-4], data[len(data)-4:]
+	if binary.BigEndian.Uint32(checksum) != crc32.ChecksumIEEE(body) {
+		return nil, ErrBytewordsChecksum
+	}
+	return body, nil
+}
@@ -0,0 +1,59 @@
+package ur
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestBytewords(t *testing.T) {
+	if len(bytewords) != 256 || len(minimalIndex) != 256 {
+		t.Fatalf("word list has %d words, %d distinct minimal forms", len(bytewords), len(minimalIndex))
+	}
+
+	// Vector from the Blockchain Commons reference implementation
+	data := []byte{0, 1, 2, 128, 255}
+	tests := []struct {
+		style Style
+		want  string
+	}{
+		{Standard, "able acid also lava zoom jade need echo taxi"},
+		{URI, "able-acid-also-lava-zoom-jade-need-echo-taxi"},
+		{Minimal, "aeadaolazmjendeoti"},
+	}
+	for _, tt := range tests {
+		got := EncodeBytewords(data, tt.style)
+		if got != tt.want {
+			t.Errorf("EncodeBytewords(style %d) = %q, want %q", tt.style, got, tt.want)
+		}
+		decoded, err := DecodeBytewords(tt.want, tt.style)
+		if err != nil || !bytes.Equal(decoded, data) {
+			t.Errorf("DecodeBytewords(%q) = %x, %v", tt.want, decoded, err)
+		}
+	}
+
+	if decoded, err := DecodeBytewords("AEADAOLAZMJENDEOTI", Minimal); err != nil || !bytes.Equal(decoded, data) {
+		t.Errorf("upper case: %x, %v", decoded, err)
+	}
+}
+
+func TestDecodeBytewords_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want error
+	}{
+		{"bad checksum", "aeadaolazmjendeotk", ErrBytewordsChecksum},
+		{"unknown word", "aeadaolazmjendeoxx", ErrInvalidByteword},
+		{"odd length", "aeadaolazmjendeot", ErrInvalidByteword},
+		{"too short", "aeadao", ErrInvalidByteword},
+		{"empty", "", ErrInvalidByteword},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeBytewords(tt.text, Minimal); !errors.Is(err, tt.want) {
+				t.Errorf("DecodeBytewords(%q) error = %v, want %v", tt.text, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package ur
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidCBOR indicates malformed or unsupported CBOR
+var ErrInvalidCBOR = errors.New("invalid CBOR")
+
+// maxCBORDepth bounds nesting so hostile input cannot exhaust the stack
+const maxCBORDepth = 16
+
+// CBOR major types
+const (
+	majorUint   = 0
+	majorNegInt = 1
+	majorBytes  = 2
+	majorText   = 3
+	majorArray  = 4
+	majorMap    = 5
+	majorTag    = 6
+	majorSimple = 7
+)
+
+// cborTag is a decoded tagged item
+type cborTag struct {
+	Number  uint64
+	Content any
+}
+
+// cborWriter builds canonical CBOR: shortest-form heads and definite
+// lengths. Callers write map keys in ascending order.
+type cborWriter struct {
+	buf []byte
+}
+
+func (w *cborWriter) head(major byte, n uint64) {
+	m := major << 5
+	switch {
+	case n < 24:
+		w.buf = append(w.buf, m|byte(n))
+	case n <= 0xff:
+		w.buf = append(w.buf, m|24, byte(n))
+	case n <= 0xffff:
+		w.buf = binary.BigEndian.AppendUint16(append(w.buf, m|25), uint16(n))
+	case n <= 0xffffffff:
+		w.buf = binary.BigEndian.AppendUint32(append(w.buf, m|26), uint32(n))
+	default:
+		w.buf = binary.BigEndian.AppendUint64(append(w.buf, m|27), n)
+	}
+}
+
+func (w *cborWriter) uint(n uint64)     { w.head(majorUint, n) }
+func (w *cborWriter) array(n int)       { w.head(majorArray, uint64(n)) }
+func (w *cborWriter) mapHeader(n int)   { w.head(majorMap, uint64(n)) }
+func (w *cborWriter) tag(number uint64) { w.head(majorTag, number) }
+
+func (w *cborWriter) bytes(b []byte) {
+	w.head(majorBytes, uint64(len(b)))
+	w.buf = append(w.buf, b...)
+}
+
+func (w *cborWriter) text(s string) {
+	w.head(majorText, uint64(len(s)))
+	w.buf = append(w.buf, s...)
+}
+
+func (w *cborWriter) bool(b bool) {
+	if b {
+		w.buf = append(w.buf, 0xf5)
+	} else {
+		w.buf = append(w.buf, 0xf4)
+	}
+}
+
+// decodeCBOR decodes one item that must span all of data. Items decode to
+// uint64, int64, []byte, string, []any, map[uint64]any, cborTag, bool or
+// nil; maps must have unsigned integer keys, as every UR type used here does.
+func decodeCBOR(data []byte) (any, error) {
+	v, rest, err := decodeItem(data, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("%w: %d trailing bytes", ErrInvalidCBOR, len(rest))
+	}
+	return v, nil
+}
+
+func decodeItem(data []byte, depth int) (any, []byte, error) {
+	if depth > maxCBORDepth {
+		return nil, nil, fmt.Errorf("%w: nested too deeply", ErrInvalidCBOR)
+	}
+	if len(data) == 0 {
+		return nil, nil, fmt.Errorf("%w: unexpected end of data", ErrInvalidCBOR)
+	}
+	major, info := data[0]>>5, data[0]&0x1f
+	data = data[1:]
+
+	if major == majorSimple {
+		switch info {
+		case 20:
+			return false, data, nil
+		case 21:
+			return true, data, nil
+		case 22:
+			return nil, data, nil
+		}
+		return nil, nil, fmt.Errorf("%w: unsupported simple value %d", ErrInvalidCBOR, info)
+	}
+
+	var n uint64
+	switch {
+	case info < 24:
+		n = uint64(info)
+	case info <= 27:
+		size := 1 << (info - 24)
+		if len(data) < size {
+			return nil, nil, fmt.Errorf("%w: unexpected end of data", ErrInvalidCBOR)
+		}
+		for _, b := range data[:size] {
+			n = n<<8 | uint64(b)
+		}
+		data = data[size:]
+	default:
+		return nil, nil, fmt.Errorf("%w: indefinite lengths are not supported", ErrInvalidCBOR)
+	}
+
+	switch major {
+	case majorUint:
+		return n, data, nil
+	case majorNegInt:
+		if n > 1<<63-1 {
+			return nil, nil, fmt.Errorf("%w: integer out of range", ErrInvalidCBOR)
+		}
+		return -1 - int64(n), data, nil
+	case majorBytes, majorText:
+		if n > uint64(len(data)) {
+			return nil, nil, fmt.Errorf("%w: unexpected end of data", ErrInvalidCBOR)
+		}
+		if major == majorText {
+			return string(data[:n]), data[n:], nil
+		}
+		return append([]byte(nil), data[:n]...), data[n:], nil
+	case majorArray:
+		// Every item takes at least one byte
+		if n > uint64(len(data)) {
+			return nil, nil, fmt.Errorf("%w: unexpected end of data", ErrInvalidCBOR)
+		}
+		items := make([]any, n)
+		for i := range items {
+			var err error
+			if items[i], data, err = decodeItem(data, depth+1); err != nil {
+				return nil, nil, err
+			}
+		}
+		return items, data, nil
+	case majorMap:
+		if n > uint64(len(data))/2 {
+			return nil, nil, fmt.Errorf("%w: unexpected end of data", ErrInvalidCBOR)
+		}
+		m := make(map[uint64]any, n)
+		for i := uint64(0); i < n; i++ {
+			k, rest, err := decodeItem(data, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			key, ok := k.(uint64)
+			if !ok {
+				return nil, nil, fmt.Errorf("%w: map key %v is not an unsigned integer", ErrInvalidCBOR, k)
+			}
+			if _, dup := m[key]; dup {
+				return nil, nil, fmt.Errorf("%w: duplicate map key %d", ErrInvalidCBOR, key)
+			}
+			if m[key], data, err = decodeItem(rest, depth+1); err != nil {
+				return nil, nil, err
+			}
+		}
+		return m, data, nil
+	default: // majorTag
+		content, rest, err := decodeItem(data, depth+1)
+		if err != nil {
+			return nil, nil, err
+		}
+		return cborTag{Number: n, Content: content}, rest, nil
+	}
+}
@@ -0,0 +1,56 @@
+package ur
+
+import (
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCBORWriter(t *testing.T) {
+	var w cborWriter
+	w.array(7)
+	w.uint(23)
+	w.uint(24)
+	w.uint(1000)
+	w.uint(1 << 32)
+	w.bool(true)
+	w.text("a")
+	w.tag(304)
+	w.mapHeader(0)
+	// RFC 8949 Appendix A encodings
+	want := "87" + "17" + "1818" + "1903e8" + "1b0000000100000000" + "f5" + "6161" + "d90130" + "a0"
+	if got := hex.EncodeToString(w.buf); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	v, err := decodeCBOR(w.buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantValue := []any{uint64(23), uint64(24), uint64(1000), uint64(1 << 32), true, "a", cborTag{304, map[uint64]any{}}}
+	if !reflect.DeepEqual(v, wantValue) {
+		t.Errorf("decodeCBOR() = %#v", v)
+	}
+}
+
+func TestDecodeCBOR_Invalid(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"18",                                   // truncated uint
+		"45010203",                             // truncated byte string
+		"9b00000000ffffffff",                   // array longer than the input
+		"5f",                                   // indefinite length
+		"a2010201",                             // truncated map
+		"a1616101",                             // text map key
+		"a201010102",                           // duplicate key
+		"0101",                                 // trailing bytes
+		"f7",                                   // undefined
+		"818181818181818181818181818181818181", // too deep
+	} {
+		data, _ := hex.DecodeString(in)
+		if _, err := decodeCBOR(data); !errors.Is(err, ErrInvalidCBOR) {
+			t.Errorf("decodeCBOR(%s) error = %v, want ErrInvalidCBOR", in, err)
+		}
+	}
+}
@@ -0,0 +1,502 @@
+package ur
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"math"
+	"math/bits"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidPart indicates a fountain part that is malformed or belongs
+	// to a different message than the parts already received
+	ErrInvalidPart = errors.New("invalid fountain part")
+	// ErrMessageChecksum indicates a reassembled message whose CRC32 does not
+	// match the one announced by its parts
+	ErrMessageChecksum = errors.New("message checksum mismatch")
+)
+
+// minFragmentLen keeps fragments from becoming so small that part headers
+// dominate the frames
+const minFragmentLen = 10
+
+// maxSeqLen and maxMessageLen bound the parts a decoder accepts. Frames are
+// untrusted scanned input, and every part costs O(SeqLen) to place, so a
+// single frame must not be able to announce millions of fragments.
+const (
+	maxSeqLen     = 10000
+	maxMessageLen = 1 << 20
+)
+
+// Part is one frame of a fountain-encoded message. The first SeqLen parts
+// carry one fragment each; later parts XOR a pseudo-random set of fragments
+// chosen from SeqNum and Checksum, so any large enough subset of parts
+// rebuilds the message.
+type Part struct {
+	SeqNum     uint32
+	SeqLen     int
+	MessageLen int
+	Checksum   uint32
+	Data       []byte
+}
+
+// CBOR encodes the part as [seqNum, seqLen, messageLen, checksum, data]
+func (p Part) CBOR() []byte {
+	var w cborWriter
+	w.array(5)
+	w.uint(uint64(p.SeqNum))
+	w.uint(uint64(p.SeqLen))
+	w.uint(uint64(p.MessageLen))
+	w.uint(uint64(p.Checksum))
+	w.bytes(p.Data)
+	return w.buf
+}
+
+func parsePart(data []byte) (Part, error) {
+	v, err := decodeCBOR(data)
+	if err != nil {
+		return Part{}, err
+	}
+	items, ok := v.([]any)
+	if !ok || len(items) != 5 {
+		return Part{}, fmt.Errorf("%w: not a 5 element array", ErrInvalidPart)
+	}
+	var header [4]uint64
+	for i := range header {
+		n, ok := items[i].(uint64)
+		if !ok || n > math.MaxUint32 {
+			return Part{}, fmt.Errorf("%w: header field %d is not a 32-bit unsigned integer", ErrInvalidPart, i)
+		}
+		header[i] = n
+	}
+	fragment, ok := items[4].([]byte)
+	if !ok {
+		return Part{}, fmt.Errorf("%w: fragment is not a byte string", ErrInvalidPart)
+	}
+	p := Part{
+		SeqNum:     uint32(header[0]),
+		SeqLen:     int(header[1]),
+		MessageLen: int(header[2]),
+		Checksum:   uint32(header[3]),
+		Data:       fragment,
+	}
+	if p.SeqLen > maxSeqLen || p.MessageLen > maxMessageLen {
+		return Part{}, fmt.Errorf("%w: %d fragments of a %d byte message exceeds the limit of %d fragments, %d bytes",
+			ErrInvalidPart, p.SeqLen, p.MessageLen, maxSeqLen, maxMessageLen)
+	}
+	if p.SeqNum == 0 || p.SeqLen == 0 || len(p.Data) == 0 ||
+		p.SeqLen != (p.MessageLen+len(p.Data)-1)/len(p.Data) {
+		return Part{}, fmt.Errorf("%w: inconsistent header", ErrInvalidPart)
+	}
+	return p, nil
+}
+
+// seqID is the "seqNum-seqLen" component of a multi-part UR
+func (p Part) seqID() string {
+	return fmt.Sprintf("%d-%d", p.SeqNum, p.SeqLen)
+}
+
+func parseSeqID(s string) (uint32, int, error) {
+	num, length, ok := strings.Cut(s, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("%w: bad sequence %q", ErrInvalidPart, s)
+	}
+	n, err1 := strconv.ParseUint(num, 10, 32)
+	l, err2 := strconv.ParseUint(length, 10, 32)
+	if err1 != nil || err2 != nil || n == 0 || l == 0 {
+		return 0, 0, fmt.Errorf("%w: bad sequence %q", ErrInvalidPart, s)
+	}
+	return uint32(n), int(l), nil
+}
+
+// FountainEncoder splits a message into fragments and emits an endless
+// stream of parts
+type FountainEncoder struct {
+	messageLen int
+	checksum   uint32
+	fragments  [][]byte
+	seqNum     uint32
+}
+
+// NewFountainEncoder splits message into equal fragments of at most
+// maxFragmentLen bytes, padding the last one with zeros
+func NewFountainEncoder(message []byte, maxFragmentLen int) (*FountainEncoder, error) {
+	if len(message) == 0 {
+		return nil, errors.New("message is empty")
+	}
+	if len(message) > maxMessageLen {
+		return nil, fmt.Errorf("message is larger than %d bytes", maxMessageLen)
+	}
+	if maxFragmentLen < minFragmentLen {
+		return nil, fmt.Errorf("fragment length must be at least %d", minFragmentLen)
+	}
+	fragmentLen := nominalFragmentLength(len(message), maxFragmentLen)
+	if seqLen := (len(message) + fragmentLen - 1) / fragmentLen; seqLen > maxSeqLen {
+		return nil, fmt.Errorf("message needs %d fragments, more than %d; use a larger fragment length", seqLen, maxSeqLen)
+	}
+	padded := make([]byte, (len(message)+fragmentLen-1)/fragmentLen*fragmentLen)
+	copy(padded, message)
+
+	e := &FountainEncoder{messageLen: len(message), checksum: crc32.ChecksumIEEE(message)}
+	for i := 0; i < len(padded); i += fragmentLen {
+		e.fragments = append(e.fragments, padded[i:i+fragmentLen])
+	}
+	return e, nil
+}
+
+// nominalFragmentLength picks the fewest equal fragments no longer than
+// maxFragmentLen
+func nominalFragmentLength(messageLen, maxFragmentLen int) int {
+	maxCount := max(1, messageLen/minFragmentLen)
+	fragmentLen := messageLen
+	for count := 1; count <= maxCount; count++ {
+		fragmentLen = (messageLen + count - 1) / count
+		if fragmentLen <= maxFragmentLen {
+			break
+		}
+	}
+	return fragmentLen
+}
+
+// SeqLen is the number of fragments, and so the minimum number of parts
+// needed to decode
+func (e *FountainEncoder) SeqLen() int {
+	return len(e.fragments)
+}
+
+// NextPart returns the next part. Parts 1 to SeqLen are the plain
+// fragments; later ones are mixed.
+func (e *FountainEncoder) NextPart() Part {
+	e.seqNum++
+	indexes := chooseFragments(e.seqNum, len(e.fragments), e.checksum)
+	data := make([]byte, len(e.fragments[0]))
+	for _, i := range indexes {
+		xorInto(data, e.fragments[i])
+	}
+	return Part{
+		SeqNum:     e.seqNum,
+		SeqLen:     len(e.fragments),
+		MessageLen: e.messageLen,
+		Checksum:   e.checksum,
+		Data:       data,
+	}
+}
+
+// chooseFragments returns the fragment indexes mixed into part seqNum
+func chooseFragments(seqNum uint32, seqLen int, checksum uint32) []int {
+	if int(seqNum) <= seqLen {
+		return []int{int(seqNum) - 1}
+	}
+	seed := binary.BigEndian.AppendUint32(binary.BigEndian.AppendUint32(nil, seqNum), checksum)
+	rng := newXoshiro256(seed)
+	degree := chooseDegree(seqLen, rng)
+
+	// The reference shuffles by removing a random position from the ordered
+	// list of indexes left, and keeps the first degree. Only those draws
+	// matter, and the tree finds the i-th index left without shifting the
+	// rest, so a part costs O(seqLen) instead of O(seqLen²).
+	remaining := newIndexTree(seqLen)
+	chosen := make([]int, degree)
+	for n := range chosen {
+		chosen[n] = remaining.take(rng.nextInt(0, seqLen-n-1))
+	}
+	return chosen
+}
+
+// indexTree is a Fenwick tree over the indexes 0 to n-1 still to be chosen,
+// counting at each node how many are left in its range
+type indexTree []int
+
+func newIndexTree(n int) indexTree {
+	t := make(indexTree, n+1)
+	for i := 1; i <= n; i++ {
+		t[i]++
+		if parent := i + i&-i; parent <= n {
+			t[parent] += t[i]
+		}
+	}
+	return t
+}
+
+// take removes and returns the i-th smallest index left
+func (t indexTree) take(i int) int {
+	pos := 0
+	for step := 1 << (bits.Len(uint(len(t)-1)) - 1); step > 0; step >>= 1 {
+		if next := pos + step; next < len(t) && t[next] <= i {
+			pos = next
+			i -= t[next]
+		}
+	}
+	for j := pos + 1; j < len(t); j += j & -j {
+		t[j]--
+	}
+	return pos
+}
+
+// chooseDegree samples how many fragments to mix, with probability
+// proportional to 1/degree
+func chooseDegree(seqLen int, rng *xoshiro256) int {
+	weights := make([]float64, seqLen)
+	for i := range weights {
+		weights[i] = 1 / float64(i+1)
+	}
+	return newAliasSampler(weights).next(rng) + 1
+}
+
+// aliasSampler is Vose's alias method with the index order of the reference
+// implementation, so both sides draw the same degree from the same seed
+type aliasSampler struct {
+	probs   []float64
+	aliases []int
+}
+
+func newAliasSampler(weights []float64) *aliasSampler {
+	n := len(weights)
+	sum := 0.0
+	for _, w := range weights {
+		sum += w
+	}
+	p := make([]float64, n)
+	for i, w := range weights {
+		p[i] = w * float64(n) / sum
+	}
+
+	var small, large []int
+	for i := n - 1; i >= 0; i-- {
+		if p[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+
+	s := &aliasSampler{probs: make([]float64, n), aliases: make([]int, n)}
+	for len(small) > 0 && len(large) > 0 {
+		a := small[len(small)-1]
+		small = small[:len(small)-1]
+		g := large[len(large)-1]
+		large = large[:len(large)-1]
+		s.probs[a] = p[a]
+		s.aliases[a] = g
+		p[g] += p[a] - 1
+		if p[g] < 1 {
+			small = append(small, g)
+		} else {
+			large = append(large, g)
+		}
+	}
+	// Leftovers on either list are 1 up to rounding error
+	for _, i := range large {
+		s.probs[i] = 1
+	}
+	for _, i := range small {
+		s.probs[i] = 1
+	}
+	return s
+}
+
+func (s *aliasSampler) next(rng *xoshiro256) int {
+	r1, r2 := rng.nextDouble(), rng.nextDouble()
+	i := int(float64(len(s.probs)) * r1)
+	if r2 < s.probs[i] {
+		return i
+	}
+	return s.aliases[i]
+}
+
+func xorInto(dst, src []byte) {
+	for i := range dst {
+		dst[i] ^= src[i]
+	}
+}
+
+// mixedPart is a received part reduced to the fragments still unknown
+type mixedPart struct {
+	indexes []int // sorted
+	data    []byte
+}
+
+func (m mixedPart) key() string {
+	return fmt.Sprint(m.indexes)
+}
+
+// FountainDecoder reassembles a message from parts received in any order,
+// with duplicates and gaps
+type FountainDecoder struct {
+	seqLen     int
+	messageLen int
+	checksum   uint32
+	fragLen    int
+	started    bool
+
+	simple   map[int][]byte
+	mixed    map[string]mixedPart
+	received map[uint32]bool
+
+	message []byte
+	err     error
+}
+
+// NewFountainDecoder returns an empty decoder
+func NewFountainDecoder() *FountainDecoder {
+	return &FountainDecoder{
+		simple:   make(map[int][]byte),
+		mixed:    make(map[string]mixedPart),
+		received: make(map[uint32]bool),
+	}
+}
+
+// ReceivePart adds a part. It returns an error if the part does not belong
+// to the message being decoded, or if the completed message fails its
+// checksum.
+func (d *FountainDecoder) ReceivePart(p Part) error {
+	if d.Complete() {
+		return d.err
+	}
+	if !d.started {
+		d.seqLen, d.messageLen, d.checksum, d.fragLen = p.SeqLen, p.MessageLen, p.Checksum, len(p.Data)
+		d.started = true
+	} else if p.SeqLen != d.seqLen || p.MessageLen != d.messageLen || p.Checksum != d.checksum || len(p.Data) != d.fragLen {
+		return fmt.Errorf("%w: part %s is from a different message", ErrInvalidPart, p.seqID())
+	}
+	if d.received[p.SeqNum] {
+		return nil
+	}
+	d.received[p.SeqNum] = true
+
+	indexes := chooseFragments(p.SeqNum, p.SeqLen, p.Checksum)
+	slices.Sort(indexes)
+	d.process(mixedPart{indexes: indexes, data: append([]byte(nil), p.Data...)})
+	return d.err
+}
+
+func (d *FountainDecoder) process(first mixedPart) {
+	queue := []mixedPart{first}
+	for len(queue) > 0 && !d.Complete() {
+		part := d.reduceBySimple(queue[0])
+		queue = queue[1:]
+
+		switch len(part.indexes) {
+		case 0:
+			continue
+		case 1:
+			index := part.indexes[0]
+			d.simple[index] = part.data
+			// Mixed parts that include this fragment may now reduce further
+			for k, m := range d.mixed {
+				if slices.Contains(m.indexes, index) {
+					delete(d.mixed, k)
+					queue = append(queue, m)
+				}
+			}
+			if len(d.simple) == d.seqLen {
+				d.finish()
+			}
+		default:
+			for _, m := range d.mixed {
+				part = reduceBy(part, m)
+			}
+			if len(part.indexes) < 2 {
+				queue = append(queue, part)
+				continue
+			}
+			if _, ok := d.mixed[part.key()]; ok {
+				continue
+			}
+			for k, m := range d.mixed {
+				if reduced := reduceBy(m, part); len(reduced.indexes) < len(m.indexes) {
+					delete(d.mixed, k)
+					queue = append(queue, reduced)
+				}
+			}
+			d.mixed[part.key()] = part
+		}
+	}
+}
+
+func (d *FountainDecoder) reduceBySimple(m mixedPart) mixedPart {
+	var indexes []int
+	data := m.data
+	for _, i := range m.indexes {
+		if fragment, ok := d.simple[i]; ok {
+			xorInto(data, fragment)
+		} else {
+			indexes = append(indexes, i)
+		}
+	}
+	return mixedPart{indexes: indexes, data: data}
+}
+
+// reduceBy removes by from m when by's fragments are a strict subset of m's
+func reduceBy(m, by mixedPart) mixedPart {
+	if len(by.indexes) >= len(m.indexes) {
+		return m
+	}
+	for _, i := range by.indexes {
+		if _, found := slices.BinarySearch(m.indexes, i); !found {
+			return m
+		}
+	}
+	var indexes []int
+	for _, i := range m.indexes {
+		if _, found := slices.BinarySearch(by.indexes, i); !found {
+			indexes = append(indexes, i)
+		}
+	}
+	data := bytes.Clone(m.data)
+	xorInto(data, by.data)
+	return mixedPart{indexes: indexes, data: data}
+}
+
+func (d *FountainDecoder) finish() {
+	message := make([]byte, 0, d.seqLen*d.fragLen)
+	for i := 0; i < d.seqLen; i++ {
+		message = append(message, d.simple[i]...)
+	}
+	message = message[:d.messageLen]
+	if crc32.ChecksumIEEE(message) != d.checksum {
+		d.err = ErrMessageChecksum
+	}
+	d.message = message
+	d.simple, d.mixed = nil, nil
+}
+
+// Complete reports whether the message has been reassembled (or failed its
+// checksum)
+func (d *FountainDecoder) Complete() bool {
+	return d.message != nil
+}
+
+// Message returns the reassembled message once Complete
+func (d *FountainDecoder) Message() ([]byte, error) {
+	if !d.Complete() {
+		return nil, errors.New("message is incomplete")
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return d.message, nil
+}
+
+// Progress estimates completion as the fraction of fragments recovered
+func (d *FountainDecoder) Progress() float64 {
+	if d.Complete() {
+		return 1
+	}
+	if d.seqLen == 0 {
+		return 0
+	}
+	return float64(len(d.simple)) / float64(d.seqLen)
+}
+
+// ExpectedParts is the number of fragments in the message, or 0 before the
+// first part arrives
+func (d *FountainDecoder) ExpectedParts() int {
+	return d.seqLen
+}
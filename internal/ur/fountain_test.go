@@ -0,0 +1,223 @@
+package ur
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+)
+
+// makeMessage is the reference implementation's deterministic test message
+func makeMessage(n int) []byte {
+	return newXoshiro256([]byte("Wolf")).nextData(n)
+}
+
+func TestXoshiro256(t *testing.T) {
+	want := []uint64{42, 81, 85, 8, 82, 84, 76, 73, 70, 88, 2, 74, 40, 48, 77, 54, 88, 7, 5, 88}
+	rng := newXoshiro256([]byte("Wolf"))
+	for i, w := range want {
+		if got := rng.next() % 100; got != w {
+			t.Fatalf("value %d = %d, want %d", i, got, w)
+		}
+	}
+}
+
+func TestNominalFragmentLength(t *testing.T) {
+	tests := []struct {
+		messageLen, maxFragmentLen, want int
+	}{
+		{256, 30, 29},
+		{1024, 100, 94},
+		{5, 30, 5},
+		{100, 100, 100},
+	}
+	for _, tt := range tests {
+		if got := nominalFragmentLength(tt.messageLen, tt.maxFragmentLen); got != tt.want {
+			t.Errorf("nominalFragmentLength(%d, %d) = %d, want %d", tt.messageLen, tt.maxFragmentLen, got, tt.want)
+		}
+	}
+}
+
+func TestChooseFragments(t *testing.T) {
+	for seqNum := uint32(1); seqNum <= 9; seqNum++ {
+		if got := chooseFragments(seqNum, 9, 0x12345678); len(got) != 1 || got[0] != int(seqNum)-1 {
+			t.Errorf("part %d mixes %v, want only fragment %d", seqNum, got, seqNum-1)
+		}
+	}
+	for seqNum := uint32(10); seqNum < 200; seqNum++ {
+		got := chooseFragments(seqNum, 9, 0x12345678)
+		seen := map[int]bool{}
+		for _, i := range got {
+			if i < 0 || i >= 9 || seen[i] {
+				t.Fatalf("part %d mixes %v", seqNum, got)
+			}
+			seen[i] = true
+		}
+		if len(got) == 0 {
+			t.Fatalf("part %d mixes no fragments", seqNum)
+		}
+	}
+}
+
+// TestChooseFragments_Reference checks the fragment choices of the reference
+// implementation's test message (1024 bytes, 100-byte fragments)
+func TestChooseFragments_Reference(t *testing.T) {
+	want := [][]int{
+		{0}, {1}, {2}, {3}, {4}, {5}, {6}, {7}, {8}, {9}, {10},
+		{9}, {2, 5, 6, 8, 9, 10}, {8}, {1, 5}, {1}, {0, 2, 4, 5, 8, 10}, {5}, {2}, {2},
+		{0, 1, 3, 4, 5, 7, 9, 10}, {0, 1, 2, 3, 5, 6, 8, 9, 10}, {0, 2, 4, 5, 7, 8, 9, 10}, {3, 5}, {4},
+		{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, {0, 1, 3, 4, 5, 6, 7, 9, 10}, {6}, {5, 6}, {7},
+	}
+	message := makeMessage(1024)
+	checksum := crc32.ChecksumIEEE(message)
+	for i, w := range want {
+		got := chooseFragments(uint32(i+1), 11, checksum)
+		slices.Sort(got)
+		if !slices.Equal(got, w) {
+			t.Errorf("part %d mixes %v, want %v", i+1, got, w)
+		}
+	}
+}
+
+// TestChooseFragments_Shuffle compares the tree against the reference's
+// shuffle, which deletes each chosen index from an ordered list
+func TestChooseFragments_Shuffle(t *testing.T) {
+	for _, seqLen := range []int{2, 3, 7, 64, 100, 513} {
+		for seqNum := uint32(seqLen + 1); seqNum < uint32(seqLen+200); seqNum++ {
+			seed := binary.BigEndian.AppendUint32(binary.BigEndian.AppendUint32(nil, seqNum), 0xdeadbeef)
+			rng := newXoshiro256(seed)
+			degree := chooseDegree(seqLen, rng)
+			remaining := make([]int, seqLen)
+			for i := range remaining {
+				remaining[i] = i
+			}
+			var want []int
+			for len(want) < degree {
+				i := rng.nextInt(0, len(remaining)-1)
+				want = append(want, remaining[i])
+				remaining = slices.Delete(remaining, i, i+1)
+			}
+			if got := chooseFragments(seqNum, seqLen, 0xdeadbeef); !slices.Equal(got, want) {
+				t.Fatalf("chooseFragments(%d, %d) = %v, want %v", seqNum, seqLen, got, want)
+			}
+		}
+	}
+}
+
+func TestPart_CBOR(t *testing.T) {
+	p := Part{SeqNum: 12, SeqLen: 8, MessageLen: 100, Checksum: 0x12345678, Data: []byte("0123456789abc")}
+	got, err := parsePart(p.CBOR())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.SeqNum != p.SeqNum || got.SeqLen != p.SeqLen || got.MessageLen != p.MessageLen ||
+		got.Checksum != p.Checksum || !bytes.Equal(got.Data, p.Data) {
+		t.Errorf("parsePart() = %+v, want %+v", got, p)
+	}
+
+	p.SeqLen = 9 // 100 bytes in 13-byte fragments is 8 fragments
+	if _, err := parsePart(p.CBOR()); !errors.Is(err, ErrInvalidPart) {
+		t.Errorf("inconsistent header: error = %v, want ErrInvalidPart", err)
+	}
+}
+
+// TestPart_TooLarge checks that one crafted frame announcing a huge number
+// of 1-byte fragments is rejected before any fragments are chosen
+func TestPart_TooLarge(t *testing.T) {
+	start := time.Now()
+	for _, p := range []Part{
+		{SeqNum: 400001, SeqLen: 400000, MessageLen: 400000, Data: []byte{0}},
+		{SeqNum: 2, SeqLen: 1, MessageLen: maxMessageLen + 1, Data: make([]byte, maxMessageLen+1)},
+	} {
+		if _, err := parsePart(p.CBOR()); !errors.Is(err, ErrInvalidPart) {
+			t.Errorf("parsePart(%d fragments, %d bytes) error = %v, want ErrInvalidPart", p.SeqLen, p.MessageLen, err)
+		}
+	}
+	frame := "ur:bytes/400001-400000/" + EncodeBytewords(Part{SeqNum: 400001, SeqLen: 400000, MessageLen: 400000, Data: []byte{0}}.CBOR(), Minimal)
+	if err := NewDecoder().Receive(frame); !errors.Is(err, ErrInvalidPart) {
+		t.Errorf("Receive(%s) error = %v, want ErrInvalidPart", frame, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("rejecting oversized frames took %v", elapsed)
+	}
+
+	// the largest accepted header still decodes quickly
+	p := Part{SeqNum: maxSeqLen + 1, SeqLen: maxSeqLen, MessageLen: maxSeqLen, Data: []byte{0}}
+	start = time.Now()
+	if err := NewFountainDecoder().ReceivePart(p); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("a part of %d fragments took %v", maxSeqLen, elapsed)
+	}
+
+	if _, err := NewFountainEncoder(make([]byte, maxMessageLen+1), 1000); err == nil {
+		t.Error("NewFountainEncoder accepted a message over maxMessageLen")
+	}
+	if _, err := NewFountainEncoder(make([]byte, 2*maxSeqLen*minFragmentLen), minFragmentLen); err == nil {
+		t.Error("NewFountainEncoder accepted more than maxSeqLen fragments")
+	}
+}
+
+func TestFountain_RoundTrip(t *testing.T) {
+	message := makeMessage(1024)
+	for _, seed := range []int64{1, 2, 3} {
+		e, err := NewFountainEncoder(message, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := NewFountainDecoder()
+		// Drop half the frames at random: the mixed parts past SeqLen make
+		// up for the lost ones
+		rng := rand.New(rand.NewSource(seed))
+		sent := 0
+		for !d.Complete() {
+			p := e.NextPart()
+			sent++
+			if sent > 1000 {
+				t.Fatalf("seed %d: not complete after %d parts", seed, sent)
+			}
+			if rng.Intn(2) == 0 {
+				continue
+			}
+			if err := d.ReceivePart(p); err != nil {
+				t.Fatal(err)
+			}
+		}
+		got, err := d.Message()
+		if err != nil || !bytes.Equal(got, message) {
+			t.Fatalf("seed %d: Message() = %x, %v", seed, got, err)
+		}
+	}
+}
+
+func TestFountainDecoder_RejectsForeignPart(t *testing.T) {
+	e1, _ := NewFountainEncoder(makeMessage(100), 30)
+	e2, _ := NewFountainEncoder(bytes.Repeat([]byte{1}, 100), 30)
+	d := NewFountainDecoder()
+	if err := d.ReceivePart(e1.NextPart()); err != nil {
+		t.Fatal(err)
+	}
+	e2.NextPart()
+	if err := d.ReceivePart(e2.NextPart()); !errors.Is(err, ErrInvalidPart) {
+		t.Errorf("error = %v, want ErrInvalidPart", err)
+	}
+}
+
+func TestFountainDecoder_Checksum(t *testing.T) {
+	e, _ := NewFountainEncoder(makeMessage(50), 30)
+	d := NewFountainDecoder()
+	p := e.NextPart()
+	p.Data[0] ^= 1
+	d.ReceivePart(p)
+	if err := d.ReceivePart(e.NextPart()); !errors.Is(err, ErrMessageChecksum) {
+		t.Errorf("error = %v, want ErrMessageChecksum", err)
+	}
+	if _, err := d.Message(); !errors.Is(err, ErrMessageChecksum) {
+		t.Errorf("Message() error = %v, want ErrMessageChecksum", err)
+	}
+}
@@ -0,0 +1,572 @@
+package ur
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/ethereum/go-ethereum/accounts"
+)
+
+// Registered UR types (BCR-2020-006)
+const (
+	TypeBytes          = "bytes"
+	TypePSBT           = "crypto-psbt"
+	TypeHDKey          = "crypto-hdkey"
+	TypeEthSignRequest = "eth-sign-request"
+	TypeEthSignature   = "eth-signature"
+)
+
+// CBOR tags of the registry types that appear nested in others
+const (
+	tagUUID     = 37
+	tagKeypath  = 304
+	tagCoinInfo = 305
+)
+
+var (
+	// ErrNotPSBT indicates a crypto-psbt payload without the PSBT magic
+	ErrNotPSBT = errors.New("not a PSBT")
+	// ErrPrivateHDKey indicates a crypto-hdkey holding a private key, which
+	// would put the key in a plain QR code
+	ErrPrivateHDKey = errors.New("private crypto-hdkey is not supported")
+)
+
+var psbtMagic = []byte("psbt\xff")
+
+func checkType(u UR, want string) error {
+	if u.Type != want {
+		return fmt.Errorf("%w: got %s, want %s", ErrUnexpectedType, u.Type, want)
+	}
+	return nil
+}
+
+// NewBytes wraps arbitrary data in a bytes UR
+func NewBytes(data []byte) UR {
+	var w cborWriter
+	w.bytes(data)
+	return UR{Type: TypeBytes, CBOR: w.buf}
+}
+
+// ParseBytes returns the data of a bytes UR
+func ParseBytes(u UR) ([]byte, error) {
+	if err := checkType(u, TypeBytes); err != nil {
+		return nil, err
+	}
+	return decodeByteString(u.CBOR)
+}
+
+// NewPSBT wraps a serialized PSBT in a crypto-psbt UR
+func NewPSBT(psbt []byte) (UR, error) {
+	if !bytes.HasPrefix(psbt, psbtMagic) {
+		return UR{}, ErrNotPSBT
+	}
+	var w cborWriter
+	w.bytes(psbt)
+	return UR{Type: TypePSBT, CBOR: w.buf}, nil
+}
+
+// ParsePSBT returns the serialized PSBT of a crypto-psbt UR
+func ParsePSBT(u UR) ([]byte, error) {
+	if err := checkType(u, TypePSBT); err != nil {
+		return nil, err
+	}
+	psbt, err := decodeByteString(u.CBOR)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(psbt, psbtMagic) {
+		return nil, ErrNotPSBT
+	}
+	return psbt, nil
+}
+
+func decodeByteString(data []byte) ([]byte, error) {
+	v, err := decodeCBOR(data)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := v.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("%w: expected a byte string", ErrInvalidCBOR)
+	}
+	return b, nil
+}
+
+// Keypath is a crypto-keypath: derivation steps from the key with
+// SourceFingerprint (usually the master key), with BIP32 hardened indexes
+type Keypath struct {
+	Path              accounts.DerivationPath
+	SourceFingerprint uint32
+	// Depth is only needed when Path does not start at the source key
+	Depth int
+}
+
+// String writes the path as in descriptor key origins, with the source
+// fingerprint in place of "m" when it is known
+func (k Keypath) String() string {
+	s := k.Path.String()
+	if k.SourceFingerprint != 0 {
+		s = fmt.Sprintf("%08x%s", k.SourceFingerprint, s[1:])
+	}
+	return s
+}
+
+func (k Keypath) write(w *cborWriter) {
+	entries := 1
+	if k.SourceFingerprint != 0 {
+		entries++
+	}
+	if k.Depth != 0 {
+		entries++
+	}
+	w.tag(tagKeypath)
+	w.mapHeader(entries)
+	w.uint(1)
+	w.array(2 * len(k.Path))
+	for _, c := range k.Path {
+		w.uint(uint64(c &^ hdkeychain.HardenedKeyStart))
+		w.bool(c >= hdkeychain.HardenedKeyStart)
+	}
+	if k.SourceFingerprint != 0 {
+		w.uint(2)
+		w.uint(uint64(k.SourceFingerprint))
+	}
+	if k.Depth != 0 {
+		w.uint(3)
+		w.uint(uint64(k.Depth))
+	}
+}
+
+func parseKeypath(v any) (Keypath, error) {
+	m, err := taggedMap(v, tagKeypath, "crypto-keypath")
+	if err != nil {
+		return Keypath{}, err
+	}
+	var k Keypath
+	components, _ := m[1].([]any)
+	if len(components)%2 != 0 {
+		return Keypath{}, fmt.Errorf("%w: keypath components must be index/hardened pairs", ErrInvalidCBOR)
+	}
+	for i := 0; i < len(components); i += 2 {
+		// Wildcards and ranges ([] or [low, high]) only make sense in
+		// descriptors, not in the paths of a single key
+		index, ok1 := components[i].(uint64)
+		hardened, ok2 := components[i+1].(bool)
+		if !ok1 || !ok2 || index >= hdkeychain.HardenedKeyStart {
+			return Keypath{}, fmt.Errorf("%w: unsupported keypath component", ErrInvalidCBOR)
+		}
+		c := uint32(index)
+		if hardened {
+			c += hdkeychain.HardenedKeyStart
+		}
+		k.Path = append(k.Path, c)
+	}
+	if k.SourceFingerprint, err = optionalUint32(m, 2); err != nil {
+		return Keypath{}, err
+	}
+	depth, err := optionalUint32(m, 3)
+	if err != nil || depth > 255 {
+		return Keypath{}, fmt.Errorf("%w: bad keypath depth", ErrInvalidCBOR)
+	}
+	k.Depth = int(depth)
+	return k, nil
+}
+
+// HDKey is a public crypto-hdkey
+type HDKey struct {
+	IsMaster          bool
+	Key               []byte // 33-byte compressed public key
+	ChainCode         []byte
+	CoinType          uint32 // SLIP-44 coin type of the use-info, 0 for Bitcoin
+	Testnet           bool
+	Origin            *Keypath
+	ParentFingerprint uint32
+	Name              string
+	Note              string
+}
+
+// HDKeyFromExtended describes a BIP32 public key. origin, if not nil, is
+// its derivation from the master key; a private key is neutered first.
+func HDKeyFromExtended(key *hdkeychain.ExtendedKey, origin *Keypath) (HDKey, error) {
+	public, err := key.Neuter()
+	if err != nil {
+		return HDKey{}, err
+	}
+	pub, err := public.ECPubKey()
+	if err != nil {
+		return HDKey{}, err
+	}
+	return HDKey{
+		IsMaster:          public.Depth() == 0 && public.ParentFingerprint() == 0,
+		Key:               pub.SerializeCompressed(),
+		ChainCode:         public.ChainCode(),
+		Testnet:           !public.IsForNet(&chaincfg.MainNetParams),
+		Origin:            origin,
+		ParentFingerprint: public.ParentFingerprint(),
+	}, nil
+}
+
+// Extended returns the key as an xpub (or tpub for testnet keys). The
+// depth and child number come from the origin, as they are not stored in
+// the crypto-hdkey itself.
+func (k HDKey) Extended() (*hdkeychain.ExtendedKey, error) {
+	params := &chaincfg.MainNetParams
+	if k.Testnet {
+		params = &chaincfg.TestNet3Params
+	}
+	if _, err := btcec.ParsePubKey(k.Key); err != nil {
+		return nil, fmt.Errorf("invalid crypto-hdkey public key: %w", err)
+	}
+	if len(k.ChainCode) != 32 {
+		return nil, fmt.Errorf("%w: crypto-hdkey has no 32-byte chain code", ErrInvalidCBOR)
+	}
+
+	var depth uint8
+	var childIndex uint32
+	if k.Origin != nil {
+		depth = uint8(len(k.Origin.Path))
+		if k.Origin.Depth != 0 {
+			depth = uint8(k.Origin.Depth)
+		}
+		if n := len(k.Origin.Path); n > 0 {
+			childIndex = k.Origin.Path[n-1]
+		}
+	}
+	parent := binary.BigEndian.AppendUint32(nil, k.ParentFingerprint)
+	return hdkeychain.NewExtendedKey(params.HDPublicKeyID[:], k.Key, k.ChainCode, parent, depth, childIndex, false), nil
+}
+
+// UR encodes the key as a crypto-hdkey
+func (k HDKey) UR() UR {
+	useInfo := k.CoinType != 0 || k.Testnet
+	entries := 2
+	for _, present := range []bool{k.IsMaster, useInfo, k.Origin != nil, k.ParentFingerprint != 0, k.Name != "", k.Note != ""} {
+		if present {
+			entries++
+		}
+	}
+
+	var w cborWriter
+	w.mapHeader(entries)
+	if k.IsMaster {
+		w.uint(1)
+		w.bool(true)
+	}
+	w.uint(3)
+	w.bytes(k.Key)
+	w.uint(4)
+	w.bytes(k.ChainCode)
+	if useInfo {
+		w.uint(5)
+		w.tag(tagCoinInfo)
+		n := 0
+		if k.CoinType != 0 {
+			n++
+		}
+		if k.Testnet {
+			n++
+		}
+		w.mapHeader(n)
+		if k.CoinType != 0 {
+			w.uint(1)
+			w.uint(uint64(k.CoinType))
+		}
+		if k.Testnet {
+			w.uint(2)
+			w.uint(1)
+		}
+	}
+	if k.Origin != nil {
+		w.uint(6)
+		k.Origin.write(&w)
+	}
+	if k.ParentFingerprint != 0 {
+		w.uint(8)
+		w.uint(uint64(k.ParentFingerprint))
+	}
+	if k.Name != "" {
+		w.uint(9)
+		w.text(k.Name)
+	}
+	if k.Note != "" {
+		w.uint(10)
+		w.text(k.Note)
+	}
+	return UR{Type: TypeHDKey, CBOR: w.buf}
+}
+
+// ParseHDKey decodes a public crypto-hdkey
+func ParseHDKey(u UR) (HDKey, error) {
+	if err := checkType(u, TypeHDKey); err != nil {
+		return HDKey{}, err
+	}
+	v, err := decodeCBOR(u.CBOR)
+	if err != nil {
+		return HDKey{}, err
+	}
+	m, ok := v.(map[uint64]any)
+	if !ok {
+		return HDKey{}, fmt.Errorf("%w: crypto-hdkey is not a map", ErrInvalidCBOR)
+	}
+	if private, _ := m[2].(bool); private {
+		return HDKey{}, ErrPrivateHDKey
+	}
+
+	var k HDKey
+	k.IsMaster, _ = m[1].(bool)
+	k.Key, _ = m[3].([]byte)
+	k.ChainCode, _ = m[4].([]byte)
+	if len(k.Key) != 33 {
+		return HDKey{}, fmt.Errorf("%w: crypto-hdkey key data must be 33 bytes", ErrInvalidCBOR)
+	}
+	if k.Key[0] == 0 {
+		return HDKey{}, ErrPrivateHDKey
+	}
+	if useInfo, ok := m[5]; ok {
+		info, err := taggedMap(useInfo, tagCoinInfo, "crypto-coininfo")
+		if err != nil {
+			return HDKey{}, err
+		}
+		if k.CoinType, err = optionalUint32(info, 1); err != nil {
+			return HDKey{}, err
+		}
+		network, err := optionalUint32(info, 2)
+		if err != nil {
+			return HDKey{}, err
+		}
+		k.Testnet = network == 1
+	}
+	if origin, ok := m[6]; ok {
+		keypath, err := parseKeypath(origin)
+		if err != nil {
+			return HDKey{}, err
+		}
+		k.Origin = &keypath
+	}
+	if k.ParentFingerprint, err = optionalUint32(m, 8); err != nil {
+		return HDKey{}, err
+	}
+	k.Name, _ = m[9].(string)
+	k.Note, _ = m[10].(string)
+	return k, nil
+}
+
+// EthDataType says how an eth-sign-request's data is hashed and signed
+type EthDataType uint64
+
+const (
+	// EthTransaction is an RLP-encoded legacy transaction
+	EthTransaction EthDataType = 1
+	// EthTypedData is EIP-712 typed data as JSON
+	EthTypedData EthDataType = 2
+	// EthPersonalMessage is an EIP-191 personal_sign message
+	EthPersonalMessage EthDataType = 3
+	// EthTypedTransaction is an EIP-2718 typed transaction (e.g. EIP-1559)
+	EthTypedTransaction EthDataType = 4
+)
+
+// EthDataTypes maps the names accepted on the command line to data types
+var EthDataTypes = map[string]EthDataType{
+	"transaction":       EthTransaction,
+	"typed-data":        EthTypedData,
+	"personal-message":  EthPersonalMessage,
+	"typed-transaction": EthTypedTransaction,
+}
+
+func (t EthDataType) String() string {
+	for name, v := range EthDataTypes {
+		if v == t {
+			return name
+		}
+	}
+	return fmt.Sprintf("unknown(%d)", uint64(t))
+}
+
+// EthSignRequest asks an air-gapped signer to sign Ethereum data with the
+// key at Path
+type EthSignRequest struct {
+	RequestID []byte // 16-byte UUID, echoed in the signature
+	SignData  []byte
+	DataType  EthDataType
+	ChainID   uint64 // 0 to omit
+	Path      Keypath
+	Address   []byte // optional 20-byte signer address
+	Origin    string // optional name of the requesting wallet
+}
+
+// UR encodes the request as an eth-sign-request
+func (r EthSignRequest) UR() UR {
+	entries := 3
+	for _, present := range []bool{r.RequestID != nil, r.ChainID != 0, r.Address != nil, r.Origin != ""} {
+		if present {
+			entries++
+		}
+	}
+	var w cborWriter
+	w.mapHeader(entries)
+	if r.RequestID != nil {
+		w.uint(1)
+		w.tag(tagUUID)
+		w.bytes(r.RequestID)
+	}
+	w.uint(2)
+	w.bytes(r.SignData)
+	w.uint(3)
+	w.uint(uint64(r.DataType))
+	if r.ChainID != 0 {
+		w.uint(4)
+		w.uint(r.ChainID)
+	}
+	w.uint(5)
+	r.Path.write(&w)
+	if r.Address != nil {
+		w.uint(6)
+		w.bytes(r.Address)
+	}
+	if r.Origin != "" {
+		w.uint(7)
+		w.text(r.Origin)
+	}
+	return UR{Type: TypeEthSignRequest, CBOR: w.buf}
+}
+
+// ParseEthSignRequest decodes an eth-sign-request
+func ParseEthSignRequest(u UR) (EthSignRequest, error) {
+	if err := checkType(u, TypeEthSignRequest); err != nil {
+		return EthSignRequest{}, err
+	}
+	m, err := decodeMap(u.CBOR, "eth-sign-request")
+	if err != nil {
+		return EthSignRequest{}, err
+	}
+	var r EthSignRequest
+	if r.RequestID, err = optionalUUID(m); err != nil {
+		return EthSignRequest{}, err
+	}
+	var ok bool
+	if r.SignData, ok = m[2].([]byte); !ok {
+		return EthSignRequest{}, fmt.Errorf("%w: eth-sign-request has no sign data", ErrInvalidCBOR)
+	}
+	dataType, ok := m[3].(uint64)
+	if !ok {
+		return EthSignRequest{}, fmt.Errorf("%w: eth-sign-request has no data type", ErrInvalidCBOR)
+	}
+	r.DataType = EthDataType(dataType)
+	if chainID, ok := m[4]; ok {
+		if r.ChainID, ok = chainID.(uint64); !ok {
+			return EthSignRequest{}, fmt.Errorf("%w: bad chain id", ErrInvalidCBOR)
+		}
+	}
+	if r.Path, err = parseKeypath(m[5]); err != nil {
+		return EthSignRequest{}, err
+	}
+	r.Address, _ = m[6].([]byte)
+	r.Origin, _ = m[7].(string)
+	return r, nil
+}
+
+// EthSignature is a signer's answer to an EthSignRequest
+type EthSignature struct {
+	RequestID []byte
+	Signature []byte // r || s || v
+	Origin    string
+}
+
+// UR encodes the signature as an eth-signature
+func (s EthSignature) UR() UR {
+	entries := 1
+	for _, present := range []bool{s.RequestID != nil, s.Origin != ""} {
+		if present {
+			entries++
+		}
+	}
+	var w cborWriter
+	w.mapHeader(entries)
+	if s.RequestID != nil {
+		w.uint(1)
+		w.tag(tagUUID)
+		w.bytes(s.RequestID)
+	}
+	w.uint(2)
+	w.bytes(s.Signature)
+	if s.Origin != "" {
+		w.uint(3)
+		w.text(s.Origin)
+	}
+	return UR{Type: TypeEthSignature, CBOR: w.buf}
+}
+
+// ParseEthSignature decodes an eth-signature
+func ParseEthSignature(u UR) (EthSignature, error) {
+	if err := checkType(u, TypeEthSignature); err != nil {
+		return EthSignature{}, err
+	}
+	m, err := decodeMap(u.CBOR, "eth-signature")
+	if err != nil {
+		return EthSignature{}, err
+	}
+	var s EthSignature
+	if s.RequestID, err = optionalUUID(m); err != nil {
+		return EthSignature{}, err
+	}
+	var ok bool
+	if s.Signature, ok = m[2].([]byte); !ok {
+		return EthSignature{}, fmt.Errorf("%w: eth-signature has no signature", ErrInvalidCBOR)
+	}
+	s.Origin, _ = m[3].(string)
+	return s, nil
+}
+
+func decodeMap(data []byte, name string) (map[uint64]any, error) {
+	v, err := decodeCBOR(data)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := v.(map[uint64]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not a map", ErrInvalidCBOR, name)
+	}
+	return m, nil
+}
+
+func taggedMap(v any, tag uint64, name string) (map[uint64]any, error) {
+	t, ok := v.(cborTag)
+	if !ok || t.Number != tag {
+		return nil, fmt.Errorf("%w: expected %s (tag %d)", ErrInvalidCBOR, name, tag)
+	}
+	m, ok := t.Content.(map[uint64]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not a map", ErrInvalidCBOR, name)
+	}
+	return m, nil
+}
+
+func optionalUint32(m map[uint64]any, key uint64) (uint32, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, nil
+	}
+	n, ok := v.(uint64)
+	if !ok || n > 0xffffffff {
+		return 0, fmt.Errorf("%w: field %d is not a 32-bit unsigned integer", ErrInvalidCBOR, key)
+	}
+	return uint32(n), nil
+}
+
+func optionalUUID(m map[uint64]any) ([]byte, error) {
+	v, ok := m[1]
+	if !ok {
+		return nil, nil
+	}
+	t, ok := v.(cborTag)
+	id, isBytes := t.Content.([]byte)
+	if !ok || t.Number != tagUUID || !isBytes || len(id) != 16 {
+		return nil, fmt.Errorf("%w: request id is not a UUID", ErrInvalidCBOR)
+	}
+	return id, nil
+}
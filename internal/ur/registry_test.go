@@ -0,0 +1,131 @@
+package ur
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/ethereum/go-ethereum/accounts"
+)
+
+func TestPSBT(t *testing.T) {
+	// BCR-2020-006 crypto-psbt example
+	psbt, _ := hex.DecodeString("70736274ff01009a020000000258e87a21b56daf0c23be8e7070456c336f7cbaa5c8757924f545887bb2abdd750000000000ffffffff838d0427d0ec650a68aa46bb0b098aea4422c071b2ca78352a077959d07cea1d0100000000ffffffff0270aaf00800000000160014d85c2b71d0060b09c9886aeb815e50991dda124d00e1f5050000000016001400aea9a2e5f0f876a588df5546e8742d1d87008f000000000000000000")
+	want := "ur:crypto-psbt/hdosjojkidjyzmadaenyaoaeaeaeaohdvsknclrejnpebncnrnmnjojofejzeojlkerdonspkpkkdkykfelokgprpyutkpaeaeaeaeaezmzmzmzmlslgaaditiwpihbkispkfgrkbdaslewdfycprtjsprsgksecdratkkhktikewdcaadaeaeaeaezmzmzmzmaojopkwtayaeaeaeaecmaebbtphhdnjstiambdassoloimwmlyhygdnlcatnbggtaevyykahaeaeaeaecmaebbaeplptoevwwtyakoonlourgofgvsjydpcaltaemyaeaeaeaeaeaeaeaeaebkgdcarh"
+
+	u, err := NewPSBT(psbt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.String() != want {
+		t.Errorf("String() = %s, want %s", u, want)
+	}
+	parsed, err := Parse(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ParsePSBT(parsed)
+	if err != nil || !bytes.Equal(got, psbt) {
+		t.Errorf("ParsePSBT() = %x, %v", got, err)
+	}
+
+	if _, err := NewPSBT([]byte("not a psbt")); !errors.Is(err, ErrNotPSBT) {
+		t.Errorf("NewPSBT(garbage) error = %v, want ErrNotPSBT", err)
+	}
+	if _, err := ParsePSBT(NewBytes(psbt)); !errors.Is(err, ErrUnexpectedType) {
+		t.Errorf("ParsePSBT(bytes UR) error = %v, want ErrUnexpectedType", err)
+	}
+}
+
+func TestHDKey_RoundTrip(t *testing.T) {
+	// BIP32 test vector 1, chain m/0H
+	const xpub = "xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBFA1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2dZXvgGDnw"
+	key, err := hdkeychain.NewKeyFromString(xpub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	origin := &Keypath{Path: accounts.DerivationPath{hdkeychain.HardenedKeyStart}, SourceFingerprint: 0x3442193e}
+	hd, err := HDKeyFromExtended(key, origin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hd.Name = "test"
+	if hd.IsMaster || hd.Testnet || hd.ParentFingerprint != 0x3442193e {
+		t.Errorf("HDKeyFromExtended() = %+v", hd)
+	}
+
+	parsed, err := ParseHDKey(hd.UR())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed, hd) {
+		t.Errorf("ParseHDKey() = %+v, want %+v", parsed, hd)
+	}
+	if parsed.Origin.String() != "3442193e/0'" {
+		t.Errorf("origin = %s", parsed.Origin)
+	}
+	extended, err := parsed.Extended()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if extended.String() != xpub {
+		t.Errorf("Extended() = %s, want %s", extended, xpub)
+	}
+}
+
+func TestParseHDKey_Private(t *testing.T) {
+	var w cborWriter
+	w.mapHeader(3)
+	w.uint(2)
+	w.bool(true)
+	w.uint(3)
+	w.bytes(make([]byte, 33))
+	w.uint(4)
+	w.bytes(make([]byte, 32))
+	if _, err := ParseHDKey(UR{Type: TypeHDKey, CBOR: w.buf}); !errors.Is(err, ErrPrivateHDKey) {
+		t.Errorf("error = %v, want ErrPrivateHDKey", err)
+	}
+}
+
+func TestEthSignRequest_RoundTrip(t *testing.T) {
+	path, _ := accounts.ParseDerivationPath("m/44'/60'/0'/0/0")
+	r := EthSignRequest{
+		RequestID: bytes.Repeat([]byte{0xab}, 16),
+		SignData:  []byte("hello"),
+		DataType:  EthPersonalMessage,
+		ChainID:   1,
+		Path:      Keypath{Path: path, SourceFingerprint: 0x12345678},
+		Address:   bytes.Repeat([]byte{0x11}, 20),
+		Origin:    "gowallet",
+	}
+	got, err := ParseEthSignRequest(r.UR())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, r) {
+		t.Errorf("ParseEthSignRequest() = %+v, want %+v", got, r)
+	}
+
+	// Optional fields are omitted rather than written as zero values
+	minimal := EthSignRequest{SignData: []byte{1}, DataType: EthTransaction, Path: Keypath{Path: path}}
+	if got, err = ParseEthSignRequest(minimal.UR()); err != nil || !reflect.DeepEqual(got, minimal) {
+		t.Errorf("ParseEthSignRequest(minimal) = %+v, %v", got, err)
+	}
+}
+
+func TestEthSignature_RoundTrip(t *testing.T) {
+	s := EthSignature{RequestID: bytes.Repeat([]byte{1}, 16), Signature: bytes.Repeat([]byte{2}, 65), Origin: "keystone"}
+	got, err := ParseEthSignature(s.UR())
+	if err != nil || !reflect.DeepEqual(got, s) {
+		t.Errorf("ParseEthSignature() = %+v, %v", got, err)
+	}
+
+	bad := s
+	bad.RequestID = []byte{1, 2, 3}
+	if _, err := ParseEthSignature(bad.UR()); !errors.Is(err, ErrInvalidCBOR) {
+		t.Errorf("short request id: error = %v, want ErrInvalidCBOR", err)
+	}
+}
@@ -0,0 +1,204 @@
+// Package ur implements Blockchain Commons Uniform Resources (BCR-2020-005):
+// CBOR payloads written as bytewords, split into fountain-coded frames for
+// animated QR codes, as exchanged with Keystone, Sparrow and other
+// air-gapped wallets.
+package ur
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrInvalidUR indicates text that is not a well-formed UR
+	ErrInvalidUR = errors.New("invalid UR")
+	// ErrUnexpectedType indicates a UR of a different type than requested
+	ErrUnexpectedType = errors.New("unexpected UR type")
+)
+
+const scheme = "ur:"
+
+// UR is a typed CBOR payload
+type UR struct {
+	Type string
+	CBOR []byte
+}
+
+func validType(t string) bool {
+	if t == "" {
+		return false
+	}
+	for _, c := range t {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// String encodes the whole UR as a single part
+func (u UR) String() string {
+	return scheme + u.Type + "/" + EncodeBytewords(u.CBOR, Minimal)
+}
+
+// Parse decodes a single-part UR
+func Parse(s string) (UR, error) {
+	typ, components, err := splitUR(s)
+	if err != nil {
+		return UR{}, err
+	}
+	if len(components) != 1 {
+		return UR{}, fmt.Errorf("%w: multi-part UR needs a decoder", ErrInvalidUR)
+	}
+	payload, err := DecodeBytewords(components[0], Minimal)
+	if err != nil {
+		return UR{}, fmt.Errorf("%w: %w", ErrInvalidUR, err)
+	}
+	return UR{Type: typ, CBOR: payload}, nil
+}
+
+// splitUR lowercases s (QR codes carry URs in upper case for alphanumeric
+// mode) and returns its type and path components
+func splitUR(s string) (string, []string, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if !strings.HasPrefix(s, scheme) {
+		return "", nil, fmt.Errorf("%w: missing %q scheme", ErrInvalidUR, scheme)
+	}
+	parts := strings.Split(s[len(scheme):], "/")
+	if len(parts) < 2 || len(parts) > 3 || !validType(parts[0]) {
+		return "", nil, fmt.Errorf("%w: expected ur:<type>/[<seq>/]<payload>", ErrInvalidUR)
+	}
+	return parts[0], parts[1:], nil
+}
+
+// Encoder emits the frames of a UR. A UR that fits in one fragment is a
+// single frame; larger ones are fountain-coded, and frames past SeqLen
+// let a scanner recover from missed frames.
+type Encoder struct {
+	ur       UR
+	fountain *FountainEncoder
+}
+
+// NewEncoder splits u into fragments of at most maxFragmentLen bytes
+func NewEncoder(u UR, maxFragmentLen int) (*Encoder, error) {
+	if !validType(u.Type) {
+		return nil, fmt.Errorf("%w: bad type %q", ErrInvalidUR, u.Type)
+	}
+	fountain, err := NewFountainEncoder(u.CBOR, maxFragmentLen)
+	if err != nil {
+		return nil, err
+	}
+	return &Encoder{ur: u, fountain: fountain}, nil
+}
+
+// SinglePart reports whether the UR fits in one frame
+func (e *Encoder) SinglePart() bool {
+	return e.fountain.SeqLen() == 1
+}
+
+// SeqLen is the number of frames needed to decode without losses
+func (e *Encoder) SeqLen() int {
+	return e.fountain.SeqLen()
+}
+
+// NextPart returns the next frame
+func (e *Encoder) NextPart() string {
+	if e.SinglePart() {
+		return e.ur.String()
+	}
+	part := e.fountain.NextPart()
+	return scheme + e.ur.Type + "/" + part.seqID() + "/" + EncodeBytewords(part.CBOR(), Minimal)
+}
+
+// Decoder collects single- or multi-part frames until the UR is complete
+type Decoder struct {
+	typ      string
+	fountain *FountainDecoder
+	result   *UR
+}
+
+// NewDecoder returns an empty decoder
+func NewDecoder() *Decoder {
+	return &Decoder{fountain: NewFountainDecoder()}
+}
+
+// Receive adds one frame. Frames of another type or message are rejected
+// without disturbing the frames already received.
+func (d *Decoder) Receive(frame string) error {
+	if d.Complete() {
+		return nil
+	}
+	typ, components, err := splitUR(frame)
+	if err != nil {
+		return err
+	}
+	if d.typ != "" && typ != d.typ {
+		return fmt.Errorf("%w: got %s while decoding %s", ErrUnexpectedType, typ, d.typ)
+	}
+
+	if len(components) == 1 {
+		payload, err := DecodeBytewords(components[0], Minimal)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidUR, err)
+		}
+		d.typ = typ
+		d.result = &UR{Type: typ, CBOR: payload}
+		return nil
+	}
+
+	seqNum, seqLen, err := parseSeqID(components[0])
+	if err != nil {
+		return err
+	}
+	payload, err := DecodeBytewords(components[1], Minimal)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidUR, err)
+	}
+	part, err := parsePart(payload)
+	if err != nil {
+		return err
+	}
+	if part.SeqNum != seqNum || part.SeqLen != seqLen {
+		return fmt.Errorf("%w: sequence %s does not match its payload", ErrInvalidPart, components[0])
+	}
+	if err := d.fountain.ReceivePart(part); err != nil {
+		return err
+	}
+	d.typ = typ
+	if d.fountain.Complete() {
+		message, err := d.fountain.Message()
+		if err != nil {
+			return err
+		}
+		d.result = &UR{Type: typ, CBOR: message}
+	}
+	return nil
+}
+
+// Complete reports whether the UR has been reassembled
+func (d *Decoder) Complete() bool {
+	return d.result != nil
+}
+
+// Result returns the reassembled UR once Complete
+func (d *Decoder) Result() (UR, error) {
+	if d.result == nil {
+		return UR{}, errors.New("UR is incomplete")
+	}
+	return *d.result, nil
+}
+
+// Progress estimates completion between 0 and 1
+func (d *Decoder) Progress() float64 {
+	if d.Complete() {
+		return 1
+	}
+	return d.fountain.Progress()
+}
+
+// ExpectedParts is the number of fragments of a multi-part UR, or 0 before
+// its first frame
+func (d *Decoder) ExpectedParts() int {
+	return d.fountain.ExpectedParts()
+}
@@ -0,0 +1,115 @@
+package ur
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEncoder_Vectors(t *testing.T) {
+	// From the Blockchain Commons reference implementation: a 256 byte
+	// message in 30 byte fragments
+	e, err := NewEncoder(NewBytes(makeMessage(256)), 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[int]string{
+		1:  "ur:bytes/1-9/lpadascfadaxcywenbpljkhdcahkadaemejtswhhylkepmykhhtsytsnoyoyaxaedsuttydmmhhpktpmsrjtdkgslpgh",
+		10: "ur:bytes/10-9/lpbkascfadaxcywenbpljkhdcahkadaemejtswhhylkepmykhhtsytsnoyoyaxaedsuttydmmhhpktpmsrjtwdkiplzs",
+		11: "ur:bytes/11-9/lpbdascfadaxcywenbpljkhdcahelbknlkuejnbadmssfhfrdpsbiegecpasvssovlgeykssjykklronvsjkvetiiapk",
+	}
+	if e.SinglePart() || e.SeqLen() != 9 {
+		t.Fatalf("SeqLen() = %d", e.SeqLen())
+	}
+	for i := 1; i <= 11; i++ {
+		part := e.NextPart()
+		if w, ok := want[i]; ok && part != w {
+			t.Errorf("part %d = %s, want %s", i, part, w)
+		}
+	}
+}
+
+func TestEncoder_SinglePart(t *testing.T) {
+	u := NewBytes([]byte("short"))
+	e, err := NewEncoder(u, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !e.SinglePart() {
+		t.Fatal("SinglePart() = false")
+	}
+	if got := e.NextPart(); got != u.String() || strings.Count(got, "/") != 1 {
+		t.Errorf("NextPart() = %s, want %s", got, u)
+	}
+	parsed, err := Parse(strings.ToUpper(u.String()))
+	if err != nil || parsed.Type != u.Type || !bytes.Equal(parsed.CBOR, u.CBOR) {
+		t.Errorf("Parse() = %+v, %v", parsed, err)
+	}
+}
+
+func TestDecoder_RoundTrip(t *testing.T) {
+	message := makeMessage(5000)
+	e, err := NewEncoder(NewBytes(message), 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := NewDecoder()
+	// Skip every third frame and feed the rest in QR alphanumeric case
+	for i := 0; !d.Complete(); i++ {
+		frame := e.NextPart()
+		if i%3 == 1 {
+			continue
+		}
+		if err := d.Receive(strings.ToUpper(frame)); err != nil {
+			t.Fatal(err)
+		}
+		if i > 500 {
+			t.Fatal("decoder did not complete")
+		}
+	}
+	if d.Progress() != 1 {
+		t.Errorf("Progress() = %v", d.Progress())
+	}
+	result, err := d.Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ParseBytes(result)
+	if err != nil || !bytes.Equal(got, message) {
+		t.Fatalf("ParseBytes() = %d bytes, %v", len(got), err)
+	}
+}
+
+func TestDecoder_Invalid(t *testing.T) {
+	e, _ := NewEncoder(NewBytes(makeMessage(100)), 30)
+	first := e.NextPart()
+
+	tests := []struct {
+		name  string
+		frame string
+		want  error
+	}{
+		{"no scheme", "bytes/aeadaolazmjendeoti", ErrInvalidUR},
+		{"no payload", "ur:bytes", ErrInvalidUR},
+		{"bad type", "ur:by_tes/aeadaolazmjendeoti", ErrInvalidUR},
+		{"bad sequence", "ur:bytes/1of4/aeadaolazmjendeoti", ErrInvalidPart},
+		{"bad checksum", first[:len(first)-1] + "a", ErrInvalidUR},
+		{"sequence mismatch", strings.Replace(first, "/1-", "/2-", 1), ErrInvalidPart},
+		{"other type", strings.Replace(first, "ur:bytes", "ur:crypto-psbt", 1), ErrUnexpectedType},
+	}
+	d := NewDecoder()
+	if err := d.Receive(first); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := d.Receive(tt.frame); !errors.Is(err, tt.want) {
+				t.Errorf("Receive() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+	if d.Complete() || d.ExpectedParts() != 4 {
+		t.Errorf("Complete() = %v, ExpectedParts() = %d", d.Complete(), d.ExpectedParts())
+	}
+}
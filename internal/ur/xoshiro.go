@@ -0,0 +1,60 @@
+package ur
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+	"math/bits"
+)
+
+// xoshiro256 is the xoshiro256** generator used by the fountain code to
+// pick fragments. It is seeded from the SHA-256 of a byte string, so the
+// encoder and decoder derive the same choices from the part header.
+type xoshiro256 struct {
+	s [4]uint64
+}
+
+func newXoshiro256(seed []byte) *xoshiro256 {
+	digest := sha256.Sum256(seed)
+	var x xoshiro256
+	for i := range x.s {
+		x.s[i] = binary.BigEndian.Uint64(digest[i*8:])
+	}
+	return &x
+}
+
+func (x *xoshiro256) next() uint64 {
+	s := &x.s
+	result := bits.RotateLeft64(s[1]*5, 7) * 9
+	t := s[1] << 17
+	s[2] ^= s[0]
+	s[3] ^= s[1]
+	s[1] ^= s[2]
+	s[0] ^= s[3]
+	s[2] ^= t
+	s[3] = bits.RotateLeft64(s[3], 45)
+	return result
+}
+
+// nextDouble returns a value in [0, 1)
+func (x *xoshiro256) nextDouble() float64 {
+	return float64(x.next()) / (float64(math.MaxUint64) + 1)
+}
+
+// nextInt returns a value in [low, high]
+func (x *xoshiro256) nextInt(low, high int) int {
+	return int(x.nextDouble()*float64(high-low+1)) + low
+}
+
+func (x *xoshiro256) nextByte() byte {
+	return byte(x.nextInt(0, 255))
+}
+
+// nextData returns count pseudo-random bytes
+func (x *xoshiro256) nextData(count int) []byte {
+	out := make([]byte, count)
+	for i := range out {
+		out[i] = x.nextByte()
+	}
+	return out
+}
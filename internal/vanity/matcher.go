@@ -0,0 +1,141 @@
+package vanity
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	// ErrNoPattern indicates no prefix, suffix or regex was provided
+	ErrNoPattern = errors.New("at least one of prefix, suffix or regex is required")
+	// ErrInvalidPattern indicates the prefix or suffix contains non-hex characters
+	ErrInvalidPattern = errors.New("pattern must contain only hex characters")
+	// ErrPatternTooLong indicates prefix and suffix together exceed the address length
+	ErrPatternTooLong = errors.New("pattern is longer than an address")
+)
+
+const addressHexLen = 40
+
+// Matcher decides whether an Ethereum address matches the requested pattern
+//
+// Prefix and suffix are compared against the 40 hex characters of the
+// address (without 0x). When CaseSensitive is set the comparison uses the
+// EIP-55 checksummed form, otherwise everything is lowercased. The regex,
+// if any, is applied to the same form.
+type Matcher struct {
+	Prefix        string
+	Suffix        string
+	Regex         *regexp.Regexp
+	CaseSensitive bool
+}
+
+// NewMatcher validates the pattern and builds a Matcher
+func NewMatcher(prefix, suffix, pattern string, caseSensitive bool) (*Matcher, error) {
+	prefix = strings.TrimPrefix(prefix, "0x")
+	if prefix == "" && suffix == "" && pattern == "" {
+		return nil, ErrNoPattern
+	}
+	for _, s := range []string{prefix, suffix} {
+		if !isHex(s) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidPattern, s)
+		}
+	}
+	if len(prefix)+len(suffix) > addressHexLen {
+		return nil, ErrPatternTooLong
+	}
+
+	m := &Matcher{CaseSensitive: caseSensitive}
+	if caseSensitive {
+		m.Prefix, m.Suffix = prefix, suffix
+	} else {
+		m.Prefix, m.Suffix = strings.ToLower(prefix), strings.ToLower(suffix)
+	}
+
+	if pattern != "" {
+		if !caseSensitive && !strings.HasPrefix(pattern, "(?i)") {
+			pattern = "(?i)" + pattern
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex: %w", err)
+		}
+		m.Regex = re
+	}
+
+	return m, nil
+}
+
+// Match reports whether address satisfies the pattern
+func (m *Matcher) Match(address common.Address) bool {
+	return m.MatchHex(m.form(address))
+}
+
+// MatchHex matches an already formatted 40 character address string
+func (m *Matcher) MatchHex(s string) bool {
+	if !strings.HasPrefix(s, m.Prefix) || !strings.HasSuffix(s, m.Suffix) {
+		return false
+	}
+	if m.Regex != nil && !m.Regex.MatchString(s) {
+		return false
+	}
+	return true
+}
+
+func (m *Matcher) form(address common.Address) string {
+	if m.CaseSensitive {
+		return address.Hex()[2:]
+	}
+	return common.Bytes2Hex(address.Bytes())
+}
+
+// Difficulty returns the expected number of attempts to find a match
+//
+// Each hex character constrains 4 bits; with case-sensitive matching every
+// letter additionally has a 1/2 chance of having the requested case.
+// Regex patterns cannot be estimated and contribute nothing, so the value
+// is a lower bound when a regex is used.
+func (m *Matcher) Difficulty() float64 {
+	d := 1.0
+	for _, s := range []string{m.Prefix, m.Suffix} {
+		for _, c := range s {
+			d *= 16
+			if m.CaseSensitive && isHexLetter(c) {
+				d *= 2
+			}
+		}
+	}
+	return d
+}
+
+// Probability returns the chance that a given attempt succeeds
+func (m *Matcher) Probability() float64 {
+	return 1 / m.Difficulty()
+}
+
+// AttemptsForProbability returns how many attempts are needed to find a
+// match with probability p (e.g. 0.5 for the median)
+func (m *Matcher) AttemptsForProbability(p float64) float64 {
+	q := m.Probability()
+	if q >= 1 {
+		return 1
+	}
+	return math.Log(1-p) / math.Log(1-q)
+}
+
+func isHex(s string) bool {
+	for _, c := range s {
+		if !(c >= '0' && c <= '9') && !isHexLetter(c) {
+			return false
+		}
+	}
+	return true
+}
+
+func isHexLetter(c rune) bool {
+	return (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
@@ -0,0 +1,115 @@
+package vanity
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestNewMatcher(t *testing.T) {
+	tests := []struct {
+		name    string
+		prefix  string
+		suffix  string
+		regex   string
+		wantErr error
+	}{
+		{name: "prefix only", prefix: "dead"},
+		{name: "0x prefix is stripped", prefix: "0xbeef"},
+		{name: "suffix only", suffix: "cafe"},
+		{name: "regex only", regex: "^0{4}"},
+		{name: "no pattern", wantErr: ErrNoPattern},
+		{name: "non hex prefix", prefix: "xyz", wantErr: ErrInvalidPattern},
+		{name: "non hex suffix", suffix: "g0", wantErr: ErrInvalidPattern},
+		{name: "too long", prefix: "0000000000000000000000000000000000000000", suffix: "1", wantErr: ErrPatternTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewMatcher(tt.prefix, tt.suffix, tt.regex, false)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("NewMatcher() unexpected error: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewMatcher() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := NewMatcher("", "", "([", false); err == nil {
+		t.Error("NewMatcher() expected error for invalid regex")
+	}
+}
+
+func TestMatcherMatch(t *testing.T) {
+	// EIP-55 checksummed: 0xC49926C4124cEe1cbA0Ea94Ea31a6c12318df947
+	address := common.HexToAddress("0xc49926c4124cee1cba0ea94ea31a6c12318df947")
+
+	tests := []struct {
+		name          string
+		prefix        string
+		suffix        string
+		regex         string
+		caseSensitive bool
+		want          bool
+	}{
+		{"prefix match", "c499", "", "", false, true},
+		{"prefix upper case insensitive", "C499", "", "", false, true},
+		{"prefix mismatch", "c498", "", "", false, false},
+		{"suffix match", "", "f947", "", false, true},
+		{"prefix and suffix", "c4", "47", "", false, true},
+		{"case sensitive match", "C4", "", "", true, true},
+		{"case sensitive mismatch", "c4", "", "", true, false},
+		{"case sensitive suffix", "", "df947", "", true, true},
+		{"regex match", "", "", "a6c1+2", false, true},
+		{"regex mismatch", "", "", "^0000", false, false},
+		{"regex case sensitive", "", "", "cEe1cbA", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMatcher(tt.prefix, tt.suffix, tt.regex, tt.caseSensitive)
+			if err != nil {
+				t.Fatalf("NewMatcher() error: %v", err)
+			}
+			if got := m.Match(address); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatcherDifficulty(t *testing.T) {
+	tests := []struct {
+		name          string
+		prefix        string
+		suffix        string
+		caseSensitive bool
+		want          float64
+	}{
+		{"one char", "a", "", false, 16},
+		{"two chars", "ab", "", false, 256},
+		{"prefix and suffix", "1", "2", false, 256},
+		{"case sensitive digits", "12", "", true, 256},
+		{"case sensitive letters", "Ab", "", true, 1024},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMatcher(tt.prefix, tt.suffix, "", tt.caseSensitive)
+			if err != nil {
+				t.Fatalf("NewMatcher() error: %v", err)
+			}
+			if got := m.Difficulty(); got != tt.want {
+				t.Errorf("Difficulty() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	m, _ := NewMatcher("a", "", "", false)
+	median := m.AttemptsForProbability(0.5)
+	if median < 10 || median > 12 {
+		t.Errorf("AttemptsForProbability(0.5) = %v, want ~10.7", median)
+	}
+}
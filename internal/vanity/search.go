@@ -0,0 +1,178 @@
+package vanity
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spark8899/gowallet/internal/commonPrivateKey"
+	"github.com/spark8899/gowallet/internal/security"
+)
+
+// ErrCancelled indicates the search was stopped before a match was found
+var ErrCancelled = errors.New("search cancelled")
+
+// Stats describes the progress of a search
+type Stats struct {
+	Attempts uint64
+	Elapsed  time.Duration
+}
+
+// Rate returns attempts per second
+func (s Stats) Rate() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Attempts) / s.Elapsed.Seconds()
+}
+
+// Expected returns the expected total duration for difficulty at the current rate
+func (s Stats) Expected(difficulty float64) time.Duration {
+	rate := s.Rate()
+	if rate == 0 {
+		return 0
+	}
+	return time.Duration(difficulty / rate * float64(time.Second))
+}
+
+// Config controls the worker pool of a search
+type Config struct {
+	// Workers is the number of goroutines, defaulting to GOMAXPROCS
+	Workers int
+	// Progress, if set, is called every Interval with the current stats
+	Progress func(Stats)
+	// Interval between Progress calls, defaulting to one second
+	Interval time.Duration
+}
+
+// Run calls attempt concurrently from a pool of workers until one of them
+// reports a hit, returns an error, or ctx is cancelled.
+//
+// attempt must be safe for concurrent use. Workers that hit at nearly the
+// same time as the one whose result is returned pass theirs to discard,
+// if it is not nil, so it can wipe any secrets they hold.
+func Run[T any](ctx context.Context, cfg Config, attempt func() (T, bool, error), discard func(T)) (T, Stats, error) {
+	var zero T
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	interval := cfg.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		attempts atomic.Uint64
+		once     sync.Once
+		result   T
+		found    bool
+		runErr   error
+		wg       sync.WaitGroup
+	)
+	start := time.Now()
+	stats := func() Stats {
+		return Stats{Attempts: attempts.Load(), Elapsed: time.Since(start)}
+	}
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				v, ok, err := attempt()
+				attempts.Add(1)
+				if err != nil || ok {
+					kept := false
+					once.Do(func() {
+						result, found, runErr = v, ok, err
+						kept = true
+						cancel()
+					})
+					if !kept && ok && discard != nil {
+						discard(v)
+					}
+					return
+				}
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	if cfg.Progress != nil {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+	loop:
+		for {
+			select {
+			case <-ticker.C:
+				cfg.Progress(stats())
+			case <-done:
+				break loop
+			}
+		}
+	} else {
+		<-done
+	}
+
+	if runErr != nil {
+		return zero, stats(), runErr
+	}
+	if !found {
+		return zero, stats(), ErrCancelled
+	}
+	return result, stats(), nil
+}
+
+// Result is a matching key pair
+type Result struct {
+	Address    common.Address
+	PrivateKey *ecdsa.PrivateKey
+}
+
+// zeroPrivateKey wipes a key that is not returned, and generateKey makes
+// the candidates. They are variables so tests can make workers hit at once
+// and check that no generated key is left behind.
+var (
+	zeroPrivateKey = security.ZeroPrivateKey
+	generateKey    = crypto.GenerateKey
+)
+
+// Search generates random keys until one produces an address accepted by m.
+// Every hit is checked with commonPrivateKey.ValidatePrivateKey; keys that
+// fail validation are discarded and the search continues. Every key except
+// the returned one is wiped, including hits that lose the race to it.
+func Search(ctx context.Context, m *Matcher, cfg Config) (*Result, Stats, error) {
+	return Run(ctx, cfg, func() (*Result, bool, error) {
+		privateKey, err := generateKey()
+		if err != nil {
+			return nil, false, err
+		}
+		address := crypto.PubkeyToAddress(privateKey.PublicKey)
+		if !m.Match(address) {
+			zeroPrivateKey(privateKey)
+			return nil, false, nil
+		}
+		if err := commonPrivateKey.ValidatePrivateKey(privateKey); err != nil {
+			zeroPrivateKey(privateKey)
+			return nil, false, nil
+		}
+		return &Result{Address: address, PrivateKey: privateKey}, true, nil
+	}, func(r *Result) {
+		zeroPrivateKey(r.PrivateKey)
+	})
+}
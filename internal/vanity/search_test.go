@@ -0,0 +1,186 @@
+package vanity
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spark8899/gowallet/internal/commonPrivateKey"
+)
+
+func TestSearch(t *testing.T) {
+	m, err := NewMatcher("a", "", "", false)
+	if err != nil {
+		t.Fatalf("NewMatcher() error: %v", err)
+	}
+
+	result, stats, err := Search(context.Background(), m, Config{Workers: 4})
+	if err != nil {
+		t.Fatalf("Search() error: %v", err)
+	}
+	if !strings.HasPrefix(strings.ToLower(result.Address.Hex()), "0xa") {
+		t.Errorf("Search() address %s does not match prefix", result.Address.Hex())
+	}
+	if crypto.PubkeyToAddress(result.PrivateKey.PublicKey) != result.Address {
+		t.Error("Search() private key does not belong to address")
+	}
+	if err := commonPrivateKey.ValidatePrivateKey(result.PrivateKey); err != nil {
+		t.Errorf("Search() returned invalid key: %v", err)
+	}
+	if stats.Attempts == 0 {
+		t.Error("Search() reported zero attempts")
+	}
+}
+
+func TestSearchCaseSensitive(t *testing.T) {
+	m, err := NewMatcher("", "E", "", true)
+	if err != nil {
+		t.Fatalf("NewMatcher() error: %v", err)
+	}
+
+	result, _, err := Search(context.Background(), m, Config{})
+	if err != nil {
+		t.Fatalf("Search() error: %v", err)
+	}
+	if !strings.HasSuffix(result.Address.Hex(), "E") {
+		t.Errorf("Search() address %s does not end with upper case E", result.Address.Hex())
+	}
+}
+
+func TestSearchCancel(t *testing.T) {
+	// Practically impossible pattern
+	m, err := NewMatcher("0000000000000000", "", "", false)
+	if err != nil {
+		t.Fatalf("NewMatcher() error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	var progressCalls atomic.Int32
+	_, stats, err := Search(ctx, m, Config{
+		Workers:  2,
+		Interval: 10 * time.Millisecond,
+		Progress: func(Stats) { progressCalls.Add(1) },
+	})
+	if !errors.Is(err, ErrCancelled) {
+		t.Fatalf("Search() error = %v, want %v", err, ErrCancelled)
+	}
+	if stats.Attempts == 0 || stats.Rate() <= 0 {
+		t.Errorf("Search() stats = %+v, want positive attempts and rate", stats)
+	}
+	if progressCalls.Load() == 0 {
+		t.Error("Progress was never called")
+	}
+}
+
+func TestRunError(t *testing.T) {
+	wantErr := errors.New("boom")
+	_, _, err := Run(context.Background(), Config{Workers: 3}, func() (int, bool, error) {
+		return 0, false, wantErr
+	}, nil)
+	if !errors.Is(err, wantErr) {
+		t.Errorf("Run() error = %v, want %v", err, wantErr)
+	}
+}
+
+func TestRun_Discard(t *testing.T) {
+	// Every worker hits at once, so all but the returned result are discarded
+	const workers = 4
+	var ready sync.WaitGroup
+	ready.Add(workers)
+	var next atomic.Int32
+	var mu sync.Mutex
+	var discarded []int32
+	got, _, err := Run(context.Background(), Config{Workers: workers}, func() (int32, bool, error) {
+		v := next.Add(1)
+		ready.Done()
+		ready.Wait()
+		return v, true, nil
+	}, func(v int32) {
+		mu.Lock()
+		discarded = append(discarded, v)
+		mu.Unlock()
+	})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if len(discarded) != workers-1 || slices.Contains(discarded, got) {
+		t.Errorf("Run() returned %d and discarded %v, want the other %d hits", got, discarded, workers-1)
+	}
+}
+
+func TestSearch_WipesLosingHits(t *testing.T) {
+	const workers = 8
+	var mu sync.Mutex
+	var wiped []*ecdsa.PrivateKey
+	origZero, origGenerate := zeroPrivateKey, generateKey
+	zeroPrivateKey = func(k *ecdsa.PrivateKey) {
+		mu.Lock()
+		wiped = append(wiped, k)
+		mu.Unlock()
+		origZero(k)
+	}
+	// Every worker generates its key before any of them may return one
+	var ready sync.WaitGroup
+	ready.Add(workers)
+	generateKey = func() (*ecdsa.PrivateKey, error) {
+		ready.Done()
+		ready.Wait()
+		return origGenerate()
+	}
+	t.Cleanup(func() { zeroPrivateKey, generateKey = origZero, origGenerate })
+
+	// Every key matches, so all workers hit and all but one lose the race
+	m, err := NewMatcher("", "", ".", false)
+	if err != nil {
+		t.Fatalf("NewMatcher() error: %v", err)
+	}
+	result, stats, err := Search(context.Background(), m, Config{Workers: workers})
+	if err != nil {
+		t.Fatalf("Search() error: %v", err)
+	}
+
+	if stats.Attempts != workers || len(wiped) != workers-1 {
+		t.Fatalf("wiped %d of %d keys, want all %d but the result", len(wiped), stats.Attempts, workers)
+	}
+	for i, k := range wiped {
+		if k == result.PrivateKey {
+			t.Fatal("Search() wiped the key it returned")
+		}
+		if k.D.Sign() != 0 {
+			t.Errorf("key %d not wiped", i)
+		}
+	}
+	if crypto.PubkeyToAddress(result.PrivateKey.PublicKey) != result.Address {
+		t.Error("Search() private key does not belong to address")
+	}
+}
+
+func TestStatsExpected(t *testing.T) {
+	s := Stats{Attempts: 1000, Elapsed: time.Second}
+	if s.Rate() != 1000 {
+		t.Errorf("Rate() = %v, want 1000", s.Rate())
+	}
+	if got := s.Expected(10000); got != 10*time.Second {
+		t.Errorf("Expected() = %v, want 10s", got)
+	}
+	if (Stats{}).Expected(100) != 0 {
+		t.Error("Expected() with zero rate should be 0")
+	}
+}
+
+func BenchmarkSearchAttempt(b *testing.B) {
+	m, _ := NewMatcher("0000000000", "", "", false)
+	for i := 0; i < b.N; i++ {
+		key, _ := crypto.GenerateKey()
+		m.Match(crypto.PubkeyToAddress(key.PublicKey))
+	}
+}